@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"task/internal/cli"
+	"task/internal/dto"
+	"task/internal/services"
+)
+
+// runImport implements "import" subcommand: it loads routes from CSV or JSONL file
+// and prints import report as JSON.
+func runImport(args []string) (err error) {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	connStrFlag := fs.String("b", "", "Database connection string")
+	formatFlag := fs.String("format", "", "File format: csv or jsonl (by default guessed from file extension)")
+	speedConfig := cli.SpeedFlags(fs)
+	loadPrecision := cli.LoadPrecisionFlag(fs)
+	storage := cli.StorageFlag(fs)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s import [flags] <file|->\n", os.Args[0])
+		fs.PrintDefaults()
+	}
+	_ = fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return fmt.Errorf("import: expected exactly one file argument")
+	}
+	path := fs.Arg(0)
+
+	connStr := os.Getenv("CONNECTION_STRING")
+	if connStr == "" {
+		connStr = *connStrFlag
+	}
+	if connStr == "" {
+		return fmt.Errorf(`set env variable CONNECTION_STRING or use "-b" flag`)
+	}
+
+	speeds, err := speedConfig()
+	if err != nil {
+		return err
+	}
+
+	precision, err := loadPrecision()
+	if err != nil {
+		return err
+	}
+
+	storageName, err := storage()
+	if err != nil {
+		return err
+	}
+
+	format := *formatFlag
+	if format == "" {
+		format = dto.FormatFromFileName(path)
+	}
+
+	var r io.Reader = os.Stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return fmt.Errorf("opening import file: %w", err)
+		}
+		defer f.Close()
+		r = f
+	}
+
+	ctx := context.Background()
+
+	a, closeDb, err := cli.OpenApp(ctx, storageName, connStr, false, cli.PoolConfig{}, services.WithSpeeds(speeds), services.WithLoadPrecision(precision))
+	if errors.Is(err, cli.ErrNoSchema) {
+		return fmt.Errorf(`%w; run "migrate up" subcommand before importing routes`, err)
+	}
+	if err != nil {
+		return err
+	}
+	defer closeDb()
+
+	report, err := a.Svc.Import(ctx, r, format)
+	if err != nil {
+		return fmt.Errorf("import: %w", err)
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(dto.FromImportReport(report))
+}
@@ -2,60 +2,50 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/cors"
-	"github.com/jackc/pgx/v5"
 	"log"
 	"net/http"
 	"os"
-	"task/internal/app"
+	"os/signal"
+	"syscall"
+	"task/internal/cli"
 	"task/internal/delivery"
+	"task/internal/services"
+	"time"
 )
 
-type config struct {
-	srvAddr string
-	connStr string
-}
-
-func checkVersion(db *pgx.Conn) (ok bool, err error) {
-	query := `
-		select count(1)
-		from information_schema.tables 
-		where table_name = 'routes'
-		limit 1`
-
-	var val int
-	err = db.QueryRow(context.Background(), query).Scan(&val)
-	if err != nil {
-		return false, fmt.Errorf("check table: %w", err)
-	}
-	if val == 1 {
-		ok = true
-	}
-
-	return ok, nil
-}
-
-func newConn(ctx context.Context, connStr string) (db *pgx.Conn, err error) {
-	db, err = pgx.Connect(ctx, connStr)
-	if err != nil {
-		return nil, fmt.Errorf("database connecting: %w", err)
-	}
+const defaultDrainTimeout = time.Second * 30
 
-	err = db.Ping(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("database ping: %w", err)
-	}
-
-	return db, nil
+type config struct {
+	srvAddr      string
+	connStr      string
+	storage      string
+	autoMigrate  bool
+	drainTimeout time.Duration
+	pool         cli.PoolConfig
+	speeds       services.SpeedConfig
+	// loadPrecision is number of fractional digits of stored loads
+	loadPrecision int32
 }
 
 func parseVariables() (cfg config, err error) {
 	srvAddressFlag := flag.String("a", "", "Server address")
 	connStrFlag := flag.String("b", "", "Database connection string")
+	maxConnsFlag := flag.Int("max-conns", 0, "Maximum size of database connection pool")
+	minConnsFlag := flag.Int("min-conns", 0, "Minimum size of database connection pool")
+	maxConnLifetimeFlag := flag.Duration("max-conn-lifetime", 0, "Maximum lifetime of database connection")
+	maxConnIdleTimeFlag := flag.Duration("max-conn-idle-time", 0, "Maximum idle time of database connection")
+	healthCheckPeriodFlag := flag.Duration("health-check-period", 0, "Period of idle database connections health check")
+	drainTimeoutFlag := flag.Duration("drain-timeout", defaultDrainTimeout, "Time to wait for in-flight requests and background work on shutdown")
+	speedConfig := cli.SpeedFlags(flag.CommandLine)
+	loadPrecision := cli.LoadPrecisionFlag(flag.CommandLine)
+	storage := cli.StorageFlag(flag.CommandLine)
+	autoMigrateFlag := flag.Bool("auto-migrate", false, "Apply pending migrations on startup")
 	flag.Parse()
 
 	srvAddr := os.Getenv("SERVER_ADDRESS")
@@ -75,33 +65,116 @@ func parseVariables() (cfg config, err error) {
 		return config{}, fmt.Errorf(`set env variable CONNECTION_STRING or use "-b" flag`)
 	}
 
+	pool := cli.PoolConfig{
+		MaxConns:          *maxConnsFlag,
+		MinConns:          *minConnsFlag,
+		MaxConnLifetime:   *maxConnLifetimeFlag,
+		MaxConnIdleTime:   *maxConnIdleTimeFlag,
+		HealthCheckPeriod: *healthCheckPeriodFlag,
+	}
+
+	if pool.MaxConns, err = cli.IntEnv("DB_MAX_CONNS", pool.MaxConns); err != nil {
+		return config{}, err
+	}
+	if pool.MinConns, err = cli.IntEnv("DB_MIN_CONNS", pool.MinConns); err != nil {
+		return config{}, err
+	}
+	if pool.MaxConnLifetime, err = cli.DurationEnv("DB_MAX_CONN_LIFETIME", pool.MaxConnLifetime); err != nil {
+		return config{}, err
+	}
+	if pool.MaxConnIdleTime, err = cli.DurationEnv("DB_MAX_CONN_IDLE_TIME", pool.MaxConnIdleTime); err != nil {
+		return config{}, err
+	}
+	if pool.HealthCheckPeriod, err = cli.DurationEnv("DB_HEALTH_CHECK_PERIOD", pool.HealthCheckPeriod); err != nil {
+		return config{}, err
+	}
+
+	drainTimeout, err := cli.DurationEnv("DRAIN_TIMEOUT", *drainTimeoutFlag)
+	if err != nil {
+		return config{}, err
+	}
+
+	speeds, err := speedConfig()
+	if err != nil {
+		return config{}, err
+	}
+
+	precision, err := loadPrecision()
+	if err != nil {
+		return config{}, err
+	}
+
+	storageName, err := storage()
+	if err != nil {
+		return config{}, err
+	}
+
+	autoMigrate, err := cli.BoolEnv("AUTO_MIGRATE", *autoMigrateFlag)
+	if err != nil {
+		return config{}, err
+	}
+
+	if pool.MaxConns < 0 || pool.MinConns < 0 {
+		return config{}, fmt.Errorf("pool size should be non-negative")
+	}
+	if pool.MaxConns > 0 && pool.MinConns > pool.MaxConns {
+		return config{}, fmt.Errorf("min pool size should not be greater than max pool size")
+	}
+
 	return config{
-		srvAddr: srvAddr,
-		connStr: connStr,
+		srvAddr:       srvAddr,
+		connStr:       connStr,
+		storage:       storageName,
+		autoMigrate:   autoMigrate,
+		drainTimeout:  drainTimeout,
+		pool:          pool,
+		speeds:        speeds,
+		loadPrecision: precision,
 	}, nil
 }
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "import" {
+		err := runImport(os.Args[2:])
+		if err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		err := runMigrate(os.Args[2:])
+		if err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
 	cfg, err := parseVariables()
 	if err != nil {
 		log.Fatal("reading config: %w", err)
 	}
 
-	db, err := newConn(context.Background(), cfg.connStr)
+	a, closeDb, err := cli.OpenApp(
+		context.Background(),
+		cfg.storage,
+		cfg.connStr,
+		cfg.autoMigrate,
+		cfg.pool,
+		services.WithSpeeds(cfg.speeds),
+		services.WithLoadPrecision(cfg.loadPrecision),
+	)
+	if errors.Is(err, cli.ErrNoSchema) {
+		log.Fatalf(`%v; run "migrate up" subcommand or start server with "-auto-migrate" flag`, err)
+	}
 	if err != nil {
 		log.Fatal(err)
 	}
-	defer db.Close(context.Background())
+	defer closeDb()
 
-	ok, err := checkVersion(db)
+	err = a.Svc.ResumeDeleteJobs(context.Background())
 	if err != nil {
 		log.Fatal(err)
 	}
-	if !ok {
-		log.Fatal("you need to run migrations before running server")
-	}
-
-	a := app.NewApp(db)
 
 	router := chi.NewRouter()
 
@@ -117,14 +190,76 @@ func main() {
 	router.Use(middleware.Logger)
 
 	router.Route("/api/route", func(r chi.Router) {
+		r.Get("/", delivery.ListHandler(a))
+		r.Get("/export", delivery.ExportHandler(a))
+		r.Get("/stats", delivery.StatsHandler(a))
+		r.Get("/search", delivery.SearchHandler(a))
+		r.Get("/near", delivery.NearHandler(a))
+		r.Get("/within", delivery.WithinHandler(a))
+		r.Get("/trips", delivery.TripsHandler(a))
 		r.Post("/register", delivery.RegisterHandler(a))
+		r.Post("/register/batch", delivery.RegisterBatchHandler(a))
+		r.Post("/import", delivery.ImportHandler(a))
 		r.Get("/{id}", delivery.GetHandler(a))
+		r.Patch("/{id}", delivery.UpdateHandler(a))
+		r.Post("/{id}/assign", delivery.AssignHandler(a))
+		r.Get("/{id}/history", delivery.HistoryHandler(a))
+		r.Get("/{id}/schedule", delivery.GetScheduleHandler(a))
+		r.Put("/{id}/schedule", delivery.SetScheduleHandler(a))
+		r.Delete("/{id}/schedule", delivery.DeleteScheduleHandler(a))
 		r.Delete("/", delivery.DeleteHandler(a))
+		r.Get("/jobs/{id}", delivery.DeleteJobHandler(a))
 	})
 
-	fmt.Printf("server is running on %s\n", cfg.srvAddr)
-	err = http.ListenAndServe(cfg.srvAddr, router)
+	router.Route("/api/cargo-types", func(r chi.Router) {
+		r.Get("/", delivery.ListCargoTypesHandler(a))
+		r.Post("/", delivery.CreateCargoTypeHandler(a))
+		r.Get("/{code}", delivery.GetCargoTypeHandler(a))
+		r.Patch("/{code}", delivery.UpdateCargoTypeHandler(a))
+		r.Delete("/{code}", delivery.DeleteCargoTypeHandler(a))
+	})
+
+	router.Route("/api/vehicles", func(r chi.Router) {
+		r.Get("/", delivery.ListVehiclesHandler(a))
+		r.Post("/", delivery.CreateVehicleHandler(a))
+		r.Get("/{id}", delivery.GetVehicleHandler(a))
+		r.Patch("/{id}", delivery.UpdateVehicleHandler(a))
+		r.Delete("/{id}", delivery.DeleteVehicleHandler(a))
+	})
+
+	srv := &http.Server{
+		Addr:    cfg.srvAddr,
+		Handler: router,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	srvErr := make(chan error, 1)
+	go func() {
+		fmt.Printf("server is running on %s\n", cfg.srvAddr)
+		srvErr <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err = <-srvErr:
+		if !errors.Is(err, http.ErrServerClosed) {
+			log.Print(err)
+		}
+	case <-ctx.Done():
+		fmt.Println("shutting down server")
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.drainTimeout)
+	defer cancel()
+
+	err = srv.Shutdown(shutdownCtx)
 	if err != nil {
-		log.Fatal(err)
+		log.Printf("server shutdown: %v", err)
+	}
+
+	err = a.Svc.Shutdown(shutdownCtx)
+	if err != nil {
+		log.Printf("route service shutdown: %v", err)
 	}
 }
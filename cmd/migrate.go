@@ -0,0 +1,86 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/golang-migrate/migrate/v4"
+	"os"
+	"strconv"
+	"task/internal/cli"
+)
+
+// runMigrate implements "migrate" subcommand applying migrations embedded in the binary.
+func runMigrate(args []string) (err error) {
+	fs := flag.NewFlagSet("migrate", flag.ExitOnError)
+	connStrFlag := fs.String("b", "", "Database connection string")
+	storage := cli.StorageFlag(fs)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s migrate [flags] up|down|status|goto N\n", os.Args[0])
+		fmt.Fprintln(fs.Output(), "  up      apply all pending migrations")
+		fmt.Fprintln(fs.Output(), "  down    revert the last applied migration")
+		fmt.Fprintln(fs.Output(), "  status  print schema version and migrations")
+		fmt.Fprintln(fs.Output(), "  goto N  migrate up or down to version N, 0 reverts all migrations")
+		fs.PrintDefaults()
+	}
+	_ = fs.Parse(args)
+
+	cmd := fs.Arg(0)
+	wantArgs := 1
+	switch cmd {
+	case "up", "down", "status":
+	case "goto":
+		wantArgs = 2
+	default:
+		fs.Usage()
+		return fmt.Errorf("migrate: unknown command %q", cmd)
+	}
+	if fs.NArg() != wantArgs {
+		fs.Usage()
+		return fmt.Errorf("migrate: unexpected arguments")
+	}
+
+	connStr := os.Getenv("CONNECTION_STRING")
+	if connStr == "" {
+		connStr = *connStrFlag
+	}
+	if connStr == "" {
+		return fmt.Errorf(`set env variable CONNECTION_STRING or use "-b" flag`)
+	}
+
+	storageName, err := storage()
+	if err != nil {
+		return err
+	}
+
+	s, err := cli.OpenSchema(storageName, connStr)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+
+	switch cmd {
+	case "up":
+		err = s.Up()
+	case "down":
+		err = s.Down()
+	case "goto":
+		var version uint64
+		version, err = strconv.ParseUint(fs.Arg(1), 10, 32)
+		if err != nil {
+			return fmt.Errorf("migrate: parsing version: %w", err)
+		}
+		err = s.Goto(uint(version))
+	case "status":
+		return s.Status(os.Stdout)
+	}
+	if errors.Is(err, migrate.ErrNoChange) {
+		fmt.Println("no change")
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("migrate %s: %w", cmd, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,24 @@
+package main
+
+import (
+	"context"
+	"io"
+	"task/pkg/dto"
+)
+
+// backend performs route operations either directly on the storage or through running server.
+// Results are returned as API response bodies, so both backends print the same output.
+type backend interface {
+	// Register returns id assigned to the route, it differs from requested one if that was taken.
+	Register(ctx context.Context, req dto.RegisterRouteRequestBody) (int, error)
+	// Get returns actual route with load converted to unit, empty unit means canonical one.
+	Get(ctx context.Context, id int, unit string) (dto.RouteResponseBody, error)
+	Delete(ctx context.Context, ids []int) (int64, error)
+	DeleteJob(ctx context.Context, id int64) (dto.DeleteJobResponseBody, error)
+	List(ctx context.Context, req dto.ListRoutesRequest) (dto.ListRoutesResponseBody, error)
+	Import(ctx context.Context, r io.Reader, format string) (dto.ImportReportResponseBody, error)
+	// Export writes all routes matching the request to w, limit of the request is ignored.
+	Export(ctx context.Context, req dto.ListRoutesRequest, format string, w io.Writer) error
+	// Close waits for background work started by the backend, e.g. delete jobs.
+	Close(ctx context.Context) error
+}
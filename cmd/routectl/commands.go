@@ -0,0 +1,283 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"task/internal/dto"
+	"task/internal/entities"
+	"task/pkg/decimal"
+	"time"
+)
+
+// jobPollInterval is delay between requests for delete job status.
+const jobPollInterval = 200 * time.Millisecond
+
+func newFlagSet(name string) *flag.FlagSet {
+	return flag.NewFlagSet(name, flag.ExitOnError)
+}
+
+// openInput opens named file, "-" means standard input.
+func openInput(name string) (io.ReadCloser, error) {
+	if name == "-" {
+		return io.NopCloser(os.Stdin), nil
+	}
+
+	return os.Open(name)
+}
+
+func runRegister(ctx context.Context, b backend, out *printer, args []string) error {
+	fs := newFlagSet("register")
+	file := fs.String("f", "-", `JSON file with route, "-" reads standard input`)
+	fs.Parse(args)
+
+	in, err := openInput(*file)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	var req dto.RegisterRouteRequestBody
+	err = json.NewDecoder(in).Decode(&req)
+	if err != nil {
+		return fmt.Errorf("decoding route: %w", err)
+	}
+
+	id, err := b.Register(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	reissued := id != req.RouteID
+	result := struct {
+		RouteID  int  `json:"route_id"`
+		Reissued bool `json:"reissued"`
+	}{id, reissued}
+
+	return out.print(result, table{
+		header: []string{"ROUTE_ID", "REISSUED"},
+		rows:   [][]string{{strconv.Itoa(id), strconv.FormatBool(reissued)}},
+	})
+}
+
+func runGet(ctx context.Context, b backend, out *printer, args []string) error {
+	fs := newFlagSet("get")
+	unit := fs.String("unit", "", "Unit to convert load to, canonical one by default")
+	fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		return fmt.Errorf("expected one route id")
+	}
+	id, err := strconv.Atoi(fs.Arg(0))
+	if err != nil {
+		return fmt.Errorf("invalid route id %q", fs.Arg(0))
+	}
+
+	route, err := b.Get(ctx, id, *unit)
+	if err != nil {
+		return err
+	}
+
+	return out.print(route, routesTable([]dto.RouteResponseBody{route}))
+}
+
+func runDelete(ctx context.Context, b backend, out *printer, args []string) error {
+	fs := newFlagSet("delete")
+	wait := fs.Bool("wait", false, "Wait for delete job to finish and print its result")
+	fs.Parse(args)
+
+	if fs.NArg() == 0 {
+		return fmt.Errorf("expected route ids")
+	}
+	ids := make([]int, 0, fs.NArg())
+	for _, arg := range fs.Args() {
+		id, err := strconv.Atoi(arg)
+		if err != nil {
+			return fmt.Errorf("invalid route id %q", arg)
+		}
+		ids = append(ids, id)
+	}
+
+	jobID, err := b.Delete(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	if !*wait {
+		result := struct {
+			JobID int64 `json:"job_id"`
+		}{jobID}
+
+		return out.print(result, table{
+			header: []string{"JOB_ID"},
+			rows:   [][]string{{strconv.FormatInt(jobID, 10)}},
+		})
+	}
+
+	for {
+		job, err := b.DeleteJob(ctx, jobID)
+		if err != nil {
+			return err
+		}
+
+		status := entities.JobStatus(job.Status)
+		if status != entities.JobPending && status != entities.JobRunning {
+			return out.print(job, deleteJobTables(job)...)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(jobPollInterval):
+		}
+	}
+}
+
+// filterFlags registers flags of route filters shared by list and export.
+func filterFlags(fs *flag.FlagSet) func() (dto.ListRoutesRequest, error) {
+	cargoType := fs.String("cargo-type", "", "Only routes of the cargo type")
+	actual := fs.String("actual", "", `Only actual ("true") or deleted ("false") routes`)
+	minLoad := fs.String("min-load", "", "Minimal load in canonical unit")
+	maxLoad := fs.String("max-load", "", "Maximal load in canonical unit")
+	namePrefix := fs.String("name-prefix", "", "Only routes with name starting with the prefix")
+	vehicleID := fs.Int("vehicle-id", 0, "Only routes assigned to the vehicle")
+
+	return func() (req dto.ListRoutesRequest, err error) {
+		req.CargoType = *cargoType
+		req.NamePrefix = *namePrefix
+
+		if *actual != "" {
+			isActual, err := strconv.ParseBool(*actual)
+			if err != nil {
+				return dto.ListRoutesRequest{}, fmt.Errorf("invalid -actual %q", *actual)
+			}
+			req.IsActual = &isActual
+		}
+
+		if *minLoad != "" {
+			load, err := decimal.Parse(*minLoad)
+			if err != nil {
+				return dto.ListRoutesRequest{}, fmt.Errorf("invalid -min-load: %w", err)
+			}
+			req.MinLoad = &load
+		}
+
+		if *maxLoad != "" {
+			load, err := decimal.Parse(*maxLoad)
+			if err != nil {
+				return dto.ListRoutesRequest{}, fmt.Errorf("invalid -max-load: %w", err)
+			}
+			req.MaxLoad = &load
+		}
+
+		if *vehicleID != 0 {
+			req.VehicleID = vehicleID
+		}
+
+		return req, nil
+	}
+}
+
+func runList(ctx context.Context, b backend, out *printer, args []string) error {
+	fs := newFlagSet("list")
+	filters := filterFlags(fs)
+	limit := fs.Int("limit", dto.DefaultListLimit, "Page size")
+	cursor := fs.String("cursor", "", "Cursor of the page, returned as next cursor by previous call")
+	all := fs.Bool("all", false, "Fetch all pages")
+	fs.Parse(args)
+
+	req, err := filters()
+	if err != nil {
+		return err
+	}
+	req.Limit = *limit
+	req.Cursor = *cursor
+
+	resp, err := b.List(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	for *all && resp.NextCursor != "" {
+		req.Cursor = resp.NextCursor
+		page, err := b.List(ctx, req)
+		if err != nil {
+			return err
+		}
+
+		resp.Routes = append(resp.Routes, page.Routes...)
+		resp.NextCursor = page.NextCursor
+	}
+
+	err = out.print(resp, routesTable(resp.Routes))
+	if err != nil {
+		return err
+	}
+
+	if !out.json && resp.NextCursor != "" {
+		fmt.Fprintf(os.Stderr, "next cursor: %s\n", resp.NextCursor)
+	}
+
+	return nil
+}
+
+func runImport(ctx context.Context, b backend, out *printer, args []string) error {
+	fs := newFlagSet("import")
+	format := fs.String("format", "", "File format, csv or jsonl, detected by file extension by default")
+	fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		return fmt.Errorf(`expected one file, "-" reads standard input`)
+	}
+
+	in, err := openInput(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	if *format == "" {
+		*format = dto.FormatFromFileName(fs.Arg(0))
+	}
+
+	report, err := b.Import(ctx, in, *format)
+	if err != nil {
+		return err
+	}
+
+	return out.print(report, importReportTables(report)...)
+}
+
+func runExport(ctx context.Context, b backend, out *printer, args []string) error {
+	fs := newFlagSet("export")
+	filters := filterFlags(fs)
+	format := fs.String("format", "jsonl", "Export format: jsonl, csv or geojson")
+	file := fs.String("f", "-", `Output file, "-" writes standard output`)
+	fs.Parse(args)
+
+	req, err := filters()
+	if err != nil {
+		return err
+	}
+
+	if *file == "-" {
+		return b.Export(ctx, req, *format, out.w)
+	}
+
+	f, err := os.Create(*file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	err = b.Export(ctx, req, *format, f)
+	if err != nil {
+		return err
+	}
+
+	return f.Close()
+}
@@ -0,0 +1,258 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"task/internal/cli"
+	"task/internal/entities"
+	"task/pkg/decimal"
+	"task/pkg/dto"
+	"testing"
+)
+
+var testWaypoints = []dto.WaypointBody{{Lat: 55.7558, Lon: 37.6173}, {Lat: 56.8587, Lon: 35.9176}}
+
+// newLocalTestBackend opens local backend on a new SQLite database with "sand" cargo type.
+func newLocalTestBackend(t *testing.T) *localBackend {
+	ctx := context.Background()
+
+	a, closeDb, err := cli.OpenApp(ctx, cli.StorageSQLite, filepath.Join(t.TempDir(), "routes.db"), true, cli.PoolConfig{})
+	require.Nil(t, err)
+	b := &localBackend{app: a, closeDb: closeDb}
+	t.Cleanup(func() {
+		require.Nil(t, b.Close(ctx))
+	})
+
+	_, err = a.Cargo.Create(ctx, dto.CargoTypeRequestBody{Code: "sand", DisplayName: "Sand", Unit: entities.UnitTonne})
+	require.Nil(t, err)
+
+	return b
+}
+
+func registerRoutes(t *testing.T, b backend, count int) {
+	for i := 1; i <= count; i++ {
+		_, err := b.Register(context.Background(), dto.RegisterRouteRequestBody{
+			RouteID:   i,
+			RouteName: fmt.Sprintf("route %d", i),
+			Load:      decimal.New(int64(i), 0),
+			CargoType: "sand",
+			Waypoints: testWaypoints,
+		})
+		require.Nil(t, err)
+	}
+}
+
+// runJSON runs command with JSON output and decodes the output into dst.
+func runJSON(t *testing.T, run func(context.Context, backend, *printer, []string) error, b backend, dst any, args ...string) error {
+	var buf bytes.Buffer
+	out, err := newPrinter(&buf, outputJSON)
+	require.Nil(t, err)
+
+	err = run(context.Background(), b, out, args)
+	if err != nil {
+		return err
+	}
+
+	require.Nil(t, json.Unmarshal(buf.Bytes(), dst))
+	return nil
+}
+
+func routeIds(routes []dto.RouteResponseBody) []int {
+	ids := make([]int, 0, len(routes))
+	for _, route := range routes {
+		ids = append(ids, route.RouteID)
+	}
+	return ids
+}
+
+func TestFilterFlags(t *testing.T) {
+	minLoad := decimal.MustParse("1.5")
+	maxLoad := decimal.MustParse("10")
+	isActual := false
+	vehicleID := 7
+
+	testCases := []struct {
+		name     string
+		args     []string
+		expected dto.ListRoutesRequest
+		wantErr  bool
+		err      error
+	}{
+		{
+			name: "no filters",
+		},
+		{
+			name: "all filters",
+			args: []string{"-cargo-type", "sand", "-actual", "false", "-min-load", "1.5", "-max-load", "10", "-name-prefix", "Mos", "-vehicle-id", "7"},
+			expected: dto.ListRoutesRequest{
+				CargoType:  "sand",
+				IsActual:   &isActual,
+				MinLoad:    &minLoad,
+				MaxLoad:    &maxLoad,
+				NamePrefix: "Mos",
+				VehicleID:  &vehicleID,
+			},
+		},
+		{
+			name:    "invalid actual",
+			args:    []string{"-actual", "maybe"},
+			wantErr: true,
+			err:     fmt.Errorf(`invalid -actual "maybe"`),
+		},
+		{
+			name:    "invalid load",
+			args:    []string{"-min-load", "1,5"},
+			wantErr: true,
+			err:     fmt.Errorf(`invalid -min-load: invalid decimal "1,5"`),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			filters := filterFlags(fs)
+			require.Nil(t, fs.Parse(tc.args))
+
+			req, err := filters()
+
+			if tc.wantErr {
+				require.Equal(t, tc.err.Error(), err.Error())
+			} else {
+				require.Nil(t, err)
+				require.Equal(t, tc.expected, req)
+			}
+		})
+	}
+}
+
+func TestListQuery(t *testing.T) {
+	var queries []url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.Query())
+		if r.URL.Path == "/api/route/export" {
+			return
+		}
+		w.Write([]byte(`{"status": "success", "data": {"routes": []}}`))
+	}))
+	defer server.Close()
+	b := newHTTPBackend(server.URL)
+
+	filters := []string{"-cargo-type", "sand", "-actual", "true", "-min-load", "1.5", "-max-load", "10", "-name-prefix", "Mos", "-vehicle-id", "7"}
+	var resp dto.ListRoutesResponseBody
+	require.Nil(t, runJSON(t, runList, b, &resp, append(filters, "-limit", "5", "-cursor", "Mg")...))
+
+	var export bytes.Buffer
+	out, err := newPrinter(&export, outputTable)
+	require.Nil(t, err)
+	require.Nil(t, runExport(context.Background(), b, out, append(filters, "-format", "csv")))
+
+	expected := url.Values{
+		"cargo_type":  {"sand"},
+		"is_actual":   {"true"},
+		"min_load":    {"1.5"},
+		"max_load":    {"10"},
+		"name_prefix": {"Mos"},
+		"vehicle_id":  {"7"},
+	}
+	require.Len(t, queries, 2)
+
+	list := url.Values{"limit": {"5"}, "cursor": {"Mg"}}
+	for k, v := range expected {
+		list[k] = v
+	}
+	require.Equal(t, list, queries[0])
+
+	// export has no pages
+	expected.Set("format", "csv")
+	require.Equal(t, expected, queries[1])
+}
+
+func TestListAll(t *testing.T) {
+	b := newLocalTestBackend(t)
+	registerRoutes(t, b, 5)
+
+	var page dto.ListRoutesResponseBody
+	require.Nil(t, runJSON(t, runList, b, &page, "-limit", "2"))
+	require.Equal(t, []int{1, 2}, routeIds(page.Routes))
+	require.NotEmpty(t, page.NextCursor)
+
+	var next dto.ListRoutesResponseBody
+	require.Nil(t, runJSON(t, runList, b, &next, "-limit", "2", "-cursor", page.NextCursor))
+	require.Equal(t, []int{3, 4}, routeIds(next.Routes))
+
+	var all dto.ListRoutesResponseBody
+	require.Nil(t, runJSON(t, runList, b, &all, "-limit", "2", "-all"))
+	require.Equal(t, []int{1, 2, 3, 4, 5}, routeIds(all.Routes))
+	require.Empty(t, all.NextCursor)
+
+	var filtered dto.ListRoutesResponseBody
+	require.Nil(t, runJSON(t, runList, b, &filtered, "-limit", "1", "-all", "-min-load", "2000", "-max-load", "4000"))
+	require.Equal(t, []int{2, 3, 4}, routeIds(filtered.Routes))
+}
+
+// jobsBackend returns delete job in the given statuses one after another.
+type jobsBackend struct {
+	backend
+	statuses []string
+	calls    int
+}
+
+func (b *jobsBackend) Delete(ctx context.Context, ids []int) (int64, error) {
+	return 7, nil
+}
+
+func (b *jobsBackend) DeleteJob(ctx context.Context, id int64) (dto.DeleteJobResponseBody, error) {
+	status := b.statuses[min(b.calls, len(b.statuses)-1)]
+	b.calls++
+	return dto.DeleteJobResponseBody{JobID: id, Status: status, Attempts: 1}, nil
+}
+
+func TestDeleteWait(t *testing.T) {
+	t.Run("polls until job is finished", func(t *testing.T) {
+		b := &jobsBackend{statuses: []string{"pending", "running", "failed"}}
+
+		var job dto.DeleteJobResponseBody
+		require.Nil(t, runJSON(t, runDelete, b, &job, "-wait", "1"))
+		require.Equal(t, "failed", job.Status)
+		require.Equal(t, 3, b.calls)
+	})
+
+	t.Run("without wait job is not polled", func(t *testing.T) {
+		b := &jobsBackend{statuses: []string{"pending"}}
+
+		var resp struct {
+			JobID int64 `json:"job_id"`
+		}
+		require.Nil(t, runJSON(t, runDelete, b, &resp, "1"))
+		require.Equal(t, int64(7), resp.JobID)
+		require.Equal(t, 0, b.calls)
+	})
+
+	t.Run("local backend", func(t *testing.T) {
+		b := newLocalTestBackend(t)
+		registerRoutes(t, b, 2)
+
+		var job dto.DeleteJobResponseBody
+		require.Nil(t, runJSON(t, runDelete, b, &job, "-wait", "1", "3"))
+		require.Equal(t, "succeeded", job.Status)
+		require.Equal(t, []dto.DeleteJobItemResponseBody{
+			{RouteID: 1, Status: "deleted"},
+			{RouteID: 3, Status: "not_found"},
+		}, job.Items)
+
+		_, err := b.Get(context.Background(), 1, "")
+		require.NotNil(t, err)
+	})
+
+	t.Run("invalid id", func(t *testing.T) {
+		err := runJSON(t, runDelete, &jobsBackend{}, nil, "-wait", "x")
+		require.Equal(t, `invalid route id "x"`, err.Error())
+	})
+}
@@ -0,0 +1,19 @@
+package main
+
+import (
+	"context"
+	"task/pkg/client"
+)
+
+// httpBackend calls API of running server.
+type httpBackend struct {
+	*client.Client
+}
+
+func newHTTPBackend(server string) *httpBackend {
+	return &httpBackend{Client: client.New(server)}
+}
+
+func (b *httpBackend) Close(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"task/internal/app"
+	"task/internal/dto"
+	"task/internal/entities"
+)
+
+// localBackend calls route service directly, without running server.
+type localBackend struct {
+	app     *app.App
+	closeDb func()
+}
+
+func (b *localBackend) Register(ctx context.Context, req dto.RegisterRouteRequestBody) (int, error) {
+	return b.app.Svc.Register(ctx, req)
+}
+
+func (b *localBackend) Get(ctx context.Context, id int, unit string) (dto.RouteResponseBody, error) {
+	route, err := b.app.Svc.GetById(ctx, id)
+	if err != nil {
+		return dto.RouteResponseBody{}, err
+	}
+	if !route.IsActual {
+		return dto.RouteResponseBody{}, fmt.Errorf("route is not actual")
+	}
+
+	if unit == "" {
+		unit = entities.CanonicalUnit
+	}
+	load, err := b.app.Svc.ConvertLoad(ctx, route, unit)
+	if err != nil {
+		return dto.RouteResponseBody{}, err
+	}
+
+	resp := dto.FromEntityModel(route)
+	resp.Load = load
+	resp.Unit = unit
+	return resp, nil
+}
+
+func (b *localBackend) Delete(ctx context.Context, ids []int) (int64, error) {
+	return b.app.Svc.DeleteByIds(ctx, dto.DeleteRoutesRequestBody{RouteIDs: ids})
+}
+
+func (b *localBackend) DeleteJob(ctx context.Context, id int64) (dto.DeleteJobResponseBody, error) {
+	job, err := b.app.Svc.GetDeleteJob(ctx, id)
+	if err != nil {
+		return dto.DeleteJobResponseBody{}, err
+	}
+
+	return dto.FromDeleteJobModel(job), nil
+}
+
+func (b *localBackend) List(ctx context.Context, req dto.ListRoutesRequest) (dto.ListRoutesResponseBody, error) {
+	routes, nextCursor, err := b.app.Svc.List(ctx, req)
+	if err != nil {
+		return dto.ListRoutesResponseBody{}, err
+	}
+
+	resp := dto.ListRoutesResponseBody{
+		Routes:     make([]dto.RouteResponseBody, 0, len(routes)),
+		NextCursor: nextCursor,
+	}
+	for _, route := range routes {
+		resp.Routes = append(resp.Routes, dto.FromEntityModel(route))
+	}
+
+	return resp, nil
+}
+
+func (b *localBackend) Import(ctx context.Context, r io.Reader, format string) (dto.ImportReportResponseBody, error) {
+	report, err := b.app.Svc.Import(ctx, r, format)
+	if err != nil {
+		return dto.ImportReportResponseBody{}, err
+	}
+
+	return dto.FromImportReport(report), nil
+}
+
+func (b *localBackend) Export(ctx context.Context, req dto.ListRoutesRequest, format string, w io.Writer) error {
+	rw, err := dto.NewRouteWriter(w, format)
+	if err != nil {
+		return err
+	}
+
+	req.Limit = 0
+	err = b.app.Svc.Export(ctx, req, rw.Write)
+	if err != nil {
+		return err
+	}
+
+	return rw.Close()
+}
+
+func (b *localBackend) Close(ctx context.Context) error {
+	defer b.closeDb()
+
+	return b.app.Svc.Shutdown(ctx)
+}
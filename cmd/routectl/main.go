@@ -0,0 +1,146 @@
+// Command routectl performs route operations from the command line, either directly on the storage
+// like the server does or through API of running server.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"task/internal/cli"
+	"task/internal/services"
+	"time"
+)
+
+// closeTimeout limits waiting for delete jobs started by local backend.
+const closeTimeout = time.Minute
+
+type command struct {
+	name  string
+	usage string
+	run   func(ctx context.Context, b backend, out *printer, args []string) error
+}
+
+var commands = []command{
+	{"register", "register [-f file]", runRegister},
+	{"get", "get [-unit unit] <id>", runGet},
+	{"delete", "delete [-wait] <id>...", runDelete},
+	{"list", "list [filters] [-limit n] [-cursor c] [-all]", runList},
+	{"import", "import [-format csv|jsonl] <file|->", runImport},
+	{"export", "export [filters] [-format jsonl|csv|geojson] [-f file]", runExport},
+}
+
+func usage() {
+	out := flag.CommandLine.Output()
+	fmt.Fprintf(out, "Usage: %s [flags] <command> [command flags] [args]\n\nCommands:\n", os.Args[0])
+	for _, cmd := range commands {
+		fmt.Fprintf(out, "  %s\n", cmd.usage)
+	}
+	fmt.Fprintln(out, "\nWithout -server routes are read and written directly in the database.\n\nFlags:")
+	flag.PrintDefaults()
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("routectl: ")
+
+	serverFlag := flag.String("server", "", "Base URL of running server, e.g. http://localhost:8080")
+	connStrFlag := flag.String("b", "", "Database connection string, used without -server")
+	outputFlag := flag.String("o", outputTable, `Output format: "table" or "json"`)
+	storage := cli.StorageFlag(flag.CommandLine)
+	speedConfig := cli.SpeedFlags(flag.CommandLine)
+	loadPrecision := cli.LoadPrecisionFlag(flag.CommandLine)
+	flag.Usage = usage
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		usage()
+		os.Exit(2)
+	}
+
+	var cmd *command
+	for i := range commands {
+		if commands[i].name == flag.Arg(0) {
+			cmd = &commands[i]
+		}
+	}
+	if cmd == nil {
+		usage()
+		log.Fatalf("unknown command %q", flag.Arg(0))
+	}
+
+	out, err := newPrinter(os.Stdout, *outputFlag)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	server := os.Getenv("ROUTECTL_SERVER")
+	if server == "" {
+		server = *serverFlag
+	}
+
+	var b backend
+	if server != "" {
+		b = newHTTPBackend(server)
+	} else {
+		b, err = openLocalBackend(ctx, *connStrFlag, storage, speedConfig, loadPrecision)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	err = cmd.run(ctx, b, out, flag.Args()[1:])
+
+	closeCtx, cancel := context.WithTimeout(context.Background(), closeTimeout)
+	defer cancel()
+	if closeErr := b.Close(closeCtx); closeErr != nil {
+		log.Printf("closing: %v", closeErr)
+	}
+
+	if err != nil {
+		log.Fatalf("%s: %v", cmd.name, err)
+	}
+}
+
+func openLocalBackend(
+	ctx context.Context,
+	connStr string,
+	storage func() (string, error),
+	speedConfig func() (services.SpeedConfig, error),
+	loadPrecision func() (int32, error),
+) (*localBackend, error) {
+	if val := os.Getenv("CONNECTION_STRING"); val != "" {
+		connStr = val
+	}
+	if connStr == "" {
+		return nil, fmt.Errorf(`set env variable ROUTECTL_SERVER or use "-server" flag to call server, or set env variable CONNECTION_STRING or use "-b" flag to open database`)
+	}
+
+	storageName, err := storage()
+	if err != nil {
+		return nil, err
+	}
+
+	speeds, err := speedConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	precision, err := loadPrecision()
+	if err != nil {
+		return nil, err
+	}
+
+	a, closeDb, err := cli.OpenApp(ctx, storageName, connStr, false, cli.PoolConfig{}, services.WithSpeeds(speeds), services.WithLoadPrecision(precision))
+	if err != nil {
+		return nil, err
+	}
+
+	return &localBackend{app: a, closeDb: closeDb}, nil
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"task/pkg/dto"
+	"text/tabwriter"
+)
+
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+)
+
+// printer writes results either as JSON documents, the same as data of API responses,
+// or as tab-aligned tables.
+type printer struct {
+	w    io.Writer
+	json bool
+}
+
+func newPrinter(w io.Writer, format string) (*printer, error) {
+	switch format {
+	case outputTable:
+		return &printer{w: w}, nil
+	case outputJSON:
+		return &printer{w: w, json: true}, nil
+	default:
+		return nil, fmt.Errorf("unknown output format %q", format)
+	}
+}
+
+// print writes v in JSON mode, otherwise the tables. Tables are separated by empty lines.
+func (p *printer) print(v any, tables ...table) error {
+	if p.json {
+		enc := json.NewEncoder(p.w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	}
+
+	for i, t := range tables {
+		if i > 0 {
+			fmt.Fprintln(p.w)
+		}
+
+		tw := tabwriter.NewWriter(p.w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, strings.Join(t.header, "\t"))
+		for _, row := range t.rows {
+			fmt.Fprintln(tw, strings.Join(row, "\t"))
+		}
+		err := tw.Flush()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type table struct {
+	header []string
+	rows   [][]string
+}
+
+func routesTable(routes []dto.RouteResponseBody) table {
+	t := table{
+		header: []string{"ROUTE_ID", "NAME", "LOAD", "UNIT", "CARGO_TYPE", "ACTUAL", "WAYPOINTS", "DISTANCE_M", "DURATION_S", "VEHICLE_ID"},
+	}
+	for _, route := range routes {
+		vehicleID := "-"
+		if route.VehicleID != nil {
+			vehicleID = strconv.Itoa(*route.VehicleID)
+		}
+
+		t.rows = append(t.rows, []string{
+			strconv.Itoa(route.RouteID),
+			route.RouteName,
+			route.Load.String(),
+			route.Unit,
+			route.CargoType,
+			strconv.FormatBool(route.IsActual),
+			strconv.Itoa(len(route.Waypoints)),
+			strconv.FormatFloat(route.DistanceM, 'f', 0, 64),
+			strconv.FormatInt(route.DurationS, 10),
+			vehicleID,
+		})
+	}
+
+	return t
+}
+
+func deleteJobTables(job dto.DeleteJobResponseBody) []table {
+	jobTable := table{
+		header: []string{"JOB_ID", "STATUS", "ATTEMPTS", "ERROR"},
+		rows: [][]string{{
+			strconv.FormatInt(job.JobID, 10),
+			job.Status,
+			strconv.Itoa(job.Attempts),
+			job.Error,
+		}},
+	}
+
+	itemsTable := table{
+		header: []string{"ROUTE_ID", "STATUS"},
+	}
+	for _, item := range job.Items {
+		itemsTable.rows = append(itemsTable.rows, []string{strconv.Itoa(item.RouteID), item.Status})
+	}
+
+	return []table{jobTable, itemsTable}
+}
+
+func importReportTables(report dto.ImportReportResponseBody) []table {
+	tables := []table{{
+		header: []string{"TOTAL", "IMPORTED", "REISSUED", "REJECTED"},
+		rows: [][]string{{
+			strconv.Itoa(report.Total),
+			strconv.Itoa(report.Imported),
+			strconv.Itoa(report.Reissued),
+			strconv.Itoa(len(report.Rejected)),
+		}},
+	}}
+
+	if len(report.Rejected) > 0 {
+		rejected := table{
+			header: []string{"LINE", "REASON"},
+		}
+		for _, row := range report.Rejected {
+			rejected.rows = append(rejected.rows, []string{strconv.Itoa(row.Line), row.Reason})
+		}
+		tables = append(tables, rejected)
+	}
+
+	return tables
+}
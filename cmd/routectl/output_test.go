@@ -0,0 +1,80 @@
+package main
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/require"
+	"task/pkg/decimal"
+	"task/pkg/dto"
+	"testing"
+)
+
+func TestPrinter(t *testing.T) {
+	vehicleID := 7
+	routes := []dto.RouteResponseBody{
+		{RouteID: 1, RouteName: "Moscow - Tver", Load: decimal.MustParse("1500"), Unit: "kg", CargoType: "sand", IsActual: true, Waypoints: testWaypoints, DistanceM: 161335.3, DurationS: 9680, VehicleID: &vehicleID},
+		{RouteID: 12, RouteName: "Kazan", Load: decimal.MustParse("0.5"), Unit: "kg", CargoType: "gravel"},
+	}
+	report := dto.ImportReportResponseBody{
+		Total:    3,
+		Imported: 1,
+		Rejected: []dto.RejectedRowResponseBody{{Line: 2, Reason: "route name should not be empty"}},
+	}
+
+	testCases := []struct {
+		name     string
+		format   string
+		v        any
+		tables   []table
+		expected string
+	}{
+		{
+			name:   "routes table",
+			format: outputTable,
+			tables: []table{routesTable(routes)},
+			expected: "ROUTE_ID  NAME           LOAD  UNIT  CARGO_TYPE  ACTUAL  WAYPOINTS  DISTANCE_M  DURATION_S  VEHICLE_ID\n" +
+				"1         Moscow - Tver  1500  kg    sand        true    2          161335      9680        7\n" +
+				"12        Kazan          0.5   kg    gravel      false   0          0           0           -\n",
+		},
+		{
+			name:   "tables are separated by empty line",
+			format: outputTable,
+			tables: importReportTables(report),
+			expected: "TOTAL  IMPORTED  REISSUED  REJECTED\n" +
+				"3      1         0         1\n" +
+				"\n" +
+				"LINE  REASON\n" +
+				"2     route name should not be empty\n",
+		},
+		{
+			name:   "json",
+			format: outputJSON,
+			v:      report,
+			tables: importReportTables(report),
+			expected: `{
+  "total": 3,
+  "imported": 1,
+  "reissued": 0,
+  "rejected": [
+    {
+      "line": 2,
+      "reason": "route name should not be empty"
+    }
+  ]
+}
+`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			out, err := newPrinter(&buf, tc.format)
+			require.Nil(t, err)
+
+			require.Nil(t, out.print(tc.v, tc.tables...))
+			require.Equal(t, tc.expected, buf.String())
+		})
+	}
+
+	_, err := newPrinter(&bytes.Buffer{}, "yaml")
+	require.Equal(t, `unknown output format "yaml"`, err.Error())
+}
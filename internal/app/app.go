@@ -1,18 +1,56 @@
 package app
 
 import (
-	"github.com/jackc/pgx/v5"
+	"database/sql"
 	"task/internal/repositories"
+	"task/internal/repositories/sqlite"
 	"task/internal/services"
 )
 
 type App struct {
-	Svc services.RouteService
+	Svc       services.RouteService
+	Cargo     services.CargoService
+	Vehicles  services.VehicleService
+	Schedules services.ScheduleService
 }
 
-func NewApp(db *pgx.Conn) *App {
-	repo := repositories.NewRouteRepo(db)
-	svc := services.NewRouteService(repo)
+func NewApp(db repositories.Querier, opts ...services.Option) *App {
+	return newApp(
+		repositories.NewRouteRepo(db),
+		repositories.NewJobRepo(db),
+		repositories.NewCargoTypeRepo(db),
+		repositories.NewVehicleRepo(db),
+		repositories.NewScheduleRepo(db),
+		opts...,
+	)
+}
+
+// NewSQLiteApp returns App storing data in SQLite database, see sqlite.Open.
+func NewSQLiteApp(db *sql.DB, opts ...services.Option) *App {
+	return newApp(
+		sqlite.NewRouteRepo(db),
+		sqlite.NewJobRepo(db),
+		sqlite.NewCargoTypeRepo(db),
+		sqlite.NewVehicleRepo(db),
+		sqlite.NewScheduleRepo(db),
+		opts...,
+	)
+}
+
+func newApp(
+	repo repositories.RouteRepo,
+	jobs repositories.JobRepo,
+	cargo repositories.CargoTypeRepo,
+	vehicles repositories.VehicleRepo,
+	schedules repositories.ScheduleRepo,
+	opts ...services.Option,
+) *App {
+	svc := services.NewRouteService(repo, jobs, cargo, vehicles, opts...)
 
-	return &App{Svc: svc}
+	return &App{
+		Svc:       svc,
+		Cargo:     services.NewCargoService(cargo),
+		Vehicles:  services.NewVehicleService(vehicles, repo, cargo),
+		Schedules: services.NewScheduleService(schedules, repo),
+	}
 }
@@ -0,0 +1,54 @@
+// Package cli holds configuration and startup code shared by command line programs of the service.
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+// IntEnv returns value of env variable if it is set, otherwise def.
+func IntEnv(name string, def int) (int, error) {
+	val := os.Getenv(name)
+	if val == "" {
+		return def, nil
+	}
+
+	res, err := strconv.Atoi(val)
+	if err != nil {
+		return 0, fmt.Errorf("parsing %s: %w", name, err)
+	}
+
+	return res, nil
+}
+
+// BoolEnv returns value of env variable if it is set, otherwise def.
+func BoolEnv(name string, def bool) (bool, error) {
+	val := os.Getenv(name)
+	if val == "" {
+		return def, nil
+	}
+
+	res, err := strconv.ParseBool(val)
+	if err != nil {
+		return false, fmt.Errorf("parsing %s: %w", name, err)
+	}
+
+	return res, nil
+}
+
+// DurationEnv returns value of env variable if it is set, otherwise def.
+func DurationEnv(name string, def time.Duration) (time.Duration, error) {
+	val := os.Getenv(name)
+	if val == "" {
+		return def, nil
+	}
+
+	res, err := time.ParseDuration(val)
+	if err != nil {
+		return 0, fmt.Errorf("parsing %s: %w", name, err)
+	}
+
+	return res, nil
+}
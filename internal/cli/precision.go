@@ -0,0 +1,26 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"task/internal/services"
+	"task/pkg/decimal"
+)
+
+// LoadPrecisionFlag registers load precision flag in fs. Returned function should be called
+// after parsing flags; it applies LOAD_PRECISION env variable on top of the flag.
+func LoadPrecisionFlag(fs *flag.FlagSet) func() (int32, error) {
+	precisionFlag := fs.Int("load-precision", services.DefaultLoadPrecision, "Number of fractional digits of load in kilograms")
+
+	return func() (int32, error) {
+		precision, err := IntEnv("LOAD_PRECISION", *precisionFlag)
+		if err != nil {
+			return 0, err
+		}
+		if precision < 0 || precision > decimal.MaxScale {
+			return 0, fmt.Errorf("load precision should be in range [0, %d]", decimal.MaxScale)
+		}
+
+		return int32(precision), nil
+	}
+}
@@ -0,0 +1,211 @@
+package cli
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database"
+	pgxmigrate "github.com/golang-migrate/migrate/v4/database/pgx/v5"
+	sqlitemigrate "github.com/golang-migrate/migrate/v4/database/sqlite"
+	"github.com/golang-migrate/migrate/v4/source"
+	"io"
+	"log"
+	"os"
+	"task/migrations"
+)
+
+// ErrNoSchema is returned if migrations were not applied to database or some of them are missing.
+var ErrNoSchema = errors.New("database schema is not up to date")
+
+// Migration is an embedded migration.
+type Migration struct {
+	Version uint
+	Name    string
+}
+
+// Schema manages schema of the storage with embedded migrations.
+type Schema struct {
+	m          *migrate.Migrate
+	migrations []Migration
+}
+
+// OpenSchema connects to the storage for migrations, separately from connection used by App.
+func OpenSchema(storage, connStr string) (s *Schema, err error) {
+	var (
+		src    source.Driver
+		driver database.Driver
+	)
+
+	switch storage {
+	case StorageSQLite:
+		src, err = migrations.SQLite()
+		if err != nil {
+			return nil, fmt.Errorf("reading migrations: %w", err)
+		}
+		driver, err = openMigrateDriver("sqlite", connStr, func(db *sql.DB) (database.Driver, error) {
+			return sqlitemigrate.WithInstance(db, &sqlitemigrate.Config{})
+		})
+	default:
+		src, err = migrations.Postgres()
+		if err != nil {
+			return nil, fmt.Errorf("reading migrations: %w", err)
+		}
+		driver, err = openMigrateDriver("pgx", connStr, func(db *sql.DB) (database.Driver, error) {
+			return pgxmigrate.WithInstance(db, &pgxmigrate.Config{})
+		})
+	}
+	if err != nil {
+		src.Close()
+		return nil, err
+	}
+
+	list, err := listMigrations(src)
+	if err != nil {
+		src.Close()
+		driver.Close()
+		return nil, err
+	}
+
+	m, err := migrate.NewWithInstance("iofs", src, storage, driver)
+	if err != nil {
+		src.Close()
+		driver.Close()
+		return nil, fmt.Errorf("creating migrator: %w", err)
+	}
+	m.Log = migrateLogger{}
+
+	return &Schema{
+		m:          m,
+		migrations: list,
+	}, nil
+}
+
+func openMigrateDriver(driverName, connStr string, withInstance func(db *sql.DB) (database.Driver, error)) (database.Driver, error) {
+	db, err := sql.Open(driverName, connStr)
+	if err != nil {
+		return nil, fmt.Errorf("opening database: %w", err)
+	}
+
+	driver, err := withInstance(db)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("opening database: %w", err)
+	}
+
+	return driver, nil
+}
+
+func listMigrations(src source.Driver) (list []Migration, err error) {
+	version, err := src.First()
+	for err == nil {
+		r, name, readErr := src.ReadUp(version)
+		if readErr != nil {
+			return nil, fmt.Errorf("reading migration %d: %w", version, readErr)
+		}
+		r.Close()
+
+		list = append(list, Migration{Version: version, Name: name})
+		version, err = src.Next(version)
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("reading migrations: %w", err)
+	}
+
+	return list, nil
+}
+
+func (s *Schema) Close() {
+	srcErr, dbErr := s.m.Close()
+	if err := errors.Join(srcErr, dbErr); err != nil {
+		log.Printf("closing migrator: %v", err)
+	}
+}
+
+// Up applies all pending migrations. Like other migrating methods, it returns
+// migrate.ErrNoChange if schema is already up to date.
+func (s *Schema) Up() error {
+	return s.m.Up()
+}
+
+// Down reverts the last applied migration.
+func (s *Schema) Down() error {
+	return s.m.Steps(-1)
+}
+
+// Goto migrates up or down to given version, zero version reverts all migrations.
+func (s *Schema) Goto(version uint) error {
+	if version == 0 {
+		return s.m.Down()
+	}
+
+	return s.m.Migrate(version)
+}
+
+func (s *Schema) latest() uint {
+	if len(s.migrations) == 0 {
+		return 0
+	}
+
+	return s.migrations[len(s.migrations)-1].Version
+}
+
+// Check compares version recorded in schema_migrations with the latest embedded migration.
+func (s *Schema) Check() error {
+	version, dirty, err := s.m.Version()
+	if errors.Is(err, migrate.ErrNilVersion) {
+		return fmt.Errorf("%w: no migrations applied, latest is %d", ErrNoSchema, s.latest())
+	}
+	if err != nil {
+		return fmt.Errorf("getting schema version: %w", err)
+	}
+	if dirty {
+		return fmt.Errorf("migration %d failed, database schema should be fixed manually", version)
+	}
+	if version > s.latest() {
+		return fmt.Errorf("schema version %d is newer than latest known migration %d, binary is outdated", version, s.latest())
+	}
+	if version < s.latest() {
+		return fmt.Errorf("%w: schema version is %d, latest is %d", ErrNoSchema, version, s.latest())
+	}
+
+	return nil
+}
+
+// Status prints schema version and state of every migration.
+func (s *Schema) Status(w io.Writer) error {
+	version, dirty, err := s.m.Version()
+	if err != nil && !errors.Is(err, migrate.ErrNilVersion) {
+		return fmt.Errorf("getting schema version: %w", err)
+	}
+
+	switch {
+	case errors.Is(err, migrate.ErrNilVersion):
+		fmt.Fprintln(w, "schema version: none")
+	case dirty:
+		fmt.Fprintf(w, "schema version: %d (dirty)\n", version)
+	default:
+		fmt.Fprintf(w, "schema version: %d\n", version)
+	}
+
+	for _, mig := range s.migrations {
+		state := "pending"
+		if err == nil && mig.Version <= version {
+			state = "applied"
+		}
+		fmt.Fprintf(w, "%06d %-32s %s\n", mig.Version, mig.Name, state)
+	}
+
+	return nil
+}
+
+// migrateLogger prints applied migrations.
+type migrateLogger struct{}
+
+func (migrateLogger) Printf(format string, v ...any) {
+	log.Printf(format, v...)
+}
+
+func (migrateLogger) Verbose() bool {
+	return false
+}
@@ -0,0 +1,79 @@
+package cli
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/stretchr/testify/require"
+	"path/filepath"
+	"testing"
+)
+
+func TestSchemaCheck(t *testing.T) {
+	testCases := []struct {
+		name      string
+		prepare   func(t *testing.T, s *Schema, path string)
+		noSchema  bool
+		errSubstr string
+	}{
+		{
+			name:      "no version",
+			prepare:   func(t *testing.T, s *Schema, path string) {},
+			noSchema:  true,
+			errSubstr: "no migrations applied",
+		},
+		{
+			name: "older than binary",
+			prepare: func(t *testing.T, s *Schema, path string) {
+				require.Nil(t, s.Goto(1))
+			},
+			noSchema:  true,
+			errSubstr: "schema version is 1",
+		},
+		{
+			name: "up to date",
+			prepare: func(t *testing.T, s *Schema, path string) {
+				require.Nil(t, s.Up())
+			},
+		},
+		{
+			name: "newer than binary",
+			prepare: func(t *testing.T, s *Schema, path string) {
+				require.Nil(t, s.Up())
+				require.Nil(t, s.m.Force(int(s.latest()+1)))
+			},
+			errSubstr: "binary is outdated",
+		},
+		{
+			name: "dirty",
+			prepare: func(t *testing.T, s *Schema, path string) {
+				require.Nil(t, s.Up())
+
+				db, err := sql.Open("sqlite", path)
+				require.Nil(t, err)
+				defer db.Close()
+				_, err = db.Exec(`update schema_migrations set dirty = true`)
+				require.Nil(t, err)
+			},
+			errSubstr: "should be fixed manually",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "routes.db")
+			s, err := OpenSchema(StorageSQLite, path)
+			require.Nil(t, err)
+			defer s.Close()
+
+			tc.prepare(t, s, path)
+			err = s.Check()
+
+			if tc.errSubstr == "" {
+				require.Nil(t, err)
+				return
+			}
+			require.NotNil(t, err)
+			require.Contains(t, err.Error(), tc.errSubstr)
+			require.Equal(t, tc.noSchema, errors.Is(err, ErrNoSchema))
+		})
+	}
+}
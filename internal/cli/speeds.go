@@ -0,0 +1,68 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"task/internal/services"
+)
+
+// SpeedFlags registers average speed flags in fs. Returned function should be called after
+// parsing flags; it applies DEFAULT_SPEED and CARGO_SPEEDS env variables on top of them.
+func SpeedFlags(fs *flag.FlagSet) func() (services.SpeedConfig, error) {
+	defaultSpeedFlag := fs.Float64("default-speed", services.DefaultSpeed, "Average speed in km/h for cargo types without configured speed")
+	cargoSpeedsFlag := fs.String("cargo-speeds", "", `Average speeds in km/h per cargo type, e.g. "sand=50,gravel=45"`)
+
+	return func() (cfg services.SpeedConfig, err error) {
+		cfg.Default = *defaultSpeedFlag
+		if val := os.Getenv("DEFAULT_SPEED"); val != "" {
+			cfg.Default, err = strconv.ParseFloat(val, 64)
+			if err != nil {
+				return services.SpeedConfig{}, fmt.Errorf("parsing DEFAULT_SPEED: %w", err)
+			}
+		}
+		if cfg.Default <= 0 {
+			return services.SpeedConfig{}, fmt.Errorf("default speed should be positive")
+		}
+
+		cargoSpeeds := *cargoSpeedsFlag
+		if val := os.Getenv("CARGO_SPEEDS"); val != "" {
+			cargoSpeeds = val
+		}
+		cfg.ByCargo, err = parseCargoSpeeds(cargoSpeeds)
+		if err != nil {
+			return services.SpeedConfig{}, err
+		}
+
+		return cfg, nil
+	}
+}
+
+// parseCargoSpeeds parses comma separated list of cargo_type=speed pairs.
+func parseCargoSpeeds(s string) (speeds map[string]float64, err error) {
+	speeds = make(map[string]float64)
+	if strings.TrimSpace(s) == "" {
+		return speeds, nil
+	}
+
+	for _, pair := range strings.Split(s, ",") {
+		cargoType, val, ok := strings.Cut(pair, "=")
+		cargoType = strings.TrimSpace(cargoType)
+		if !ok || cargoType == "" {
+			return nil, fmt.Errorf("parsing cargo speeds: expected cargo_type=speed, got %q", pair)
+		}
+
+		speed, err := strconv.ParseFloat(strings.TrimSpace(val), 64)
+		if err != nil {
+			return nil, fmt.Errorf("parsing cargo speeds: %s: %w", cargoType, err)
+		}
+		if speed <= 0 {
+			return nil, fmt.Errorf("parsing cargo speeds: %s: speed should be positive", cargoType)
+		}
+		speeds[cargoType] = speed
+	}
+
+	return speeds, nil
+}
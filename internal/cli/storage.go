@@ -0,0 +1,128 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"os"
+	"task/internal/app"
+	"task/internal/repositories/sqlite"
+	"task/internal/services"
+	"time"
+)
+
+const (
+	StoragePostgres = "postgres"
+	// StorageSQLite keeps data in a local file, connection string is a path to it.
+	StorageSQLite = "sqlite"
+)
+
+// StorageFlag registers storage backend flag in fs. Returned function should be called
+// after parsing flags; STORAGE env variable is used if the flag is not set explicitly.
+func StorageFlag(fs *flag.FlagSet) func() (string, error) {
+	storage := fs.String("storage", StoragePostgres, `Storage backend: "postgres" or "sqlite"; for sqlite connection string is a database file path`)
+
+	return func() (string, error) {
+		set := false
+		fs.Visit(func(f *flag.Flag) {
+			set = set || f.Name == "storage"
+		})
+		if val := os.Getenv("STORAGE"); val != "" && !set {
+			*storage = val
+		}
+
+		switch *storage {
+		case StoragePostgres, StorageSQLite:
+			return *storage, nil
+		default:
+			return "", fmt.Errorf("unknown storage %q", *storage)
+		}
+	}
+}
+
+// OpenApp checks schema version of the storage, applying pending migrations first if autoMigrate
+// is set, and connects to it. Returned close function releases the connection. Pool settings
+// are used only by PostgreSQL.
+func OpenApp(ctx context.Context, storage, connStr string, autoMigrate bool, pool PoolConfig, opts ...services.Option) (a *app.App, closeFn func(), err error) {
+	s, err := OpenSchema(storage, connStr)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer s.Close()
+
+	if autoMigrate {
+		err = s.Up()
+		if err != nil && !errors.Is(err, migrate.ErrNoChange) {
+			return nil, nil, err
+		}
+	}
+
+	err = s.Check()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if storage == StorageSQLite {
+		db, err := sqlite.Open(ctx, connStr)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return app.NewSQLiteApp(db, opts...), func() { db.Close() }, nil
+	}
+
+	db, err := newPool(ctx, connStr, pool)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return app.NewApp(db, opts...), db.Close, nil
+}
+
+// PoolConfig overrides pgxpool settings; zero values keep pgxpool defaults.
+type PoolConfig struct {
+	MaxConns          int
+	MinConns          int
+	MaxConnLifetime   time.Duration
+	MaxConnIdleTime   time.Duration
+	HealthCheckPeriod time.Duration
+}
+
+func newPool(ctx context.Context, connStr string, cfg PoolConfig) (db *pgxpool.Pool, err error) {
+	poolCfg, err := pgxpool.ParseConfig(connStr)
+	if err != nil {
+		return nil, fmt.Errorf("parsing connection string: %w", err)
+	}
+
+	if cfg.MaxConns > 0 {
+		poolCfg.MaxConns = int32(cfg.MaxConns)
+	}
+	if cfg.MinConns > 0 {
+		poolCfg.MinConns = int32(cfg.MinConns)
+	}
+	if cfg.MaxConnLifetime > 0 {
+		poolCfg.MaxConnLifetime = cfg.MaxConnLifetime
+	}
+	if cfg.MaxConnIdleTime > 0 {
+		poolCfg.MaxConnIdleTime = cfg.MaxConnIdleTime
+	}
+	if cfg.HealthCheckPeriod > 0 {
+		poolCfg.HealthCheckPeriod = cfg.HealthCheckPeriod
+	}
+
+	db, err = pgxpool.NewWithConfig(ctx, poolCfg)
+	if err != nil {
+		return nil, fmt.Errorf("database connecting: %w", err)
+	}
+
+	err = db.Ping(ctx)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("database ping: %w", err)
+	}
+
+	return db, nil
+}
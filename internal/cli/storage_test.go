@@ -0,0 +1,54 @@
+package cli
+
+import (
+	"flag"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestStorageFlag(t *testing.T) {
+	testCases := []struct {
+		name     string
+		args     []string
+		env      string
+		expected string
+		err      string
+	}{
+		{
+			name:     "default",
+			expected: StoragePostgres,
+		},
+		{
+			name:     "env",
+			env:      StorageSQLite,
+			expected: StorageSQLite,
+		},
+		{
+			name:     "flag wins over env",
+			args:     []string{"-storage", StoragePostgres},
+			env:      StorageSQLite,
+			expected: StoragePostgres,
+		},
+		{
+			name: "unknown",
+			args: []string{"-storage", "mysql"},
+			err:  `unknown storage "mysql"`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv("STORAGE", tc.env)
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			storage := StorageFlag(fs)
+			require.Nil(t, fs.Parse(tc.args))
+
+			got, err := storage()
+			if tc.err != "" {
+				require.EqualError(t, err, tc.err)
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, tc.expected, got)
+		})
+	}
+}
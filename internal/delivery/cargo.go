@@ -0,0 +1,118 @@
+package delivery
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/go-chi/chi/v5"
+	"net/http"
+	"task/internal/app"
+	"task/internal/dto"
+)
+
+func CreateCargoTypeHandler(app *app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		prompt := "create cargo type handler"
+
+		var req dto.CargoTypeRequestBody
+
+		err := json.NewDecoder(r.Body).Decode(&req)
+		if err != nil {
+			handleError(w, prompt, badRequest(err))
+			return
+		}
+
+		cargoType, err := app.Cargo.Create(r.Context(), req)
+		if err != nil {
+			handleError(w, prompt, err)
+			return
+		}
+
+		successResponse(w, http.StatusCreated, dto.FromCargoTypeModel(cargoType))
+	}
+}
+
+func ListCargoTypesHandler(app *app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		prompt := "list cargo types handler"
+
+		cargoTypes, err := app.Cargo.List(r.Context())
+		if err != nil {
+			handleError(w, prompt, err)
+			return
+		}
+
+		resp := make([]dto.CargoTypeResponseBody, 0, len(cargoTypes))
+		for _, cargoType := range cargoTypes {
+			resp = append(resp, dto.FromCargoTypeModel(cargoType))
+		}
+		successResponse(w, http.StatusOK, resp)
+	}
+}
+
+func GetCargoTypeHandler(app *app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		prompt := "get cargo type handler"
+
+		code := chi.URLParam(r, "code")
+		if code == "" {
+			handleError(w, prompt, badRequest(fmt.Errorf("empty code")))
+			return
+		}
+
+		cargoType, err := app.Cargo.GetByCode(r.Context(), code)
+		if err != nil {
+			handleError(w, prompt, err)
+			return
+		}
+
+		successResponse(w, http.StatusOK, dto.FromCargoTypeModel(cargoType))
+	}
+}
+
+func UpdateCargoTypeHandler(app *app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		prompt := "update cargo type handler"
+
+		code := chi.URLParam(r, "code")
+		if code == "" {
+			handleError(w, prompt, badRequest(fmt.Errorf("empty code")))
+			return
+		}
+
+		var req dto.UpdateCargoTypeRequestBody
+
+		err := json.NewDecoder(r.Body).Decode(&req)
+		if err != nil {
+			handleError(w, prompt, badRequest(err))
+			return
+		}
+
+		cargoType, err := app.Cargo.Update(r.Context(), code, req)
+		if err != nil {
+			handleError(w, prompt, err)
+			return
+		}
+
+		successResponse(w, http.StatusOK, dto.FromCargoTypeModel(cargoType))
+	}
+}
+
+func DeleteCargoTypeHandler(app *app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		prompt := "delete cargo type handler"
+
+		code := chi.URLParam(r, "code")
+		if code == "" {
+			handleError(w, prompt, badRequest(fmt.Errorf("empty code")))
+			return
+		}
+
+		err := app.Cargo.Delete(r.Context(), code)
+		if err != nil {
+			handleError(w, prompt, err)
+			return
+		}
+
+		successResponse(w, http.StatusOK, nil)
+	}
+}
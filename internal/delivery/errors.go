@@ -0,0 +1,46 @@
+package delivery
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"task/internal/services"
+)
+
+// Machine-readable error codes of ErrorResponse.
+const (
+	CodeBadRequest = "bad_request"
+	CodeValidation = "validation_failed"
+	CodeNotFound   = "not_found"
+	CodeConflict   = "conflict"
+	CodeGone       = "gone"
+	CodeInternal   = "internal_error"
+)
+
+var errBadRequest = errors.New("bad request")
+
+// badRequest marks errors of request parsing, e.g. malformed JSON or path parameters.
+func badRequest(err error) error {
+	return &services.Error{Kind: errBadRequest, Err: err}
+}
+
+// handleError writes error response with status code and error code matching the kind of err.
+func handleError(w http.ResponseWriter, prompt string, err error) {
+	statusCode, code := classifyError(err)
+	errorResponse(w, code, fmt.Errorf("%s: %w", prompt, err).Error(), statusCode)
+}
+
+func classifyError(err error) (statusCode int, code string) {
+	switch {
+	case errors.Is(err, errBadRequest):
+		return http.StatusBadRequest, CodeBadRequest
+	case errors.Is(err, services.ErrValidation):
+		return http.StatusUnprocessableEntity, CodeValidation
+	case errors.Is(err, services.ErrNotFound):
+		return http.StatusNotFound, CodeNotFound
+	case errors.Is(err, services.ErrConflict):
+		return http.StatusConflict, CodeConflict
+	default:
+		return http.StatusInternalServerError, CodeInternal
+	}
+}
@@ -0,0 +1,16 @@
+package delivery
+
+import (
+	"net/http"
+)
+
+// trackingWriter remembers whether anything was written to response.
+type trackingWriter struct {
+	http.ResponseWriter
+	written bool
+}
+
+func (w *trackingWriter) Write(p []byte) (int, error) {
+	w.written = true
+	return w.ResponseWriter.Write(p)
+}
@@ -3,11 +3,13 @@ package delivery
 import (
 	"encoding/json"
 	"fmt"
-	"github.com/go-chi/chi/v5"
+	"log"
 	"net/http"
 	"strconv"
 	"task/internal/app"
 	"task/internal/dto"
+	"task/internal/entities"
+	"time"
 )
 
 func RegisterHandler(app *app.App) http.HandlerFunc {
@@ -18,13 +20,13 @@ func RegisterHandler(app *app.App) http.HandlerFunc {
 
 		err := json.NewDecoder(r.Body).Decode(&req)
 		if err != nil {
-			errorResponse(w, fmt.Errorf("%s: %w", prompt, err).Error(), http.StatusInternalServerError)
+			handleError(w, prompt, badRequest(err))
 			return
 		}
 
 		routeId, err := app.Svc.Register(r.Context(), req)
 		if err != nil {
-			errorResponse(w, fmt.Errorf("%s: %w", prompt, err).Error(), http.StatusInternalServerError)
+			handleError(w, prompt, err)
 			return
 		}
 
@@ -38,41 +40,323 @@ func RegisterHandler(app *app.App) http.HandlerFunc {
 	}
 }
 
-func GetHandler(app *app.App) http.HandlerFunc {
+func RegisterBatchHandler(app *app.App) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		prompt := "get handler"
+		prompt := "register batch handler"
+
+		var req dto.RegisterBatchRequestBody
 
-		id := chi.URLParam(r, "id")
-		if id == "" {
-			errorResponse(w, fmt.Errorf("%s: empty id", prompt).Error(), http.StatusInternalServerError)
+		err := json.NewDecoder(r.Body).Decode(&req)
+		if err != nil {
+			handleError(w, prompt, badRequest(err))
 			return
 		}
 
-		idInt, err := strconv.Atoi(id)
+		results, err := app.Svc.RegisterBatch(r.Context(), req)
 		if err != nil {
-			errorResponse(w, fmt.Errorf("%s: converting string id to int: %w", prompt, err).Error(), http.StatusInternalServerError)
+			handleError(w, prompt, err)
 			return
 		}
 
-		route, err := app.Svc.GetById(r.Context(), idInt)
+		successResponse(w, http.StatusOK, dto.FromRegisterResults(results))
+	}
+}
+
+func ImportHandler(app *app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		prompt := "import handler"
+
+		format := r.URL.Query().Get("format")
+		if format == "" {
+			format = formatFromContentType(r.Header.Get("Content-Type"))
+		}
+
+		report, err := app.Svc.Import(r.Context(), r.Body, format)
+		if err != nil {
+			handleError(w, prompt, err)
+			return
+		}
+
+		successResponse(w, http.StatusOK, dto.FromImportReport(report))
+	}
+}
+
+func GetHandler(app *app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		prompt := "get handler"
+
+		id, err := idParam(r)
 		if err != nil {
-			errorResponse(w, fmt.Errorf("%s: %w", prompt, err).Error(), http.StatusInternalServerError)
+			handleError(w, prompt, badRequest(err))
+			return
+		}
+
+		route, err := app.Svc.GetById(r.Context(), id)
+		if err != nil {
+			handleError(w, prompt, err)
 			return
 		}
 
 		if !route.IsActual {
-			errorResponse(w, fmt.Errorf("%s: route is not actual", prompt).Error(), http.StatusGone)
+			errorResponse(w, CodeGone, fmt.Errorf("%s: route is not actual", prompt).Error(), http.StatusGone)
+			return
+		}
+
+		unit := r.URL.Query().Get("unit")
+		if unit == "" {
+			unit = entities.CanonicalUnit
+		}
+
+		load, err := app.Svc.ConvertLoad(r.Context(), route, unit)
+		if err != nil {
+			handleError(w, prompt, err)
 			return
 		}
 
 		successResponse(w, http.StatusOK, map[string]any{
 			"route_name": route.RouteName,
-			"load":       route.Load,
+			"load":       load,
+			"unit":       unit,
 			"cargo_type": route.CargoType,
+			"waypoints":  dto.FromWaypoints(route.Waypoints),
+			"polyline":   route.Polyline(),
+			"distance_m": route.Distance,
+			"duration_s": int64(route.Duration / time.Second),
+			"vehicle_id": route.VehicleID,
 		})
 	}
 }
 
+func ListHandler(app *app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		prompt := "list handler"
+
+		req, err := parseListRequest(r)
+		if err != nil {
+			handleError(w, prompt, badRequest(err))
+			return
+		}
+
+		routes, nextCursor, err := app.Svc.List(r.Context(), req)
+		if err != nil {
+			handleError(w, prompt, err)
+			return
+		}
+
+		resp := routesResponse(routes)
+		resp.NextCursor = nextCursor
+		successResponse(w, http.StatusOK, resp)
+	}
+}
+
+func SearchHandler(app *app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		prompt := "search handler"
+
+		query := r.URL.Query()
+		req := dto.SearchRoutesRequest{
+			Query: query.Get("q"),
+		}
+		if val := query.Get("limit"); val != "" {
+			limit, err := strconv.Atoi(val)
+			if err != nil {
+				handleError(w, prompt, badRequest(fmt.Errorf("parsing limit: %w", err)))
+				return
+			}
+			req.Limit = limit
+		}
+
+		routes, err := app.Svc.Search(r.Context(), req)
+		if err != nil {
+			handleError(w, prompt, err)
+			return
+		}
+
+		successResponse(w, http.StatusOK, routesResponse(routes))
+	}
+}
+
+func StatsHandler(app *app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		prompt := "stats handler"
+
+		stats, err := app.Svc.Stats(r.Context())
+		if err != nil {
+			handleError(w, prompt, err)
+			return
+		}
+
+		resp := make([]dto.RouteStatsResponseBody, 0, len(stats))
+		for _, item := range stats {
+			resp = append(resp, dto.FromRouteStatsModel(item))
+		}
+		successResponse(w, http.StatusOK, resp)
+	}
+}
+
+func NearHandler(app *app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		prompt := "near handler"
+
+		req, err := parseNearRequest(r)
+		if err != nil {
+			handleError(w, prompt, badRequest(err))
+			return
+		}
+
+		routes, err := app.Svc.Near(r.Context(), req)
+		if err != nil {
+			handleError(w, prompt, err)
+			return
+		}
+
+		successResponse(w, http.StatusOK, routesResponse(routes))
+	}
+}
+
+func WithinHandler(app *app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		prompt := "within handler"
+
+		req, err := parseWithinRequest(r)
+		if err != nil {
+			handleError(w, prompt, badRequest(err))
+			return
+		}
+
+		routes, err := app.Svc.Within(r.Context(), req)
+		if err != nil {
+			handleError(w, prompt, err)
+			return
+		}
+
+		successResponse(w, http.StatusOK, routesResponse(routes))
+	}
+}
+
+func ExportHandler(app *app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		prompt := "export handler"
+
+		req, err := parseListRequest(r)
+		if err != nil {
+			handleError(w, prompt, badRequest(err))
+			return
+		}
+		req.Limit = 0
+
+		format := r.URL.Query().Get("format")
+		if format == "" {
+			format = dto.FormatJSONL
+		}
+
+		tw := &trackingWriter{ResponseWriter: w}
+		enc, err := dto.NewRouteWriter(tw, format)
+		if err != nil {
+			handleError(w, prompt, badRequest(err))
+			return
+		}
+
+		w.Header().Set("Content-Type", dto.ExportContentType(format))
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="routes.%s"`, format))
+
+		err = app.Svc.Export(r.Context(), req, enc.Write)
+		if err == nil {
+			err = enc.Close()
+		}
+		if err != nil {
+			if !tw.written {
+				w.Header().Del("Content-Disposition")
+				handleError(w, prompt, err)
+				return
+			}
+			// response is partially sent, the only way to report error is to break the connection
+			log.Printf("%s: %v", prompt, err)
+			panic(http.ErrAbortHandler)
+		}
+	}
+}
+
+func UpdateHandler(app *app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		prompt := "update handler"
+
+		id, err := idParam(r)
+		if err != nil {
+			handleError(w, prompt, badRequest(err))
+			return
+		}
+
+		var req dto.UpdateRouteRequestBody
+
+		err = json.NewDecoder(r.Body).Decode(&req)
+		if err != nil {
+			handleError(w, prompt, badRequest(err))
+			return
+		}
+
+		route, err := app.Svc.Update(r.Context(), id, req)
+		if err != nil {
+			handleError(w, prompt, err)
+			return
+		}
+
+		successResponse(w, http.StatusOK, dto.FromEntityModel(route))
+	}
+}
+
+func AssignHandler(app *app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		prompt := "assign handler"
+
+		id, err := idParam(r)
+		if err != nil {
+			handleError(w, prompt, badRequest(err))
+			return
+		}
+
+		var req dto.AssignVehicleRequestBody
+
+		err = json.NewDecoder(r.Body).Decode(&req)
+		if err != nil {
+			handleError(w, prompt, badRequest(err))
+			return
+		}
+
+		route, err := app.Svc.Assign(r.Context(), id, req)
+		if err != nil {
+			handleError(w, prompt, err)
+			return
+		}
+
+		successResponse(w, http.StatusOK, dto.FromEntityModel(route))
+	}
+}
+
+func HistoryHandler(app *app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		prompt := "history handler"
+
+		id, err := idParam(r)
+		if err != nil {
+			handleError(w, prompt, badRequest(err))
+			return
+		}
+
+		versions, err := app.Svc.History(r.Context(), id)
+		if err != nil {
+			handleError(w, prompt, err)
+			return
+		}
+
+		resp := make([]dto.RouteVersionResponseBody, 0, len(versions))
+		for _, version := range versions {
+			resp = append(resp, dto.FromVersionModel(version))
+		}
+		successResponse(w, http.StatusOK, resp)
+	}
+}
+
 func DeleteHandler(app *app.App) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		prompt := "delete handler"
@@ -81,16 +365,38 @@ func DeleteHandler(app *app.App) http.HandlerFunc {
 
 		err := json.NewDecoder(r.Body).Decode(&req.RouteIDs)
 		if err != nil {
-			errorResponse(w, fmt.Errorf("%s: %w", prompt, err).Error(), http.StatusInternalServerError)
+			handleError(w, prompt, badRequest(err))
+			return
+		}
+
+		jobId, err := app.Svc.DeleteByIds(r.Context(), req)
+		if err != nil {
+			handleError(w, prompt, err)
+			return
+		}
+
+		successResponse(w, http.StatusAccepted, map[string]any{
+			"job_id": jobId,
+		})
+	}
+}
+
+func DeleteJobHandler(app *app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		prompt := "delete job handler"
+
+		id, err := idParam(r)
+		if err != nil {
+			handleError(w, prompt, badRequest(err))
 			return
 		}
 
-		err = app.Svc.DeleteByIds(r.Context(), req)
+		job, err := app.Svc.GetDeleteJob(r.Context(), int64(id))
 		if err != nil {
-			errorResponse(w, fmt.Errorf("%s: %w", prompt, err).Error(), http.StatusInternalServerError)
+			handleError(w, prompt, err)
 			return
 		}
 
-		successResponse(w, http.StatusAccepted, nil)
+		successResponse(w, http.StatusOK, dto.FromDeleteJobModel(job))
 	}
 }
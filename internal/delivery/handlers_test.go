@@ -0,0 +1,136 @@
+package delivery
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"task/internal/app"
+	"task/internal/entities"
+	"task/internal/mocks"
+	"task/internal/repositories"
+	"task/internal/services"
+	"testing"
+)
+
+const routeGeometry = `"waypoints": [{"lat": 55.7558, "lon": 37.6173}, {"lat": 56.8587, "lon": 35.9176}],
+	"polyline": "wxhsIccrdFclvEb~jI", "distance_m": 161335.30076083014, "duration_s": 9680`
+
+// TestRouteHandlers runs requests one after another against in-memory repository,
+// so each case sees routes registered by previous ones.
+func TestRouteHandlers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cargo := mocks.NewMockCargoTypeRepo(ctrl)
+	cargo.EXPECT().List(gomock.Any()).Return([]entities.CargoType{
+		{Code: "sand", DisplayName: "Sand", Unit: entities.UnitKilogram},
+	}, nil).AnyTimes()
+
+	a := &app.App{
+		Svc: services.NewRouteService(
+			repositories.NewMemoryRouteRepo(),
+			mocks.NewMockJobRepo(ctrl),
+			cargo,
+			mocks.NewMockVehicleRepo(ctrl),
+		),
+	}
+
+	router := chi.NewRouter()
+	router.Route("/api/route", func(r chi.Router) {
+		r.Get("/", ListHandler(a))
+		r.Get("/search", SearchHandler(a))
+		r.Post("/register", RegisterHandler(a))
+		r.Get("/{id}", GetHandler(a))
+		r.Get("/{id}/history", HistoryHandler(a))
+	})
+
+	testCases := []struct {
+		name         string
+		method       string
+		target       string
+		body         string
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			name:         "register",
+			method:       http.MethodPost,
+			target:       "/api/route/register",
+			body:         `{"route_id": 1, "route_name": "Moscow - Tver", "load": 1.5, "unit": "t", "cargo_type": "sand", "waypoints": [{"lat": 55.7558, "lon": 37.6173}, {"lat": 56.8587, "lon": 35.9176}]}`,
+			expectedCode: http.StatusOK,
+			expectedBody: `{"status": "success", "data": {}}`,
+		},
+		{
+			name:         "register taken id",
+			method:       http.MethodPost,
+			target:       "/api/route/register",
+			body:         `{"route_id": 1, "route_name": "Moscow - Tver gravel", "load": 2, "cargo_type": "sand", "waypoints": [{"lat": 55.7558, "lon": 37.6173}, {"lat": 56.8587, "lon": 35.9176}]}`,
+			expectedCode: http.StatusAlreadyReported,
+			expectedBody: `{"status": "success", "data": {"route_id": 2}}`,
+		},
+		{
+			name:         "unknown cargo type",
+			method:       http.MethodPost,
+			target:       "/api/route/register",
+			body:         `{"route_id": 3, "route_name": "Kazan loop", "load": 2, "cargo_type": "ore", "waypoints": [{"lat": 55.7558, "lon": 37.6173}, {"lat": 56.8587, "lon": 35.9176}]}`,
+			expectedCode: http.StatusUnprocessableEntity,
+			expectedBody: `{"status": "error", "code": "validation_failed", "error": "register handler: converting dto to entity model: unknown cargo type \"ore\""}`,
+		},
+		{
+			name:         "get reissued route",
+			method:       http.MethodGet,
+			target:       "/api/route/2",
+			expectedCode: http.StatusOK,
+			expectedBody: `{"status": "success", "data": {"route_name": "Moscow - Tver gravel", "load": 2, "unit": "kg", "cargo_type": "sand", "vehicle_id": null, ` + routeGeometry + `}}`,
+		},
+		{
+			name:         "get superseded route",
+			method:       http.MethodGet,
+			target:       "/api/route/1",
+			expectedCode: http.StatusGone,
+			expectedBody: `{"status": "error", "code": "gone", "error": "get handler: route is not actual"}`,
+		},
+		{
+			name:         "get missing route",
+			method:       http.MethodGet,
+			target:       "/api/route/3",
+			expectedCode: http.StatusNotFound,
+			expectedBody: `{"status": "error", "code": "not_found", "error": "get handler: getting route by id: getting route by id: no rows in result set"}`,
+		},
+		{
+			name:         "list actual routes",
+			method:       http.MethodGet,
+			target:       "/api/route/?is_actual=true",
+			expectedCode: http.StatusOK,
+			expectedBody: `{"status": "success", "data": {"routes": [{"route_id": 2, "route_name": "Moscow - Tver gravel", "load": 2, "unit": "kg", "cargo_type": "sand", "is_actual": true, ` + routeGeometry + `}]}}`,
+		},
+		{
+			name:         "search with typo",
+			method:       http.MethodGet,
+			target:       "/api/route/search?q=gravle",
+			expectedCode: http.StatusOK,
+			expectedBody: `{"status": "success", "data": {"routes": [{"route_id": 2, "route_name": "Moscow - Tver gravel", "load": 2, "unit": "kg", "cargo_type": "sand", "is_actual": true, ` + routeGeometry + `}]}}`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, tc.target, strings.NewReader(tc.body))
+			rec := httptest.NewRecorder()
+
+			router.ServeHTTP(rec, req)
+
+			require.Equal(t, tc.expectedCode, rec.Code)
+			require.JSONEq(t, tc.expectedBody, rec.Body.String())
+		})
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/route/2/history", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Contains(t, rec.Body.String(), `"route_name":"Moscow - Tver"`)
+	require.Contains(t, rec.Body.String(), `"route_name":"Moscow - Tver gravel"`)
+}
@@ -0,0 +1,122 @@
+package delivery
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"task/internal/app"
+	"task/internal/dto"
+)
+
+func SetScheduleHandler(app *app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		prompt := "set schedule handler"
+
+		id, err := idParam(r)
+		if err != nil {
+			handleError(w, prompt, badRequest(err))
+			return
+		}
+
+		var req dto.ScheduleRequestBody
+
+		err = json.NewDecoder(r.Body).Decode(&req)
+		if err != nil {
+			handleError(w, prompt, badRequest(err))
+			return
+		}
+
+		schedule, err := app.Schedules.Set(r.Context(), id, req)
+		if err != nil {
+			handleError(w, prompt, err)
+			return
+		}
+
+		successResponse(w, http.StatusOK, dto.FromScheduleModel(schedule))
+	}
+}
+
+func GetScheduleHandler(app *app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		prompt := "get schedule handler"
+
+		id, err := idParam(r)
+		if err != nil {
+			handleError(w, prompt, badRequest(err))
+			return
+		}
+
+		schedule, err := app.Schedules.Get(r.Context(), id)
+		if err != nil {
+			handleError(w, prompt, err)
+			return
+		}
+
+		successResponse(w, http.StatusOK, dto.FromScheduleModel(schedule))
+	}
+}
+
+func DeleteScheduleHandler(app *app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		prompt := "delete schedule handler"
+
+		id, err := idParam(r)
+		if err != nil {
+			handleError(w, prompt, badRequest(err))
+			return
+		}
+
+		err = app.Schedules.Delete(r.Context(), id)
+		if err != nil {
+			handleError(w, prompt, err)
+			return
+		}
+
+		successResponse(w, http.StatusOK, nil)
+	}
+}
+
+func TripsHandler(app *app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		prompt := "trips handler"
+
+		query := r.URL.Query()
+		req := dto.TripsRequest{
+			From:   query.Get("from"),
+			To:     query.Get("to"),
+			Cursor: query.Get("cursor"),
+		}
+		if val := query.Get("route_id"); val != "" {
+			routeID, err := strconv.Atoi(val)
+			if err != nil {
+				handleError(w, prompt, badRequest(fmt.Errorf("parsing route_id: %w", err)))
+				return
+			}
+			req.RouteID = &routeID
+		}
+		if val := query.Get("limit"); val != "" {
+			limit, err := strconv.Atoi(val)
+			if err != nil {
+				handleError(w, prompt, badRequest(fmt.Errorf("parsing limit: %w", err)))
+				return
+			}
+			req.Limit = limit
+		}
+
+		trips, nextCursor, err := app.Schedules.Trips(r.Context(), req)
+		if err != nil {
+			handleError(w, prompt, err)
+			return
+		}
+
+		resp := dto.TripsResponseBody{
+			Trips:      make([]dto.TripResponseBody, 0, len(trips)),
+			NextCursor: nextCursor,
+		}
+		for _, trip := range trips {
+			resp.Trips = append(resp.Trips, dto.FromTripModel(trip))
+		}
+		successResponse(w, http.StatusOK, resp)
+	}
+}
@@ -2,7 +2,15 @@ package delivery
 
 import (
 	"encoding/json"
+	"fmt"
+	"github.com/go-chi/chi/v5"
+	"mime"
 	"net/http"
+	"strconv"
+	"strings"
+	"task/internal/dto"
+	"task/internal/entities"
+	"task/pkg/decimal"
 )
 
 const (
@@ -12,6 +20,7 @@ const (
 
 type ErrorResponse struct {
 	Status string `json:"status"`
+	Code   string `json:"code"`
 	Error  string `json:"error"`
 }
 
@@ -20,10 +29,10 @@ type SuccessResponse struct {
 	Data   interface{} `json:"data,omitempty"`
 }
 
-func errorResponse(w http.ResponseWriter, err string, statusCode int) {
+func errorResponse(w http.ResponseWriter, code, err string, statusCode int) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(statusCode)
-	json.NewEncoder(w).Encode(ErrorResponse{Status: errorMsg, Error: err})
+	json.NewEncoder(w).Encode(ErrorResponse{Status: errorMsg, Code: code, Error: err})
 }
 
 func successResponse(w http.ResponseWriter, statusCode int, data interface{}) {
@@ -31,3 +40,153 @@ func successResponse(w http.ResponseWriter, statusCode int, data interface{}) {
 	w.WriteHeader(statusCode)
 	json.NewEncoder(w).Encode(SuccessResponse{Status: successMsg, Data: data})
 }
+
+func routesResponse(routes []entities.Route) dto.ListRoutesResponseBody {
+	resp := dto.ListRoutesResponseBody{
+		Routes: make([]dto.RouteResponseBody, 0, len(routes)),
+	}
+	for _, route := range routes {
+		resp.Routes = append(resp.Routes, dto.FromEntityModel(route))
+	}
+
+	return resp
+}
+
+func parseListRequest(r *http.Request) (req dto.ListRoutesRequest, err error) {
+	query := r.URL.Query()
+
+	req.Cursor = query.Get("cursor")
+	req.CargoType = query.Get("cargo_type")
+	req.NamePrefix = query.Get("name_prefix")
+
+	if val := query.Get("limit"); val != "" {
+		req.Limit, err = strconv.Atoi(val)
+		if err != nil {
+			return dto.ListRoutesRequest{}, fmt.Errorf("parsing limit: %w", err)
+		}
+	}
+
+	if val := query.Get("is_actual"); val != "" {
+		isActual, err := strconv.ParseBool(val)
+		if err != nil {
+			return dto.ListRoutesRequest{}, fmt.Errorf("parsing is_actual: %w", err)
+		}
+		req.IsActual = &isActual
+	}
+
+	req.MinLoad, err = parseLoadParam(query.Get("min_load"))
+	if err != nil {
+		return dto.ListRoutesRequest{}, fmt.Errorf("parsing min_load: %w", err)
+	}
+
+	req.MaxLoad, err = parseLoadParam(query.Get("max_load"))
+	if err != nil {
+		return dto.ListRoutesRequest{}, fmt.Errorf("parsing max_load: %w", err)
+	}
+
+	if val := query.Get("vehicle_id"); val != "" {
+		vehicleID, err := strconv.Atoi(val)
+		if err != nil {
+			return dto.ListRoutesRequest{}, fmt.Errorf("parsing vehicle_id: %w", err)
+		}
+		req.VehicleID = &vehicleID
+	}
+
+	return req, nil
+}
+
+func parseNearRequest(r *http.Request) (req dto.NearRequest, err error) {
+	query := r.URL.Query()
+
+	params := []struct {
+		name string
+		dst  *float64
+	}{
+		{"lat", &req.Lat},
+		{"lon", &req.Lon},
+		{"radius_m", &req.RadiusM},
+	}
+	for _, p := range params {
+		val := query.Get(p.name)
+		if val == "" {
+			return dto.NearRequest{}, fmt.Errorf("missing %s", p.name)
+		}
+
+		*p.dst, err = strconv.ParseFloat(val, 64)
+		if err != nil {
+			return dto.NearRequest{}, fmt.Errorf("parsing %s: %w", p.name, err)
+		}
+	}
+
+	return req, nil
+}
+
+// parseWithinRequest reads bbox parameter in "min_lon,min_lat,max_lon,max_lat" form, as in GeoJSON.
+func parseWithinRequest(r *http.Request) (req dto.WithinRequest, err error) {
+	val := r.URL.Query().Get("bbox")
+	if val == "" {
+		return dto.WithinRequest{}, fmt.Errorf("missing bbox")
+	}
+
+	parts := strings.Split(val, ",")
+	if len(parts) != 4 {
+		return dto.WithinRequest{}, fmt.Errorf("parsing bbox: expected min_lon,min_lat,max_lon,max_lat")
+	}
+
+	coords := make([]float64, 0, len(parts))
+	for _, part := range parts {
+		coord, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil {
+			return dto.WithinRequest{}, fmt.Errorf("parsing bbox: %w", err)
+		}
+		coords = append(coords, coord)
+	}
+
+	return dto.WithinRequest{
+		MinLon: coords[0],
+		MinLat: coords[1],
+		MaxLon: coords[2],
+		MaxLat: coords[3],
+	}, nil
+}
+
+// idParam reads integer id from URL path.
+func idParam(r *http.Request) (int, error) {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		return 0, fmt.Errorf("empty id")
+	}
+
+	idInt, err := strconv.Atoi(id)
+	if err != nil {
+		return 0, fmt.Errorf("converting string id to int: %w", err)
+	}
+
+	return idInt, nil
+}
+
+func parseLoadParam(val string) (*decimal.Decimal, error) {
+	if val == "" {
+		return nil, nil
+	}
+
+	load, err := decimal.Parse(val)
+	if err != nil {
+		return nil, err
+	}
+
+	return &load, nil
+}
+
+// formatFromContentType guesses import format when it is not set explicitly.
+func formatFromContentType(contentType string) string {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch mediaType {
+	case "text/csv":
+		return dto.FormatCSV
+	case "application/x-ndjson", "application/jsonl", "application/x-jsonlines":
+		return dto.FormatJSONL
+	default:
+		return ""
+	}
+}
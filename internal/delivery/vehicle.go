@@ -0,0 +1,116 @@
+package delivery
+
+import (
+	"encoding/json"
+	"net/http"
+	"task/internal/app"
+	"task/internal/dto"
+)
+
+func CreateVehicleHandler(app *app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		prompt := "create vehicle handler"
+
+		var req dto.VehicleRequestBody
+
+		err := json.NewDecoder(r.Body).Decode(&req)
+		if err != nil {
+			handleError(w, prompt, badRequest(err))
+			return
+		}
+
+		vehicle, err := app.Vehicles.Create(r.Context(), req)
+		if err != nil {
+			handleError(w, prompt, err)
+			return
+		}
+
+		successResponse(w, http.StatusCreated, dto.FromVehicleModel(vehicle))
+	}
+}
+
+func ListVehiclesHandler(app *app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		prompt := "list vehicles handler"
+
+		vehicles, err := app.Vehicles.List(r.Context())
+		if err != nil {
+			handleError(w, prompt, err)
+			return
+		}
+
+		resp := make([]dto.VehicleResponseBody, 0, len(vehicles))
+		for _, vehicle := range vehicles {
+			resp = append(resp, dto.FromVehicleModel(vehicle))
+		}
+		successResponse(w, http.StatusOK, resp)
+	}
+}
+
+func GetVehicleHandler(app *app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		prompt := "get vehicle handler"
+
+		id, err := idParam(r)
+		if err != nil {
+			handleError(w, prompt, badRequest(err))
+			return
+		}
+
+		vehicle, err := app.Vehicles.GetById(r.Context(), id)
+		if err != nil {
+			handleError(w, prompt, err)
+			return
+		}
+
+		successResponse(w, http.StatusOK, dto.FromVehicleModel(vehicle))
+	}
+}
+
+func UpdateVehicleHandler(app *app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		prompt := "update vehicle handler"
+
+		id, err := idParam(r)
+		if err != nil {
+			handleError(w, prompt, badRequest(err))
+			return
+		}
+
+		var req dto.UpdateVehicleRequestBody
+
+		err = json.NewDecoder(r.Body).Decode(&req)
+		if err != nil {
+			handleError(w, prompt, badRequest(err))
+			return
+		}
+
+		vehicle, err := app.Vehicles.Update(r.Context(), id, req)
+		if err != nil {
+			handleError(w, prompt, err)
+			return
+		}
+
+		successResponse(w, http.StatusOK, dto.FromVehicleModel(vehicle))
+	}
+}
+
+func DeleteVehicleHandler(app *app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		prompt := "delete vehicle handler"
+
+		id, err := idParam(r)
+		if err != nil {
+			handleError(w, prompt, badRequest(err))
+			return
+		}
+
+		err = app.Vehicles.Delete(r.Context(), id)
+		if err != nil {
+			handleError(w, prompt, err)
+			return
+		}
+
+		successResponse(w, http.StatusOK, nil)
+	}
+}
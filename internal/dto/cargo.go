@@ -0,0 +1,143 @@
+package dto
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"task/internal/entities"
+)
+
+const maxCargoCodeLength = 64
+
+var loadUnits = map[string]bool{
+	entities.UnitKilogram:   true,
+	entities.UnitTonne:      true,
+	entities.UnitCubicMetre: true,
+	entities.UnitLitre:      true,
+}
+
+// hazardClasses are classes of dangerous goods by ADR. Empty class means non-dangerous cargo.
+var hazardClasses = map[string]bool{
+	"":    true,
+	"1":   true,
+	"2":   true,
+	"3":   true,
+	"4.1": true,
+	"4.2": true,
+	"4.3": true,
+	"5.1": true,
+	"5.2": true,
+	"6.1": true,
+	"6.2": true,
+	"7":   true,
+	"8":   true,
+	"9":   true,
+}
+
+// NormalizeCargoCode brings cargo code or alias to the form it is stored in catalog.
+func NormalizeCargoCode(code string) string {
+	return strings.ToLower(strings.TrimSpace(code))
+}
+
+func ToCargoTypeModel(data CargoTypeRequestBody) (cargoType entities.CargoType, err error) {
+	code := NormalizeCargoCode(data.Code)
+	if code == "" {
+		return entities.CargoType{}, fmt.Errorf("code should not be empty")
+	}
+	if len(code) > maxCargoCodeLength {
+		return entities.CargoType{}, fmt.Errorf("code should not be longer than %d characters", maxCargoCodeLength)
+	}
+
+	displayName := strings.TrimSpace(data.DisplayName)
+	if displayName == "" {
+		return entities.CargoType{}, fmt.Errorf("display name should not be empty")
+	}
+
+	if !loadUnits[data.Unit] {
+		return entities.CargoType{}, fmt.Errorf("unknown unit %q", data.Unit)
+	}
+
+	if math.IsNaN(data.Density) || data.Density < 0 {
+		return entities.CargoType{}, fmt.Errorf("density should be non-negative")
+	}
+	if entities.IsVolumeUnit(data.Unit) && data.Density == 0 {
+		return entities.CargoType{}, fmt.Errorf("density should be set for cargo measured in %s", data.Unit)
+	}
+
+	if !hazardClasses[data.HazardClass] {
+		return entities.CargoType{}, fmt.Errorf("unknown hazard class %q", data.HazardClass)
+	}
+
+	seen := map[string]bool{code: true}
+	aliases := make([]string, 0, len(data.Aliases))
+	for _, alias := range data.Aliases {
+		alias = NormalizeCargoCode(alias)
+		if alias == "" {
+			return entities.CargoType{}, fmt.Errorf("alias should not be empty")
+		}
+		if len(alias) > maxCargoCodeLength {
+			return entities.CargoType{}, fmt.Errorf("alias should not be longer than %d characters", maxCargoCodeLength)
+		}
+		if seen[alias] {
+			return entities.CargoType{}, fmt.Errorf("alias %q is duplicated", alias)
+		}
+		seen[alias] = true
+		aliases = append(aliases, alias)
+	}
+
+	return entities.CargoType{
+		Code:        code,
+		DisplayName: displayName,
+		Unit:        data.Unit,
+		Density:     data.Density,
+		HazardClass: data.HazardClass,
+		Aliases:     aliases,
+	}, nil
+}
+
+// MergeCargoTypeUpdate applies supplied fields of update request on top of existing cargo type.
+// Result should be validated with ToCargoTypeModel.
+func MergeCargoTypeUpdate(cargoType entities.CargoType, data UpdateCargoTypeRequestBody) CargoTypeRequestBody {
+	merged := CargoTypeRequestBody{
+		Code:        cargoType.Code,
+		DisplayName: cargoType.DisplayName,
+		Unit:        cargoType.Unit,
+		Density:     cargoType.Density,
+		HazardClass: cargoType.HazardClass,
+		Aliases:     cargoType.Aliases,
+	}
+
+	if data.DisplayName != nil {
+		merged.DisplayName = *data.DisplayName
+	}
+	if data.Unit != nil {
+		merged.Unit = *data.Unit
+	}
+	if data.Density != nil {
+		merged.Density = *data.Density
+	}
+	if data.HazardClass != nil {
+		merged.HazardClass = *data.HazardClass
+	}
+	if data.Aliases != nil {
+		merged.Aliases = *data.Aliases
+	}
+
+	return merged
+}
+
+func FromCargoTypeModel(cargoType entities.CargoType) CargoTypeResponseBody {
+	aliases := cargoType.Aliases
+	if aliases == nil {
+		aliases = []string{}
+	}
+
+	return CargoTypeResponseBody{
+		Code:        cargoType.Code,
+		DisplayName: cargoType.DisplayName,
+		Unit:        cargoType.Unit,
+		Density:     cargoType.Density,
+		HazardClass: cargoType.HazardClass,
+		Aliases:     aliases,
+	}
+}
@@ -1,24 +1,29 @@
 package dto
 
 import (
+	"encoding/base64"
 	"fmt"
+	"strconv"
+	"strings"
 	"task/internal/entities"
+	"time"
+	"unicode/utf8"
 )
 
-const eps = 1e-6
+// maxRouteNameLength is the size of route_name column in characters
+const maxRouteNameLength = 128
 
-type RegisterRouteRequestBody struct {
-	RouteID   int     `json:"route_id"`
-	RouteName string  `json:"route_name"`
-	Load      float32 `json:"load"`
-	CargoType string  `json:"cargo_type"`
+func ToEntityModel(data RegisterRouteRequestBody) (route entities.Route, err error) {
+	return toEntityModel(data, true)
 }
 
-type DeleteRoutesRequestBody struct {
-	RouteIDs []int `json:"route_ids"`
+// ToUpdatedEntityModel validates route merged by MergeUpdate. Number of waypoints is checked
+// only if update supplies them, since routes registered before waypoints were added have none.
+func ToUpdatedEntityModel(merged RegisterRouteRequestBody, data UpdateRouteRequestBody) (route entities.Route, err error) {
+	return toEntityModel(merged, data.Waypoints != nil)
 }
 
-func ToEntityModel(data RegisterRouteRequestBody) (route entities.Route, err error) {
+func toEntityModel(data RegisterRouteRequestBody, requireWaypoints bool) (route entities.Route, err error) {
 	if data.RouteID < 0 {
 		return entities.Route{}, fmt.Errorf("route id should be non-negative")
 	}
@@ -26,19 +31,255 @@ func ToEntityModel(data RegisterRouteRequestBody) (route entities.Route, err err
 	if data.RouteName == "" {
 		return entities.Route{}, fmt.Errorf("route name should not be empty")
 	}
+	if utf8.RuneCountInString(data.RouteName) > maxRouteNameLength {
+		return entities.Route{}, fmt.Errorf("route name should not be longer than %d characters", maxRouteNameLength)
+	}
 
-	if data.Load < eps {
+	if data.Load.Sign() <= 0 {
 		return entities.Route{}, fmt.Errorf("load should be non-negative")
 	}
 
+	if data.Unit != "" && !loadUnits[data.Unit] {
+		return entities.Route{}, fmt.Errorf("unknown unit %q", data.Unit)
+	}
+
 	if data.CargoType == "" {
 		return entities.Route{}, fmt.Errorf("cargo type should not be empty")
 	}
+	if utf8.RuneCountInString(data.CargoType) > maxCargoCodeLength {
+		return entities.Route{}, fmt.Errorf("cargo type should not be longer than %d characters", maxCargoCodeLength)
+	}
+
+	if requireWaypoints && len(data.Waypoints) < MinWaypoints {
+		return entities.Route{}, fmt.Errorf("route should have at least %d waypoints", MinWaypoints)
+	}
+
+	waypoints, err := toWaypoints(data.Waypoints)
+	if err != nil {
+		return entities.Route{}, err
+	}
 
 	return entities.Route{
 		RouteID:   data.RouteID,
 		RouteName: data.RouteName,
 		Load:      data.Load,
 		CargoType: data.CargoType,
+		Waypoints: waypoints,
 	}, nil
 }
+
+// MergeUpdate applies supplied fields of update request on top of existing route.
+// Result should be validated with ToUpdatedEntityModel.
+func MergeUpdate(route entities.Route, data UpdateRouteRequestBody) RegisterRouteRequestBody {
+	merged := RegisterRouteRequestBody{
+		RouteID:   route.RouteID,
+		RouteName: route.RouteName,
+		Load:      route.Load,
+		Unit:      entities.CanonicalUnit,
+		CargoType: route.CargoType,
+		Waypoints: FromWaypoints(route.Waypoints),
+	}
+
+	if data.RouteName != nil {
+		merged.RouteName = *data.RouteName
+	}
+	if data.Load != nil {
+		merged.Load = *data.Load
+		merged.Unit = ""
+		if data.Unit != nil {
+			merged.Unit = *data.Unit
+		}
+	}
+	if data.CargoType != nil {
+		merged.CargoType = *data.CargoType
+	}
+	if data.Waypoints != nil {
+		merged.Waypoints = *data.Waypoints
+	}
+
+	return merged
+}
+
+func FromEntityModel(route entities.Route) RouteResponseBody {
+	return RouteResponseBody{
+		RouteID:   route.RouteID,
+		RouteName: route.RouteName,
+		Load:      route.Load,
+		Unit:      entities.CanonicalUnit,
+		CargoType: route.CargoType,
+		IsActual:  route.IsActual,
+		Waypoints: FromWaypoints(route.Waypoints),
+		Polyline:  route.Polyline(),
+		DistanceM: route.Distance,
+		DurationS: int64(route.Duration / time.Second),
+		VehicleID: route.VehicleID,
+	}
+}
+
+// ToRouteFilter validates list request and converts it to repository filter.
+// Limit of the returned filter is exactly the page size, without look-ahead row.
+func ToRouteFilter(data ListRoutesRequest) (filter entities.RouteFilter, err error) {
+	if data.Limit < 0 {
+		return entities.RouteFilter{}, fmt.Errorf("limit should be non-negative")
+	}
+	if data.Limit > MaxListLimit {
+		return entities.RouteFilter{}, fmt.Errorf("limit should not be greater than %d", MaxListLimit)
+	}
+
+	if data.MinLoad != nil && data.MinLoad.Sign() < 0 {
+		return entities.RouteFilter{}, fmt.Errorf("min load should be non-negative")
+	}
+	if data.MaxLoad != nil && data.MaxLoad.Sign() < 0 {
+		return entities.RouteFilter{}, fmt.Errorf("max load should be non-negative")
+	}
+	if data.MinLoad != nil && data.MaxLoad != nil && data.MinLoad.Cmp(*data.MaxLoad) > 0 {
+		return entities.RouteFilter{}, fmt.Errorf("min load should not be greater than max load")
+	}
+
+	filter = entities.RouteFilter{
+		Limit:      data.Limit,
+		CargoType:  data.CargoType,
+		IsActual:   data.IsActual,
+		MinLoad:    data.MinLoad,
+		MaxLoad:    data.MaxLoad,
+		NamePrefix: data.NamePrefix,
+		VehicleID:  data.VehicleID,
+	}
+	if filter.Limit == 0 {
+		filter.Limit = DefaultListLimit
+	}
+
+	if data.Cursor != "" {
+		afterID, err := DecodeCursor(data.Cursor)
+		if err != nil {
+			return entities.RouteFilter{}, err
+		}
+		filter.AfterID = &afterID
+	}
+
+	return filter, nil
+}
+
+// ToSearchQuery validates search request and returns trimmed query and limit of results.
+func ToSearchQuery(data SearchRoutesRequest) (query string, limit int, err error) {
+	query = strings.TrimSpace(data.Query)
+	if query == "" {
+		return "", 0, fmt.Errorf("search query should not be empty")
+	}
+	if len(query) > MaxSearchQueryLength {
+		return "", 0, fmt.Errorf("search query should not be longer than %d characters", MaxSearchQueryLength)
+	}
+
+	if data.Limit < 0 {
+		return "", 0, fmt.Errorf("limit should be non-negative")
+	}
+	if data.Limit > MaxListLimit {
+		return "", 0, fmt.Errorf("limit should not be greater than %d", MaxListLimit)
+	}
+	limit = data.Limit
+	if limit == 0 {
+		limit = DefaultListLimit
+	}
+
+	return query, limit, nil
+}
+
+// EncodeCursor returns opaque pagination cursor pointing after given route id.
+func EncodeCursor(routeID int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.Itoa(routeID)))
+}
+
+func DecodeCursor(cursor string) (routeID int, err error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, fmt.Errorf("invalid cursor")
+	}
+
+	routeID, err = strconv.Atoi(string(raw))
+	if err != nil || routeID < 0 {
+		return 0, fmt.Errorf("invalid cursor")
+	}
+
+	return routeID, nil
+}
+
+func FromRouteStatsModel(stats entities.RouteStats) RouteStatsResponseBody {
+	return RouteStatsResponseBody{
+		CargoType: stats.CargoType,
+		IsActual:  stats.IsActual,
+		Count:     stats.Count,
+		TotalLoad: stats.TotalLoad,
+		AvgLoad:   stats.AvgLoad,
+		MinLoad:   stats.MinLoad,
+		MaxLoad:   stats.MaxLoad,
+		Unit:      entities.CanonicalUnit,
+	}
+}
+
+func FromVersionModel(version entities.RouteVersion) RouteVersionResponseBody {
+	return RouteVersionResponseBody{
+		VersionID:    version.VersionID,
+		RouteID:      version.Route.RouteID,
+		RouteName:    version.Route.RouteName,
+		Load:         version.Route.Load,
+		CargoType:    version.Route.CargoType,
+		Waypoints:    FromWaypoints(version.Route.Waypoints),
+		CreatedAt:    version.CreatedAt,
+		SupersededBy: version.SupersededBy,
+		SupersededAt: version.SupersededAt,
+	}
+}
+
+func FromDeleteJobModel(job entities.DeleteJob) DeleteJobResponseBody {
+	items := make([]DeleteJobItemResponseBody, 0, len(job.Items))
+	for _, item := range job.Items {
+		items = append(items, DeleteJobItemResponseBody{
+			RouteID: item.RouteID,
+			Status:  string(item.Status),
+		})
+	}
+
+	return DeleteJobResponseBody{
+		JobID:     job.JobID,
+		Status:    string(job.Status),
+		Attempts:  job.Attempts,
+		Error:     job.Error,
+		Items:     items,
+		CreatedAt: job.CreatedAt,
+		UpdatedAt: job.UpdatedAt,
+	}
+}
+
+func FromRegisterResults(results []entities.RegisterResult) []RegisterBatchItemResponseBody {
+	items := make([]RegisterBatchItemResponseBody, 0, len(results))
+	for i, res := range results {
+		item := RegisterBatchItemResponseBody{
+			Index:    i,
+			RouteID:  res.RouteID,
+			Reissued: res.Reissued,
+		}
+		if res.Err != nil {
+			item.Error = res.Err.Error()
+		}
+		items = append(items, item)
+	}
+
+	return items
+}
+
+func FromImportReport(report entities.ImportReport) ImportReportResponseBody {
+	rejected := make([]RejectedRowResponseBody, 0, len(report.Rejected))
+	for _, row := range report.Rejected {
+		rejected = append(rejected, RejectedRowResponseBody{
+			Line:   row.Line,
+			Reason: row.Reason,
+		})
+	}
+
+	return ImportReportResponseBody{
+		Total:    report.Total,
+		Imported: report.Imported,
+		Reissued: report.Reissued,
+		Rejected: rejected,
+	}
+}
@@ -0,0 +1,148 @@
+package dto
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"task/internal/entities"
+	"time"
+)
+
+// RouteWriter writes routes of export file one by one. Prefix of the document is written
+// with the first route, so nothing is written if export fails before it.
+type RouteWriter interface {
+	Write(route entities.Route) error
+	// Close completes the document; it should be called after the last route.
+	Close() error
+}
+
+func NewRouteWriter(w io.Writer, format string) (RouteWriter, error) {
+	switch format {
+	case FormatCSV:
+		return &csvRouteWriter{w: csv.NewWriter(w)}, nil
+	case FormatJSONL:
+		return &jsonlRouteWriter{enc: json.NewEncoder(w)}, nil
+	case FormatGeoJSON:
+		return &geoJSONRouteWriter{w: w}, nil
+	default:
+		return nil, fmt.Errorf("unknown export format %q", format)
+	}
+}
+
+// ExportContentType returns media type of export file in given format.
+func ExportContentType(format string) string {
+	switch format {
+	case FormatCSV:
+		return "text/csv"
+	case FormatJSONL:
+		return "application/x-ndjson"
+	default:
+		return "application/geo+json"
+	}
+}
+
+type csvRouteWriter struct {
+	w       *csv.Writer
+	started bool
+}
+
+func (e *csvRouteWriter) begin() error {
+	if e.started {
+		return nil
+	}
+	e.started = true
+
+	return e.w.Write([]string{"route_id", "route_name", "load", "unit", "cargo_type", "is_actual", "waypoints", "distance_m", "duration_s"})
+}
+
+func (e *csvRouteWriter) Write(route entities.Route) error {
+	err := e.begin()
+	if err != nil {
+		return err
+	}
+
+	waypoints, err := json.Marshal(FromWaypoints(route.Waypoints))
+	if err != nil {
+		return err
+	}
+
+	return e.w.Write([]string{
+		strconv.Itoa(route.RouteID),
+		route.RouteName,
+		route.Load.String(),
+		entities.CanonicalUnit,
+		route.CargoType,
+		strconv.FormatBool(route.IsActual),
+		string(waypoints),
+		strconv.FormatFloat(route.Distance, 'f', -1, 64),
+		strconv.FormatInt(int64(route.Duration/time.Second), 10),
+	})
+}
+
+func (e *csvRouteWriter) Close() error {
+	err := e.begin()
+	if err != nil {
+		return err
+	}
+
+	e.w.Flush()
+	return e.w.Error()
+}
+
+type jsonlRouteWriter struct {
+	enc *json.Encoder
+}
+
+func (e *jsonlRouteWriter) Write(route entities.Route) error {
+	return e.enc.Encode(FromEntityModel(route))
+}
+
+func (e *jsonlRouteWriter) Close() error {
+	return nil
+}
+
+// geoJSONRouteWriter writes FeatureCollection with a feature per route.
+type geoJSONRouteWriter struct {
+	w       io.Writer
+	started bool
+}
+
+func (e *geoJSONRouteWriter) begin() error {
+	if e.started {
+		_, err := io.WriteString(e.w, ",")
+		return err
+	}
+	e.started = true
+
+	_, err := io.WriteString(e.w, `{"type":"FeatureCollection","features":[`)
+	return err
+}
+
+func (e *geoJSONRouteWriter) Write(route entities.Route) error {
+	err := e.begin()
+	if err != nil {
+		return err
+	}
+
+	feature, err := json.Marshal(ToGeoJSONFeature(route))
+	if err != nil {
+		return err
+	}
+
+	_, err = e.w.Write(feature)
+	return err
+}
+
+func (e *geoJSONRouteWriter) Close() error {
+	if !e.started {
+		_, err := io.WriteString(e.w, `{"type":"FeatureCollection","features":[`)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err := io.WriteString(e.w, "]}\n")
+	return err
+}
@@ -0,0 +1,123 @@
+package dto
+
+import (
+	"fmt"
+	"math"
+	"task/internal/entities"
+)
+
+var stopTypes = map[string]bool{
+	"":                   true,
+	entities.StopDepot:   true,
+	entities.StopPickup:  true,
+	entities.StopDropoff: true,
+	entities.StopRest:    true,
+}
+
+func toWaypoints(data []WaypointBody) (waypoints []entities.Waypoint, err error) {
+	waypoints = make([]entities.Waypoint, 0, len(data))
+	for i, wp := range data {
+		err = validateCoordinates(wp.Lat, wp.Lon)
+		if err != nil {
+			return nil, fmt.Errorf("waypoint #%d: %w", i, err)
+		}
+		if !stopTypes[wp.StopType] {
+			return nil, fmt.Errorf("waypoint #%d: unknown stop type %q", i, wp.StopType)
+		}
+
+		waypoints = append(waypoints, entities.Waypoint{
+			Lat:      wp.Lat,
+			Lon:      wp.Lon,
+			StopName: wp.StopName,
+			StopType: wp.StopType,
+		})
+	}
+
+	return waypoints, nil
+}
+
+func validateCoordinates(lat, lon float64) error {
+	if math.IsNaN(lat) || lat < -90 || lat > 90 {
+		return fmt.Errorf("latitude should be in range [-90, 90]")
+	}
+	if math.IsNaN(lon) || lon < -180 || lon > 180 {
+		return fmt.Errorf("longitude should be in range [-180, 180]")
+	}
+
+	return nil
+}
+
+// ToNearQuery validates near request and returns its center point and radius in meters.
+func ToNearQuery(data NearRequest) (point entities.Waypoint, radius float64, err error) {
+	err = validateCoordinates(data.Lat, data.Lon)
+	if err != nil {
+		return entities.Waypoint{}, 0, err
+	}
+
+	if math.IsNaN(data.RadiusM) || data.RadiusM <= 0 {
+		return entities.Waypoint{}, 0, fmt.Errorf("radius should be positive")
+	}
+	if data.RadiusM > MaxNearRadius {
+		return entities.Waypoint{}, 0, fmt.Errorf("radius should not be greater than %d meters", MaxNearRadius)
+	}
+
+	return entities.Waypoint{Lat: data.Lat, Lon: data.Lon}, data.RadiusM, nil
+}
+
+func ToBBox(data WithinRequest) (box entities.BBox, err error) {
+	err = validateCoordinates(data.MinLat, data.MinLon)
+	if err != nil {
+		return entities.BBox{}, fmt.Errorf("bbox min corner: %w", err)
+	}
+	err = validateCoordinates(data.MaxLat, data.MaxLon)
+	if err != nil {
+		return entities.BBox{}, fmt.Errorf("bbox max corner: %w", err)
+	}
+
+	if data.MinLat > data.MaxLat || data.MinLon > data.MaxLon {
+		return entities.BBox{}, fmt.Errorf("bbox min corner should not be greater than max corner")
+	}
+
+	return entities.BBox{
+		MinLat: data.MinLat,
+		MinLon: data.MinLon,
+		MaxLat: data.MaxLat,
+		MaxLon: data.MaxLon,
+	}, nil
+}
+
+func FromWaypoints(waypoints []entities.Waypoint) []WaypointBody {
+	data := make([]WaypointBody, 0, len(waypoints))
+	for _, wp := range waypoints {
+		data = append(data, WaypointBody{
+			Lat:      wp.Lat,
+			Lon:      wp.Lon,
+			StopName: wp.StopName,
+			StopType: wp.StopType,
+		})
+	}
+
+	return data
+}
+
+// ToGeoJSONFeature represents route as LineString feature. Routes without waypoints
+// get null geometry.
+func ToGeoJSONFeature(route entities.Route) GeoJSONFeature {
+	feature := GeoJSONFeature{
+		Type:       "Feature",
+		Properties: FromEntityModel(route),
+	}
+
+	if len(route.Waypoints) > 0 {
+		coords := make([][2]float64, 0, len(route.Waypoints))
+		for _, wp := range route.Waypoints {
+			coords = append(coords, [2]float64{wp.Lon, wp.Lat})
+		}
+		feature.Geometry = &GeoJSONGeometry{
+			Type:        "LineString",
+			Coordinates: coords,
+		}
+	}
+
+	return feature
+}
@@ -0,0 +1,166 @@
+package dto
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"task/pkg/decimal"
+)
+
+const maxJSONLLineSize = 1 << 20
+
+// csvColumns are required columns of csv import file. Optional "unit" column sets unit of load,
+// optional "waypoints" column holds waypoints as JSON array.
+var csvColumns = []string{"route_id", "route_name", "load", "cargo_type"}
+
+// FormatFromFileName guesses import format from file extension, it returns empty string if unknown.
+func FormatFromFileName(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return FormatCSV
+	case ".jsonl", ".ndjson":
+		return FormatJSONL
+	default:
+		return ""
+	}
+}
+
+// RouteReader reads routes one by one from import file.
+type RouteReader interface {
+	// Next returns next route and number of line it starts at. Errors of a single row are
+	// returned as *RowError, reading can be continued after them. At the end of input io.EOF is returned.
+	Next() (line int, data RegisterRouteRequestBody, err error)
+}
+
+// RowError describes malformed row of import file.
+type RowError struct {
+	Line int
+	Err  error
+}
+
+func (e *RowError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+func (e *RowError) Unwrap() error {
+	return e.Err
+}
+
+func NewRouteReader(r io.Reader, format string) (RouteReader, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVRouteReader(r)
+	case FormatJSONL:
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 0, 64*1024), maxJSONLLineSize)
+		return &jsonlRouteReader{scanner: scanner}, nil
+	default:
+		return nil, fmt.Errorf("unknown import format %q", format)
+	}
+}
+
+type csvRouteReader struct {
+	reader  *csv.Reader
+	columns map[string]int
+}
+
+func newCSVRouteReader(r io.Reader) (*csvRouteReader, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("reading csv header: %w", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.TrimSpace(name)] = i
+	}
+	for _, name := range csvColumns {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("csv header: missing column %q", name)
+		}
+	}
+
+	return &csvRouteReader{reader: reader, columns: columns}, nil
+}
+
+func (r *csvRouteReader) Next() (line int, data RegisterRouteRequestBody, err error) {
+	record, err := r.reader.Read()
+	if err != nil {
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			return parseErr.StartLine, RegisterRouteRequestBody{}, &RowError{Line: parseErr.StartLine, Err: parseErr.Err}
+		}
+		return 0, RegisterRouteRequestBody{}, err
+	}
+	line, _ = r.reader.FieldPos(0)
+
+	field := func(name string) string {
+		idx, ok := r.columns[name]
+		if !ok || idx >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[idx])
+	}
+
+	data.RouteID, err = strconv.Atoi(field("route_id"))
+	if err != nil {
+		return line, RegisterRouteRequestBody{}, &RowError{Line: line, Err: fmt.Errorf("parsing route_id: %w", err)}
+	}
+
+	data.Load, err = decimal.Parse(field("load"))
+	if err != nil {
+		return line, RegisterRouteRequestBody{}, &RowError{Line: line, Err: fmt.Errorf("parsing load: %w", err)}
+	}
+
+	data.RouteName = field("route_name")
+	data.Unit = field("unit")
+	data.CargoType = field("cargo_type")
+
+	if raw := field("waypoints"); raw != "" {
+		err = json.Unmarshal([]byte(raw), &data.Waypoints)
+		if err != nil {
+			return line, RegisterRouteRequestBody{}, &RowError{Line: line, Err: fmt.Errorf("parsing waypoints: %w", err)}
+		}
+	}
+
+	return line, data, nil
+}
+
+type jsonlRouteReader struct {
+	scanner *bufio.Scanner
+	line    int
+}
+
+func (r *jsonlRouteReader) Next() (line int, data RegisterRouteRequestBody, err error) {
+	for r.scanner.Scan() {
+		r.line++
+
+		raw := strings.TrimSpace(r.scanner.Text())
+		if raw == "" {
+			continue
+		}
+
+		err = json.Unmarshal([]byte(raw), &data)
+		if err != nil {
+			return r.line, RegisterRouteRequestBody{}, &RowError{Line: r.line, Err: err}
+		}
+
+		return r.line, data, nil
+	}
+
+	if err = r.scanner.Err(); err != nil {
+		return 0, RegisterRouteRequestBody{}, fmt.Errorf("line %d: %w", r.line+1, err)
+	}
+
+	return 0, RegisterRouteRequestBody{}, io.EOF
+}
@@ -0,0 +1,246 @@
+package dto
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"task/internal/entities"
+	"time"
+)
+
+// maxScheduleHours limits window offsets to a week after midnight of the trip day
+const maxScheduleHours = 7 * 24
+
+func ToScheduleModel(routeID int, data ScheduleRequestBody) (schedule entities.Schedule, err error) {
+	departure, err := toTimeWindow(data.Departure)
+	if err != nil {
+		return entities.Schedule{}, fmt.Errorf("departure %w", err)
+	}
+	arrival, err := toTimeWindow(data.Arrival)
+	if err != nil {
+		return entities.Schedule{}, fmt.Errorf("arrival %w", err)
+	}
+
+	if departure.From >= 24*time.Hour {
+		return entities.Schedule{}, fmt.Errorf("departure window should start before 24:00")
+	}
+	if arrival.From < departure.From {
+		return entities.Schedule{}, fmt.Errorf("arrival window should not start before departure window")
+	}
+	if arrival.To < departure.To {
+		return entities.Schedule{}, fmt.Errorf("arrival window should not end before departure window")
+	}
+
+	recurrence, err := entities.ParseRecurrence(data.Recurrence)
+	if err != nil {
+		return entities.Schedule{}, err
+	}
+
+	timezone := data.Timezone
+	if timezone == "" {
+		timezone = DefaultTimezone
+	}
+	// local zone of the server is not a stable part of schedule
+	if _, err = time.LoadLocation(timezone); err != nil || timezone == "Local" {
+		return entities.Schedule{}, fmt.Errorf("unknown time zone %q", data.Timezone)
+	}
+
+	validFrom, err := parseOptionalDate(data.ValidFrom)
+	if err != nil {
+		return entities.Schedule{}, fmt.Errorf("parsing valid from: %w", err)
+	}
+	validUntil, err := parseOptionalDate(data.ValidUntil)
+	if err != nil {
+		return entities.Schedule{}, fmt.Errorf("parsing valid until: %w", err)
+	}
+	if validFrom != nil && validUntil != nil && validFrom.After(*validUntil) {
+		return entities.Schedule{}, fmt.Errorf("valid from should not be after valid until")
+	}
+
+	return entities.Schedule{
+		RouteID:    routeID,
+		Departure:  departure,
+		Arrival:    arrival,
+		Recurrence: recurrence,
+		Timezone:   timezone,
+		ValidFrom:  validFrom,
+		ValidUntil: validUntil,
+	}, nil
+}
+
+func toTimeWindow(data TimeWindowBody) (window entities.TimeWindow, err error) {
+	window.From, err = parseTimeOfDay(data.From)
+	if err != nil {
+		return entities.TimeWindow{}, fmt.Errorf("window start: %w", err)
+	}
+	window.To, err = parseTimeOfDay(data.To)
+	if err != nil {
+		return entities.TimeWindow{}, fmt.Errorf("window end: %w", err)
+	}
+
+	if window.To < window.From {
+		return entities.TimeWindow{}, fmt.Errorf("window should not end before it starts")
+	}
+
+	return window, nil
+}
+
+// parseTimeOfDay parses "HH:MM" into offset from midnight.
+func parseTimeOfDay(s string) (time.Duration, error) {
+	hh, mm, ok := strings.Cut(s, ":")
+	if !ok || len(mm) != 2 || hh == "" || len(hh) > 3 {
+		return 0, fmt.Errorf("invalid time %q, expected HH:MM", s)
+	}
+
+	hours, err := strconv.Atoi(hh)
+	if err != nil || hours < 0 || hours >= maxScheduleHours {
+		return 0, fmt.Errorf("invalid time %q, hours should be in range 0-%d", s, maxScheduleHours-1)
+	}
+	minutes, err := strconv.Atoi(mm)
+	if err != nil || minutes < 0 || minutes > 59 {
+		return 0, fmt.Errorf("invalid time %q, minutes should be in range 00-59", s)
+	}
+
+	return time.Duration(hours)*time.Hour + time.Duration(minutes)*time.Minute, nil
+}
+
+func formatTimeOfDay(d time.Duration) string {
+	return fmt.Sprintf("%02d:%02d", int(d/time.Hour), int(d%time.Hour/time.Minute))
+}
+
+func parseOptionalDate(s string) (*time.Time, error) {
+	if s == "" {
+		return nil, nil
+	}
+
+	date, err := time.Parse(time.DateOnly, s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid date %q, expected YYYY-MM-DD", s)
+	}
+
+	return &date, nil
+}
+
+func formatOptionalDate(date *time.Time) string {
+	if date == nil {
+		return ""
+	}
+	return date.Format(time.DateOnly)
+}
+
+func FromScheduleModel(schedule entities.Schedule) ScheduleResponseBody {
+	return ScheduleResponseBody{
+		RouteID: schedule.RouteID,
+		Departure: TimeWindowBody{
+			From: formatTimeOfDay(schedule.Departure.From),
+			To:   formatTimeOfDay(schedule.Departure.To),
+		},
+		Arrival: TimeWindowBody{
+			From: formatTimeOfDay(schedule.Arrival.From),
+			To:   formatTimeOfDay(schedule.Arrival.To),
+		},
+		Recurrence: schedule.Recurrence.String(),
+		Timezone:   schedule.Timezone,
+		ValidFrom:  formatOptionalDate(schedule.ValidFrom),
+		ValidUntil: formatOptionalDate(schedule.ValidUntil),
+	}
+}
+
+// ToTripRange validates trips request and returns its first and last dates.
+func ToTripRange(data TripsRequest) (first, last time.Time, err error) {
+	if data.From == "" || data.To == "" {
+		return time.Time{}, time.Time{}, fmt.Errorf("from and to dates should be set")
+	}
+
+	first, err = time.Parse(time.DateOnly, data.From)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid from date %q, expected YYYY-MM-DD", data.From)
+	}
+	last, err = time.Parse(time.DateOnly, data.To)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid to date %q, expected YYYY-MM-DD", data.To)
+	}
+
+	if last.Before(first) {
+		return time.Time{}, time.Time{}, fmt.Errorf("to date should not be before from date")
+	}
+	if last.Sub(first) >= MaxTripRangeDays*24*time.Hour {
+		return time.Time{}, time.Time{}, fmt.Errorf("date range should not be longer than %d days", MaxTripRangeDays)
+	}
+
+	if data.RouteID != nil && *data.RouteID < 0 {
+		return time.Time{}, time.Time{}, fmt.Errorf("route id should be non-negative")
+	}
+
+	return first, last, nil
+}
+
+// ToTripPage validates paging of trips request. after is nil for the first page.
+func ToTripPage(data TripsRequest) (limit int, after *entities.Trip, err error) {
+	if data.Limit < 0 {
+		return 0, nil, fmt.Errorf("limit should be non-negative")
+	}
+	if data.Limit > MaxListLimit {
+		return 0, nil, fmt.Errorf("limit should not be greater than %d", MaxListLimit)
+	}
+	limit = data.Limit
+	if limit == 0 {
+		limit = DefaultListLimit
+	}
+
+	if data.Cursor != "" {
+		trip, err := DecodeTripCursor(data.Cursor)
+		if err != nil {
+			return 0, nil, err
+		}
+		after = &trip
+	}
+
+	return limit, after, nil
+}
+
+// EncodeTripCursor returns opaque pagination cursor pointing after given trip. Trips are ordered
+// by departure and route id, so only these fields are kept.
+func EncodeTripCursor(trip entities.Trip) string {
+	raw := fmt.Sprintf("%d:%d", trip.Departure.From.UnixNano(), trip.RouteID)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func DecodeTripCursor(cursor string) (trip entities.Trip, err error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return entities.Trip{}, fmt.Errorf("invalid cursor")
+	}
+
+	departure, routeID, ok := strings.Cut(string(raw), ":")
+	if !ok {
+		return entities.Trip{}, fmt.Errorf("invalid cursor")
+	}
+	nanos, err := strconv.ParseInt(departure, 10, 64)
+	if err != nil {
+		return entities.Trip{}, fmt.Errorf("invalid cursor")
+	}
+	trip.RouteID, err = strconv.Atoi(routeID)
+	if err != nil || trip.RouteID < 0 {
+		return entities.Trip{}, fmt.Errorf("invalid cursor")
+	}
+	trip.Departure.From = time.Unix(0, nanos).UTC()
+
+	return trip, nil
+}
+
+func FromTripModel(trip entities.Trip) TripResponseBody {
+	return TripResponseBody{
+		RouteID: trip.RouteID,
+		Date:    trip.Date.Format(time.DateOnly),
+		Departure: IntervalBody{
+			From: trip.Departure.From,
+			To:   trip.Departure.To,
+		},
+		Arrival: IntervalBody{
+			From: trip.Arrival.From,
+			To:   trip.Arrival.To,
+		},
+	}
+}
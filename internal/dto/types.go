@@ -0,0 +1,64 @@
+// Package dto validates request bodies of the API and converts them to entities and back.
+// Bodies themselves are public in task/pkg/dto, they are aliased here so that handlers
+// and services use one package.
+package dto
+
+import (
+	api "task/pkg/dto"
+)
+
+const (
+	DefaultListLimit     = api.DefaultListLimit
+	MaxListLimit         = api.MaxListLimit
+	MaxBatchSize         = api.MaxBatchSize
+	MaxSearchQueryLength = api.MaxSearchQueryLength
+	MinWaypoints         = api.MinWaypoints
+	MaxNearRadius        = api.MaxNearRadius
+	MaxTripRangeDays     = api.MaxTripRangeDays
+	DefaultTimezone      = api.DefaultTimezone
+
+	FormatCSV     = api.FormatCSV
+	FormatJSONL   = api.FormatJSONL
+	FormatGeoJSON = api.FormatGeoJSON
+)
+
+type (
+	RegisterRouteRequestBody      = api.RegisterRouteRequestBody
+	RegisterBatchRequestBody      = api.RegisterBatchRequestBody
+	RegisterBatchItemResponseBody = api.RegisterBatchItemResponseBody
+	ImportReportResponseBody      = api.ImportReportResponseBody
+	RejectedRowResponseBody       = api.RejectedRowResponseBody
+	UpdateRouteRequestBody        = api.UpdateRouteRequestBody
+	DeleteRoutesRequestBody       = api.DeleteRoutesRequestBody
+	ListRoutesRequest             = api.ListRoutesRequest
+	SearchRoutesRequest           = api.SearchRoutesRequest
+	RouteResponseBody             = api.RouteResponseBody
+	ListRoutesResponseBody        = api.ListRoutesResponseBody
+	RouteStatsResponseBody        = api.RouteStatsResponseBody
+	RouteVersionResponseBody      = api.RouteVersionResponseBody
+	DeleteJobResponseBody         = api.DeleteJobResponseBody
+	DeleteJobItemResponseBody     = api.DeleteJobItemResponseBody
+
+	WaypointBody    = api.WaypointBody
+	NearRequest     = api.NearRequest
+	WithinRequest   = api.WithinRequest
+	GeoJSONFeature  = api.GeoJSONFeature
+	GeoJSONGeometry = api.GeoJSONGeometry
+
+	CargoTypeRequestBody       = api.CargoTypeRequestBody
+	UpdateCargoTypeRequestBody = api.UpdateCargoTypeRequestBody
+	CargoTypeResponseBody      = api.CargoTypeResponseBody
+
+	TimeWindowBody       = api.TimeWindowBody
+	ScheduleRequestBody  = api.ScheduleRequestBody
+	ScheduleResponseBody = api.ScheduleResponseBody
+	TripsRequest         = api.TripsRequest
+	IntervalBody         = api.IntervalBody
+	TripResponseBody     = api.TripResponseBody
+	TripsResponseBody    = api.TripsResponseBody
+
+	VehicleRequestBody       = api.VehicleRequestBody
+	UpdateVehicleRequestBody = api.UpdateVehicleRequestBody
+	VehicleResponseBody      = api.VehicleResponseBody
+	AssignVehicleRequestBody = api.AssignVehicleRequestBody
+)
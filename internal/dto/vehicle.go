@@ -0,0 +1,105 @@
+package dto
+
+import (
+	"fmt"
+	"strings"
+	"task/internal/entities"
+)
+
+const maxVehicleNameLength = 128
+
+var vehicleStatuses = map[entities.VehicleStatus]bool{
+	entities.VehicleAvailable:   true,
+	entities.VehicleMaintenance: true,
+	entities.VehicleRetired:     true,
+}
+
+// ToVehicleModel validates vehicle. Cargo types are normalized, but it is up to caller
+// to check that they exist in catalog.
+func ToVehicleModel(data VehicleRequestBody) (vehicle entities.Vehicle, err error) {
+	name := strings.TrimSpace(data.Name)
+	if name == "" {
+		return entities.Vehicle{}, fmt.Errorf("name should not be empty")
+	}
+	if len(name) > maxVehicleNameLength {
+		return entities.Vehicle{}, fmt.Errorf("name should not be longer than %d characters", maxVehicleNameLength)
+	}
+
+	if data.Capacity.Sign() <= 0 {
+		return entities.Vehicle{}, fmt.Errorf("capacity should be positive")
+	}
+
+	if len(data.CargoTypes) == 0 {
+		return entities.Vehicle{}, fmt.Errorf("cargo types should not be empty")
+	}
+	seen := make(map[string]bool, len(data.CargoTypes))
+	cargoTypes := make([]string, 0, len(data.CargoTypes))
+	for _, cargoType := range data.CargoTypes {
+		cargoType = NormalizeCargoCode(cargoType)
+		if cargoType == "" {
+			return entities.Vehicle{}, fmt.Errorf("cargo type should not be empty")
+		}
+		if seen[cargoType] {
+			return entities.Vehicle{}, fmt.Errorf("cargo type %q is duplicated", cargoType)
+		}
+		seen[cargoType] = true
+		cargoTypes = append(cargoTypes, cargoType)
+	}
+
+	status := entities.VehicleStatus(data.Status)
+	if status == "" {
+		status = entities.VehicleAvailable
+	}
+	if !vehicleStatuses[status] {
+		return entities.Vehicle{}, fmt.Errorf("unknown vehicle status %q", data.Status)
+	}
+
+	return entities.Vehicle{
+		Name:       name,
+		Capacity:   data.Capacity,
+		CargoTypes: cargoTypes,
+		Status:     status,
+	}, nil
+}
+
+// MergeVehicleUpdate applies supplied fields of update request on top of existing vehicle.
+// Result should be validated with ToVehicleModel.
+func MergeVehicleUpdate(vehicle entities.Vehicle, data UpdateVehicleRequestBody) VehicleRequestBody {
+	merged := VehicleRequestBody{
+		Name:       vehicle.Name,
+		Capacity:   vehicle.Capacity,
+		CargoTypes: vehicle.CargoTypes,
+		Status:     string(vehicle.Status),
+	}
+
+	if data.Name != nil {
+		merged.Name = *data.Name
+	}
+	if data.Capacity != nil {
+		merged.Capacity = *data.Capacity
+	}
+	if data.CargoTypes != nil {
+		merged.CargoTypes = *data.CargoTypes
+	}
+	if data.Status != nil {
+		merged.Status = *data.Status
+	}
+
+	return merged
+}
+
+func FromVehicleModel(vehicle entities.Vehicle) VehicleResponseBody {
+	cargoTypes := vehicle.CargoTypes
+	if cargoTypes == nil {
+		cargoTypes = []string{}
+	}
+
+	return VehicleResponseBody{
+		VehicleID:  vehicle.VehicleID,
+		Name:       vehicle.Name,
+		Capacity:   vehicle.Capacity,
+		Unit:       entities.CanonicalUnit,
+		CargoTypes: cargoTypes,
+		Status:     string(vehicle.Status),
+	}
+}
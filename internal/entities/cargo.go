@@ -0,0 +1,91 @@
+package entities
+
+import (
+	"fmt"
+	"task/pkg/decimal"
+)
+
+// Units of load measurement. Loads of routes are stored in CanonicalUnit.
+const (
+	UnitKilogram   = "kg"
+	UnitTonne      = "t"
+	UnitCubicMetre = "m3"
+	UnitLitre      = "l"
+
+	CanonicalUnit = UnitKilogram
+)
+
+// massUnits holds kilograms per unit, volumeUnits holds cubic metres per unit.
+var (
+	massUnits = map[string]decimal.Decimal{
+		UnitKilogram: decimal.New(1, 0),
+		UnitTonne:    decimal.New(1000, 0),
+	}
+	volumeUnits = map[string]decimal.Decimal{
+		UnitCubicMetre: decimal.New(1, 0),
+		UnitLitre:      decimal.New(1, 3),
+	}
+)
+
+// IsVolumeUnit reports whether unit measures volume, so converting it to mass requires density.
+func IsVolumeUnit(unit string) bool {
+	_, ok := volumeUnits[unit]
+	return ok
+}
+
+// CargoType is an entry of cargo catalog. Routes refer to cargo types by code.
+type CargoType struct {
+	Code        string
+	DisplayName string
+	// Unit is a default unit of load of this cargo
+	Unit string
+	// Density is mass of cubic metre of cargo in kilograms, zero if unknown.
+	// It is required to convert load between volume and mass units.
+	Density float64
+	// HazardClass is ADR class of dangerous goods, empty for non-dangerous cargo
+	HazardClass string
+	// Aliases are alternative names resolved to Code on route registration
+	Aliases []string
+}
+
+// ToCanonical converts load of this cargo measured in unit to CanonicalUnit. Result is exact,
+// it is up to caller to round it.
+func (c CargoType) ToCanonical(load decimal.Decimal, unit string) (decimal.Decimal, error) {
+	kgPerUnit, err := c.kilogramsPer(unit)
+	if err != nil {
+		return decimal.Decimal{}, err
+	}
+
+	return load.Mul(kgPerUnit)
+}
+
+// FromCanonical converts load of this cargo measured in CanonicalUnit to unit
+// rounding it to scale fractional digits.
+func (c CargoType) FromCanonical(load decimal.Decimal, unit string, scale int32) (decimal.Decimal, error) {
+	kgPerUnit, err := c.kilogramsPer(unit)
+	if err != nil {
+		return decimal.Decimal{}, err
+	}
+
+	return load.Div(kgPerUnit, scale)
+}
+
+func (c CargoType) kilogramsPer(unit string) (decimal.Decimal, error) {
+	if kg, ok := massUnits[unit]; ok {
+		return kg, nil
+	}
+
+	m3, ok := volumeUnits[unit]
+	if !ok {
+		return decimal.Decimal{}, fmt.Errorf("unknown unit %q", unit)
+	}
+	if c.Density <= 0 {
+		return decimal.Decimal{}, fmt.Errorf("cargo type %q has no density to convert %s to mass", c.Code, unit)
+	}
+
+	density, err := decimal.FromFloat(c.Density)
+	if err != nil {
+		return decimal.Decimal{}, err
+	}
+	return m3.Mul(density)
+}
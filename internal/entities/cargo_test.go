@@ -0,0 +1,87 @@
+package entities
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"task/pkg/decimal"
+	"testing"
+)
+
+func TestCargoTypeConversion(t *testing.T) {
+	petrol := CargoType{Code: "petrol", Unit: UnitLitre, Density: 750}
+	sand := CargoType{Code: "sand", Unit: UnitTonne}
+
+	testCases := []struct {
+		name      string
+		cargoType CargoType
+		load      string
+		unit      string
+		expected  string
+		err       error
+	}{
+		{
+			name:      "tonnes",
+			cargoType: sand,
+			load:      "2.5",
+			unit:      UnitTonne,
+			expected:  "2500",
+		},
+		{
+			name:      "exact tonnes",
+			cargoType: sand,
+			load:      "12.1",
+			unit:      UnitTonne,
+			expected:  "12100",
+		},
+		{
+			name:      "kilograms",
+			cargoType: sand,
+			load:      "10",
+			unit:      UnitKilogram,
+			expected:  "10",
+		},
+		{
+			name:      "litres",
+			cargoType: petrol,
+			load:      "1000",
+			unit:      UnitLitre,
+			expected:  "750",
+		},
+		{
+			name:      "cubic metres",
+			cargoType: petrol,
+			load:      "2",
+			unit:      UnitCubicMetre,
+			expected:  "1500",
+		},
+		{
+			name:      "volume without density",
+			cargoType: sand,
+			load:      "1",
+			unit:      UnitCubicMetre,
+			err:       fmt.Errorf(`cargo type "sand" has no density to convert m3 to mass`),
+		},
+		{
+			name:      "unknown unit",
+			cargoType: sand,
+			load:      "1",
+			unit:      "lb",
+			err:       fmt.Errorf(`unknown unit "lb"`),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			canonical, err := tc.cargoType.ToCanonical(decimal.MustParse(tc.load), tc.unit)
+			if tc.err != nil {
+				require.Equal(t, tc.err.Error(), err.Error())
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, decimal.MustParse(tc.expected), canonical)
+
+			load, err := tc.cargoType.FromCanonical(canonical, tc.unit, 3)
+			require.Nil(t, err)
+			require.Equal(t, decimal.MustParse(tc.load), load)
+		})
+	}
+}
@@ -0,0 +1,185 @@
+package entities
+
+import (
+	"math"
+	"strings"
+)
+
+// Stop types of waypoints. Empty stop type means the point only shapes the route.
+const (
+	StopDepot   = "depot"
+	StopPickup  = "pickup"
+	StopDropoff = "dropoff"
+	StopRest    = "rest"
+)
+
+// Waypoint is a point of the route in WGS 84 coordinates.
+type Waypoint struct {
+	Lat      float64
+	Lon      float64
+	StopName string
+	StopType string
+}
+
+// Polyline encodes route waypoints with Encoded Polyline Algorithm Format (precision 5).
+func (r Route) Polyline() string {
+	var (
+		sb               strings.Builder
+		prevLat, prevLon int64
+	)
+	for _, wp := range r.Waypoints {
+		lat := int64(math.Round(wp.Lat * 1e5))
+		lon := int64(math.Round(wp.Lon * 1e5))
+		encodePolylineValue(&sb, lat-prevLat)
+		encodePolylineValue(&sb, lon-prevLon)
+		prevLat, prevLon = lat, lon
+	}
+
+	return sb.String()
+}
+
+func encodePolylineValue(sb *strings.Builder, v int64) {
+	v <<= 1
+	if v < 0 {
+		v = ^v
+	}
+	for v >= 0x20 {
+		sb.WriteByte(byte((0x20 | (v & 0x1f)) + 63))
+		v >>= 5
+	}
+	sb.WriteByte(byte(v + 63))
+}
+
+// EarthRadius is mean radius of the Earth in meters.
+const EarthRadius = 6371008.8
+
+// Haversine returns great-circle distance between two points in meters.
+func Haversine(a, b Waypoint) float64 {
+	lat1, lat2 := a.Lat*math.Pi/180, b.Lat*math.Pi/180
+	dLat := lat2 - lat1
+	dLon := (b.Lon - a.Lon) * math.Pi / 180
+
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * EarthRadius * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+// Length returns great-circle length of the route through all its waypoints in meters.
+func (r Route) Length() float64 {
+	var length float64
+	for i := 1; i < len(r.Waypoints); i++ {
+		length += Haversine(r.Waypoints[i-1], r.Waypoints[i])
+	}
+
+	return length
+}
+
+// BBox is a rectangle in WGS 84 coordinates. Boxes crossing the antimeridian are not supported.
+type BBox struct {
+	MinLat float64
+	MinLon float64
+	MaxLat float64
+	MaxLon float64
+}
+
+func (b BBox) Contains(wp Waypoint) bool {
+	return wp.Lat >= b.MinLat && wp.Lat <= b.MaxLat && wp.Lon >= b.MinLon && wp.Lon <= b.MaxLon
+}
+
+// BBoxAround returns box covering circle of given radius in meters around the point.
+func BBoxAround(center Waypoint, radius float64) BBox {
+	dLat := radius / EarthRadius * 180 / math.Pi
+
+	dLon := 180.0
+	if cos := math.Cos(center.Lat * math.Pi / 180); cos > 1e-9 {
+		dLon = math.Min(180, dLat/cos)
+	}
+
+	return BBox{
+		MinLat: math.Max(-90, center.Lat-dLat),
+		MinLon: math.Max(-180, center.Lon-dLon),
+		MaxLat: math.Min(90, center.Lat+dLat),
+		MaxLon: math.Min(180, center.Lon+dLon),
+	}
+}
+
+// PassesNear reports whether any segment of the route comes within radius meters of the point.
+// Segments are measured in local planar projection around the point, which is accurate
+// for radii much smaller than the Earth radius.
+func (r Route) PassesNear(point Waypoint, radius float64) bool {
+	if len(r.Waypoints) == 1 {
+		return Haversine(point, r.Waypoints[0]) <= radius
+	}
+
+	for i := 1; i < len(r.Waypoints); i++ {
+		ax, ay := project(point, r.Waypoints[i-1])
+		bx, by := project(point, r.Waypoints[i])
+
+		// closest point of segment ab to the origin
+		dx, dy := bx-ax, by-ay
+		t := 0.0
+		if l := dx*dx + dy*dy; l > 0 {
+			t = math.Max(0, math.Min(1, -(ax*dx+ay*dy)/l))
+		}
+		if math.Hypot(ax+t*dx, ay+t*dy) <= radius {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Crosses reports whether the route has a point inside the box.
+func (r Route) Crosses(b BBox) bool {
+	if len(r.Waypoints) == 1 {
+		return b.Contains(r.Waypoints[0])
+	}
+
+	for i := 1; i < len(r.Waypoints); i++ {
+		if segmentCrosses(r.Waypoints[i-1], r.Waypoints[i], b) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// project returns planar coordinates of wp in meters relative to origin.
+func project(origin, wp Waypoint) (x, y float64) {
+	dLon := math.Remainder(wp.Lon-origin.Lon, 360)
+	x = dLon * math.Pi / 180 * EarthRadius * math.Cos(origin.Lat*math.Pi/180)
+	y = (wp.Lat - origin.Lat) * math.Pi / 180 * EarthRadius
+
+	return x, y
+}
+
+// segmentCrosses clips segment ab by the box with Liang-Barsky algorithm.
+func segmentCrosses(a, b Waypoint, box BBox) bool {
+	t0, t1 := 0.0, 1.0
+	clip := func(p, q float64) bool {
+		if p == 0 {
+			return q >= 0
+		}
+
+		r := q / p
+		if p < 0 {
+			if r > t1 {
+				return false
+			}
+			t0 = math.Max(t0, r)
+		} else {
+			if r < t0 {
+				return false
+			}
+			t1 = math.Min(t1, r)
+		}
+
+		return true
+	}
+
+	dLon, dLat := b.Lon-a.Lon, b.Lat-a.Lat
+
+	return clip(-dLon, a.Lon-box.MinLon) &&
+		clip(dLon, box.MaxLon-a.Lon) &&
+		clip(-dLat, a.Lat-box.MinLat) &&
+		clip(dLat, box.MaxLat-a.Lat)
+}
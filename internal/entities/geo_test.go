@@ -0,0 +1,139 @@
+package entities
+
+import (
+	"github.com/stretchr/testify/require"
+	"math"
+	"testing"
+)
+
+func TestPolyline(t *testing.T) {
+	route := Route{Waypoints: []Waypoint{
+		{Lat: 38.5, Lon: -120.2},
+		{Lat: 40.7, Lon: -120.95},
+		{Lat: 43.252, Lon: -126.453},
+	}}
+
+	require.Equal(t, "_p~iF~ps|U_ulLnnqC_mqNvxq`@", route.Polyline())
+	require.Equal(t, "", Route{}.Polyline())
+}
+
+func TestLength(t *testing.T) {
+	const eps = 1e-6
+
+	degree := EarthRadius * math.Pi / 180
+
+	route := Route{Waypoints: []Waypoint{
+		{Lat: 0, Lon: 0},
+		{Lat: 0, Lon: 1},
+		{Lat: 90, Lon: 1},
+	}}
+
+	require.InDelta(t, degree, Haversine(route.Waypoints[0], route.Waypoints[1]), eps)
+	require.InDelta(t, 91*degree, route.Length(), eps)
+	require.Zero(t, Route{Waypoints: route.Waypoints[:1]}.Length())
+}
+
+func TestPassesNear(t *testing.T) {
+	// segment along the equator from 0 to 1 degree of longitude
+	route := Route{Waypoints: []Waypoint{{Lat: 0, Lon: 0}, {Lat: 0, Lon: 1}}}
+	degree := EarthRadius * math.Pi / 180
+
+	testCases := []struct {
+		name     string
+		route    Route
+		point    Waypoint
+		radius   float64
+		expected bool
+	}{
+		{
+			name:     "point above the middle of segment",
+			route:    route,
+			point:    Waypoint{Lat: 0.01, Lon: 0.5},
+			radius:   0.011 * degree,
+			expected: true,
+		},
+		{
+			name:     "point above the middle of segment out of radius",
+			route:    route,
+			point:    Waypoint{Lat: 0.01, Lon: 0.5},
+			radius:   0.009 * degree,
+			expected: false,
+		},
+		{
+			name:     "point beyond the end of segment",
+			route:    route,
+			point:    Waypoint{Lat: 0, Lon: 1.01},
+			radius:   0.009 * degree,
+			expected: false,
+		},
+		{
+			name:     "single waypoint",
+			route:    Route{Waypoints: route.Waypoints[:1]},
+			point:    Waypoint{Lat: 0.01, Lon: 0},
+			radius:   0.011 * degree,
+			expected: true,
+		},
+		{
+			name:     "no waypoints",
+			point:    Waypoint{Lat: 0, Lon: 0},
+			radius:   degree,
+			expected: false,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, tc.route.PassesNear(tc.point, tc.radius))
+		})
+	}
+}
+
+func TestCrosses(t *testing.T) {
+	box := BBox{MinLat: 0, MinLon: 0, MaxLat: 1, MaxLon: 1}
+
+	testCases := []struct {
+		name      string
+		waypoints []Waypoint
+		expected  bool
+	}{
+		{
+			name:      "segment passes through the box",
+			waypoints: []Waypoint{{Lat: -1, Lon: 0.5}, {Lat: 2, Lon: 0.5}},
+			expected:  true,
+		},
+		{
+			name:      "segment ends inside the box",
+			waypoints: []Waypoint{{Lat: 5, Lon: 5}, {Lat: 0.5, Lon: 0.5}},
+			expected:  true,
+		},
+		{
+			name:      "segment passes by the corner",
+			waypoints: []Waypoint{{Lat: 2.5, Lon: 0}, {Lat: 0, Lon: 2.5}},
+			expected:  false,
+		},
+		{
+			name:      "single waypoint inside",
+			waypoints: []Waypoint{{Lat: 0.5, Lon: 0.5}},
+			expected:  true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, Route{Waypoints: tc.waypoints}.Crosses(box))
+		})
+	}
+}
+
+func TestBBoxAround(t *testing.T) {
+	const eps = 1e-9
+
+	degree := EarthRadius * math.Pi / 180
+
+	box := BBoxAround(Waypoint{Lat: 60, Lon: 30}, degree)
+	require.InDelta(t, 59, box.MinLat, eps)
+	require.InDelta(t, 61, box.MaxLat, eps)
+	require.InDelta(t, 28, box.MinLon, eps)
+	require.InDelta(t, 32, box.MaxLon, eps)
+
+	box = BBoxAround(Waypoint{Lat: 90, Lon: 0}, degree)
+	require.Equal(t, BBox{MinLat: 89, MinLon: -180, MaxLat: 90, MaxLon: 180}, box)
+}
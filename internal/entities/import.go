@@ -0,0 +1,19 @@
+package entities
+
+// ImportedRoute is a valid route read from import file.
+type ImportedRoute struct {
+	Line  int
+	Route Route
+}
+
+type RejectedRow struct {
+	Line   int
+	Reason string
+}
+
+type ImportReport struct {
+	Total    int
+	Imported int
+	Reissued int
+	Rejected []RejectedRow
+}
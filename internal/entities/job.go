@@ -0,0 +1,46 @@
+package entities
+
+import "time"
+
+type JobStatus string
+
+const (
+	JobPending   JobStatus = "pending"
+	JobRunning   JobStatus = "running"
+	JobSucceeded JobStatus = "succeeded"
+	JobFailed    JobStatus = "failed"
+)
+
+type DeleteOutcome string
+
+const (
+	DeletePending  DeleteOutcome = "pending"
+	DeleteDone     DeleteOutcome = "deleted"
+	DeleteNotFound DeleteOutcome = "not_found"
+	DeleteFailed   DeleteOutcome = "failed"
+)
+
+type DeleteJob struct {
+	JobID     int64
+	Status    JobStatus
+	Attempts  int
+	Error     string
+	Items     []DeleteJobItem
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type DeleteJobItem struct {
+	RouteID int
+	Status  DeleteOutcome
+}
+
+// RouteIDs returns ids of routes the job should delete.
+func (j DeleteJob) RouteIDs() []int {
+	ids := make([]int, 0, len(j.Items))
+	for _, item := range j.Items {
+		ids = append(ids, item.RouteID)
+	}
+
+	return ids
+}
@@ -1,9 +1,129 @@
 package entities
 
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"task/pkg/decimal"
+	"time"
+)
+
 type Route struct {
 	RouteID   int
 	RouteName string
-	Load      float32
+	// Load is measured in CanonicalUnit
+	Load      decimal.Decimal
+	CargoType string
+	IsActual  bool
+	Waypoints []Waypoint
+	// Distance is great-circle length of the route in meters
+	Distance float64
+	// Duration is estimated travel time of the route
+	Duration time.Duration
+	// VehicleID is id of vehicle assigned to the route, nil if there is none
+	VehicleID *int
+}
+
+// RegisterResult is an outcome of registering one route of a batch.
+type RegisterResult struct {
+	RouteID  int
+	Reissued bool
+	Err      error
+}
+
+type RouteFilter struct {
+	// RouteIDs restricts result to given routes when not nil
+	RouteIDs   []int
+	AfterID    *int
+	Limit      int
+	CargoType  string
+	IsActual   *bool
+	MinLoad    *decimal.Decimal
+	MaxLoad    *decimal.Decimal
+	NamePrefix string
+	VehicleID  *int
+}
+
+// RouteStats aggregates loads of routes sharing cargo type and actuality.
+// Loads are measured in CanonicalUnit.
+type RouteStats struct {
 	CargoType string
 	IsActual  bool
+	Count     int
+	TotalLoad decimal.Decimal
+	// AvgLoad is rounded to the largest number of fractional digits of aggregated loads
+	AvgLoad decimal.Decimal
+	MinLoad decimal.Decimal
+	MaxLoad decimal.Decimal
+}
+
+// AggregateStats computes load statistics of routes the same way database does: groups are
+// ordered by cargo type, actual routes go first.
+func AggregateStats(routes []Route) (stats []RouteStats, err error) {
+	type key struct {
+		cargoType string
+		isActual  bool
+	}
+	groups := make(map[key]*RouteStats)
+	scales := make(map[key]int32)
+	for _, route := range routes {
+		k := key{cargoType: route.CargoType, isActual: route.IsActual}
+		item, ok := groups[k]
+		if !ok {
+			item = &RouteStats{
+				CargoType: route.CargoType,
+				IsActual:  route.IsActual,
+				MinLoad:   route.Load,
+				MaxLoad:   route.Load,
+			}
+			groups[k] = item
+		}
+
+		item.Count++
+		item.TotalLoad, err = item.TotalLoad.Add(route.Load)
+		if err != nil {
+			return nil, fmt.Errorf("total load of routes with cargo type %q is too large: %w", route.CargoType, err)
+		}
+		if route.Load.Cmp(item.MinLoad) < 0 {
+			item.MinLoad = route.Load
+		}
+		if route.Load.Cmp(item.MaxLoad) > 0 {
+			item.MaxLoad = route.Load
+		}
+		scales[k] = max(scales[k], route.Load.Scale())
+	}
+
+	stats = make([]RouteStats, 0, len(groups))
+	for k, item := range groups {
+		item.AvgLoad, err = item.TotalLoad.Div(decimal.New(int64(item.Count), 0), scales[k])
+		if err != nil {
+			return nil, err
+		}
+		stats = append(stats, *item)
+	}
+
+	slices.SortFunc(stats, func(a, b RouteStats) int {
+		if c := cmp.Compare(a.CargoType, b.CargoType); c != 0 {
+			return c
+		}
+		// actual routes go first, as with "is_actual desc"
+		switch {
+		case a.IsActual == b.IsActual:
+			return 0
+		case a.IsActual:
+			return -1
+		default:
+			return 1
+		}
+	})
+
+	return stats, nil
+}
+
+type RouteVersion struct {
+	VersionID    int64
+	Route        Route
+	CreatedAt    time.Time
+	SupersededBy *int64
+	SupersededAt *time.Time
 }
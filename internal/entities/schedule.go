@@ -0,0 +1,247 @@
+package entities
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	RecurrenceDaily    = "daily"
+	RecurrenceWeekdays = "weekdays"
+)
+
+var (
+	monthNames = map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}
+	weekdayNames = map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}
+)
+
+// Recurrence selects days on which scheduled route runs. Rule is either "daily", "weekdays"
+// (Monday to Friday) or cron-like expression "day-of-month month day-of-week" with the syntax
+// of crontab date fields: "*", numbers, names, ranges "1-5", lists "1,15" and steps "*/2".
+// As in cron, when both day of month and day of week are restricted, day matching either of them matches.
+type Recurrence struct {
+	rule     string
+	days     uint64
+	months   uint64
+	weekdays uint64
+	// domStar and dowStar are set when day of month or day of week field starts with "*"
+	domStar bool
+	dowStar bool
+}
+
+func ParseRecurrence(rule string) (Recurrence, error) {
+	normalized := strings.Join(strings.Fields(strings.ToLower(rule)), " ")
+	expr := normalized
+	switch expr {
+	case "":
+		return Recurrence{}, fmt.Errorf("recurrence should not be empty")
+	case RecurrenceDaily:
+		expr = "* * *"
+	case RecurrenceWeekdays:
+		expr = "* * mon-fri"
+	}
+
+	fields := strings.Fields(expr)
+	if len(fields) != 3 {
+		return Recurrence{}, fmt.Errorf("recurrence %q should be %q, %q or \"day-of-month month day-of-week\"", rule, RecurrenceDaily, RecurrenceWeekdays)
+	}
+
+	r := Recurrence{
+		rule:    normalized,
+		domStar: strings.HasPrefix(fields[0], "*"),
+		dowStar: strings.HasPrefix(fields[2], "*"),
+	}
+
+	var err error
+	if r.days, err = parseCronField(fields[0], 1, 31, nil); err != nil {
+		return Recurrence{}, fmt.Errorf("recurrence %q: day of month: %w", rule, err)
+	}
+	if r.months, err = parseCronField(fields[1], 1, 12, monthNames); err != nil {
+		return Recurrence{}, fmt.Errorf("recurrence %q: month: %w", rule, err)
+	}
+	if r.weekdays, err = parseCronField(fields[2], 0, 7, weekdayNames); err != nil {
+		return Recurrence{}, fmt.Errorf("recurrence %q: day of week: %w", rule, err)
+	}
+	// both 0 and 7 are Sunday
+	if r.weekdays&(1<<7) != 0 {
+		r.weekdays = r.weekdays&^(1<<7) | 1
+	}
+
+	return r, nil
+}
+
+// MustParseRecurrence is like ParseRecurrence but panics on error.
+func MustParseRecurrence(rule string) Recurrence {
+	r, err := ParseRecurrence(rule)
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
+
+// parseCronField returns bit set of values in [min, max] listed by field.
+func parseCronField(field string, min, max int, names map[string]int) (bits uint64, err error) {
+	for _, part := range strings.Split(field, ",") {
+		span, stepStr, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			step, err = strconv.Atoi(stepStr)
+			if err != nil || step <= 0 {
+				return 0, fmt.Errorf("invalid step %q", stepStr)
+			}
+		}
+
+		lo, hi := min, max
+		if span != "*" {
+			loStr, hiStr, isRange := strings.Cut(span, "-")
+			lo, err = parseCronValue(loStr, names)
+			if err != nil {
+				return 0, err
+			}
+			hi = lo
+			if isRange {
+				hi, err = parseCronValue(hiStr, names)
+				if err != nil {
+					return 0, err
+				}
+			} else if hasStep {
+				// "5/10" is a shorthand for "5-max/10"
+				hi = max
+			}
+		}
+
+		if lo < min || hi > max || lo > hi {
+			return 0, fmt.Errorf("%q is out of range %d-%d", part, min, max)
+		}
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << v
+		}
+	}
+
+	return bits, nil
+}
+
+func parseCronValue(s string, names map[string]int) (int, error) {
+	if v, ok := names[s]; ok {
+		return v, nil
+	}
+
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q", s)
+	}
+	return v, nil
+}
+
+// Matches reports whether route runs on the date. Only calendar date of t is used.
+func (r Recurrence) Matches(t time.Time) bool {
+	if r.months&(1<<int(t.Month())) == 0 {
+		return false
+	}
+
+	dayOK := r.days&(1<<t.Day()) != 0
+	weekdayOK := r.weekdays&(1<<int(t.Weekday())) != 0
+	if r.domStar || r.dowStar {
+		return dayOK && weekdayOK
+	}
+	return dayOK || weekdayOK
+}
+
+// String returns normalized rule recurrence was parsed from.
+func (r Recurrence) String() string {
+	return r.rule
+}
+
+// TimeWindow is interval of time of day given as offsets from midnight of the trip day.
+// Offsets may exceed 24 hours for arrivals on the following days.
+type TimeWindow struct {
+	From time.Duration
+	To   time.Duration
+}
+
+// Schedule tells when route runs: on days selected by Recurrence it departs within
+// Departure window and arrives within Arrival window.
+type Schedule struct {
+	RouteID    int
+	Departure  TimeWindow
+	Arrival    TimeWindow
+	Recurrence Recurrence
+	// Timezone is IANA name of time zone windows are given in
+	Timezone string
+	// ValidFrom and ValidUntil are the first and the last dates of trips, nil if unbounded
+	ValidFrom  *time.Time
+	ValidUntil *time.Time
+}
+
+// Interval is a span of absolute time.
+type Interval struct {
+	From time.Time
+	To   time.Time
+}
+
+// Trip is a single run of scheduled route.
+type Trip struct {
+	RouteID int
+	// Date is local date of departure at UTC midnight
+	Date      time.Time
+	Departure Interval
+	Arrival   Interval
+}
+
+// Trips expands schedule into trips departing on dates from first to last inclusive.
+// Only calendar dates of first and last are used.
+func (s Schedule) Trips(first, last time.Time) (trips []Trip, err error) {
+	loc, err := time.LoadLocation(s.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("loading time zone of route %d schedule: %w", s.RouteID, err)
+	}
+
+	first, last = civilDate(first), civilDate(last)
+	if s.ValidFrom != nil && first.Before(civilDate(*s.ValidFrom)) {
+		first = civilDate(*s.ValidFrom)
+	}
+	if s.ValidUntil != nil && last.After(civilDate(*s.ValidUntil)) {
+		last = civilDate(*s.ValidUntil)
+	}
+
+	for date := first; !date.After(last); date = date.AddDate(0, 0, 1) {
+		if !s.Recurrence.Matches(date) {
+			continue
+		}
+
+		trips = append(trips, Trip{
+			RouteID: s.RouteID,
+			Date:    date,
+			Departure: Interval{
+				From: localTime(date, s.Departure.From, loc),
+				To:   localTime(date, s.Departure.To, loc),
+			},
+			Arrival: Interval{
+				From: localTime(date, s.Arrival.From, loc),
+				To:   localTime(date, s.Arrival.To, loc),
+			},
+		})
+	}
+
+	return trips, nil
+}
+
+// civilDate returns calendar date of t at UTC midnight.
+func civilDate(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+// localTime returns wall clock time offset from midnight of the date in loc. Offset is applied
+// to wall clock, so 08:00 stays 08:00 on days of daylight saving time transitions.
+func localTime(date time.Time, offset time.Duration, loc *time.Location) time.Time {
+	y, m, d := date.Date()
+	return time.Date(y, m, d, 0, 0, 0, int(offset), loc)
+}
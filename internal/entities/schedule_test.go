@@ -0,0 +1,174 @@
+package entities
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func dateOf(y int, m time.Month, d int) time.Time {
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestRecurrence(t *testing.T) {
+	testCases := []struct {
+		name  string
+		rule  string
+		dates map[time.Time]bool
+		err   error
+	}{
+		{
+			name: "daily",
+			rule: "daily",
+			dates: map[time.Time]bool{
+				dateOf(2026, 10, 17): true,
+				dateOf(2026, 10, 18): true,
+			},
+		},
+		{
+			name: "weekdays",
+			rule: "Weekdays",
+			dates: map[time.Time]bool{
+				dateOf(2026, 10, 16): true,  // Friday
+				dateOf(2026, 10, 17): false, // Saturday
+				dateOf(2026, 10, 18): false, // Sunday
+				dateOf(2026, 10, 19): true,  // Monday
+			},
+		},
+		{
+			name: "sunday as 7",
+			rule: "* * 6,7",
+			dates: map[time.Time]bool{
+				dateOf(2026, 10, 16): false,
+				dateOf(2026, 10, 17): true,
+				dateOf(2026, 10, 18): true,
+			},
+		},
+		{
+			name: "day of month or day of week",
+			rule: "1,15 * fri",
+			dates: map[time.Time]bool{
+				dateOf(2026, 10, 1):  true,
+				dateOf(2026, 10, 14): false,
+				dateOf(2026, 10, 15): true,
+				dateOf(2026, 10, 16): true,
+			},
+		},
+		{
+			name: "day of month with step and star day of week",
+			rule: "*/10 jun-aug *",
+			dates: map[time.Time]bool{
+				dateOf(2026, 7, 1):  true,
+				dateOf(2026, 7, 11): true,
+				dateOf(2026, 7, 12): false,
+				dateOf(2026, 9, 1):  false,
+			},
+		},
+		{
+			name: "start with step",
+			rule: "* * 1/2",
+			dates: map[time.Time]bool{
+				dateOf(2026, 10, 19): true,  // Monday
+				dateOf(2026, 10, 20): false, // Tuesday
+				dateOf(2026, 10, 21): true,  // Wednesday
+				dateOf(2026, 10, 24): false, // Saturday
+			},
+		},
+		{
+			name: "empty",
+			rule: " ",
+			err:  fmt.Errorf("recurrence should not be empty"),
+		},
+		{
+			name: "five fields",
+			rule: "0 8 * * 1-5",
+			err:  fmt.Errorf(`recurrence "0 8 * * 1-5" should be "daily", "weekdays" or "day-of-month month day-of-week"`),
+		},
+		{
+			name: "out of range",
+			rule: "0 * *",
+			err:  fmt.Errorf(`recurrence "0 * *": day of month: "0" is out of range 1-31`),
+		},
+		{
+			name: "reversed range",
+			rule: "* * fri-mon",
+			err:  fmt.Errorf(`recurrence "* * fri-mon": day of week: "fri-mon" is out of range 0-7`),
+		},
+		{
+			name: "invalid step",
+			rule: "* */0 *",
+			err:  fmt.Errorf(`recurrence "* */0 *": month: invalid step "0"`),
+		},
+		{
+			name: "invalid value",
+			rule: "* * monday",
+			err:  fmt.Errorf(`recurrence "* * monday": day of week: invalid value "monday"`),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r, err := ParseRecurrence(tc.rule)
+			if tc.err != nil {
+				require.Equal(t, tc.err.Error(), err.Error())
+				return
+			}
+			require.Nil(t, err)
+
+			for d, expected := range tc.dates {
+				require.Equal(t, expected, r.Matches(d), d.Format(time.DateOnly))
+			}
+		})
+	}
+}
+
+func TestScheduleTrips(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.Nil(t, err)
+
+	validUntil := dateOf(2026, 10, 28)
+	schedule := Schedule{
+		RouteID:    3,
+		Departure:  TimeWindow{From: 8 * time.Hour, To: 8*time.Hour + 30*time.Minute},
+		Arrival:    TimeWindow{From: 27 * time.Hour, To: 28 * time.Hour},
+		Recurrence: MustParseRecurrence("* * sat,sun"),
+		Timezone:   "Europe/Berlin",
+		ValidUntil: &validUntil,
+	}
+
+	trips, err := schedule.Trips(dateOf(2026, 10, 20), dateOf(2026, 11, 30))
+	require.Nil(t, err)
+	require.Equal(t, []Trip{
+		{
+			RouteID: 3,
+			Date:    dateOf(2026, 10, 24),
+			Departure: Interval{
+				From: time.Date(2026, 10, 24, 8, 0, 0, 0, berlin),
+				To:   time.Date(2026, 10, 24, 8, 30, 0, 0, berlin),
+			},
+			Arrival: Interval{
+				From: time.Date(2026, 10, 25, 3, 0, 0, 0, berlin),
+				To:   time.Date(2026, 10, 25, 4, 0, 0, 0, berlin),
+			},
+		},
+		{
+			// daylight saving time ends, wall clock times are kept
+			RouteID: 3,
+			Date:    dateOf(2026, 10, 25),
+			Departure: Interval{
+				From: time.Date(2026, 10, 25, 8, 0, 0, 0, berlin),
+				To:   time.Date(2026, 10, 25, 8, 30, 0, 0, berlin),
+			},
+			Arrival: Interval{
+				From: time.Date(2026, 10, 26, 3, 0, 0, 0, berlin),
+				To:   time.Date(2026, 10, 26, 4, 0, 0, 0, berlin),
+			},
+		},
+	}, trips)
+	require.Equal(t, "2026-10-24T08:00:00+02:00", trips[0].Departure.From.Format(time.RFC3339))
+	require.Equal(t, "2026-10-25T08:00:00+01:00", trips[1].Departure.From.Format(time.RFC3339))
+
+	schedule.Timezone = "Mars/Olympus"
+	_, err = schedule.Trips(dateOf(2026, 10, 20), dateOf(2026, 10, 21))
+	require.NotNil(t, err)
+}
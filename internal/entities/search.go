@@ -0,0 +1,134 @@
+package entities
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// SearchSimilarityThreshold is the least word similarity of route name to search query
+// for the route to be found without exact word match.
+const SearchSimilarityThreshold = 0.3
+
+// fullTextRank is added to rank of names containing all words of query, so exact matches
+// come before fuzzy ones.
+const fullTextRank = 0.1
+
+// SearchRank tells whether route name matches search query and how relevant it is. It is
+// in-process counterpart of database search: name matches if it contains all words of query
+// or is similar enough to it, see NameSimilarity.
+func SearchRank(query, name string) (rank float64, ok bool) {
+	queryWords, nameWords := searchWords(query), searchWords(name)
+	if len(queryWords) == 0 {
+		return 0, false
+	}
+
+	rank = wordSimilarity(queryWords, nameWords)
+	fullText := containsWords(nameWords, queryWords)
+	if fullText {
+		rank += fullTextRank
+	}
+
+	return rank, fullText || rank >= SearchSimilarityThreshold
+}
+
+// RankRoutes returns at most limit routes with names matching query, the most relevant first.
+// Routes of equal rank are ordered by id.
+func RankRoutes(query string, routes []Route, limit int) []Route {
+	type ranked struct {
+		route Route
+		rank  float64
+	}
+	var found []ranked
+	for _, route := range routes {
+		if rank, ok := SearchRank(query, route.RouteName); ok {
+			found = append(found, ranked{route: route, rank: rank})
+		}
+	}
+
+	slices.SortFunc(found, func(a, b ranked) int {
+		if c := cmp.Compare(b.rank, a.rank); c != 0 {
+			return c
+		}
+		return a.route.RouteID - b.route.RouteID
+	})
+	if len(found) > limit {
+		found = found[:limit]
+	}
+
+	res := make([]Route, 0, len(found))
+	for _, item := range found {
+		res = append(res, item.route)
+	}
+
+	return res
+}
+
+// NameSimilarity mirrors word_similarity of pg_trgm: it is the greatest similarity of trigram
+// set of query to a continuous extent of ordered trigrams of name, from 0 to 1.
+func NameSimilarity(query, name string) float64 {
+	return wordSimilarity(searchWords(query), searchWords(name))
+}
+
+func wordSimilarity(queryWords, nameWords []string) (best float64) {
+	query := make(map[string]bool)
+	for _, trigram := range trigrams(queryWords) {
+		query[trigram] = true
+	}
+	if len(query) == 0 {
+		return 0
+	}
+
+	ordered := trigrams(nameWords)
+	for i := range ordered {
+		extent := make(map[string]bool)
+		common := 0
+		for _, trigram := range ordered[i:] {
+			if extent[trigram] {
+				continue
+			}
+			extent[trigram] = true
+			if query[trigram] {
+				common++
+			}
+			best = max(best, float64(common)/float64(len(query)+len(extent)-common))
+		}
+	}
+
+	return best
+}
+
+// searchWords splits s into lower case words of letters and digits, as pg_trgm does.
+func searchWords(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// trigrams returns trigrams of words in order, each word is padded with two spaces in front
+// and one space behind.
+func trigrams(words []string) []string {
+	var res []string
+	for _, word := range words {
+		runes := []rune("  " + word + " ")
+		for i := 0; i+3 <= len(runes); i++ {
+			res = append(res, string(runes[i:i+3]))
+		}
+	}
+	return res
+}
+
+func containsWords(words []string, subset []string) bool {
+	set := make(map[string]bool, len(words))
+	for _, word := range words {
+		set[word] = true
+	}
+
+	for _, word := range subset {
+		if !set[word] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,66 @@
+package entities
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestSearchRank(t *testing.T) {
+	testCases := []struct {
+		name    string
+		query   string
+		route   string
+		matches bool
+	}{
+		{
+			name:    "exact words",
+			query:   "tver",
+			route:   "Moscow - Tver gravel",
+			matches: true,
+		},
+		{
+			name:    "typos",
+			query:   "Mosow tvr",
+			route:   "Moscow - Tver gravel",
+			matches: true,
+		},
+		{
+			name:    "words in other order",
+			query:   "gravel moscow",
+			route:   "Moscow - Tver gravel",
+			matches: true,
+		},
+		{
+			name:    "unrelated",
+			query:   "kazan",
+			route:   "Moscow - Tver gravel",
+			matches: false,
+		},
+		{
+			name:    "empty query",
+			query:   " - ",
+			route:   "Moscow - Tver gravel",
+			matches: false,
+		},
+		{
+			name:    "cyrillic",
+			query:   "Тверь",
+			route:   "Москва - Тверь",
+			matches: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, ok := SearchRank(tc.query, tc.route)
+			require.Equal(t, tc.matches, ok)
+		})
+	}
+
+	exact, _ := SearchRank("moscow tver", "Moscow - Tver gravel")
+	fuzzy, _ := SearchRank("moscow tver", "Moscow - Tula - Tverskaya")
+	require.Greater(t, exact, fuzzy)
+
+	// example from pg_trgm documentation
+	require.InDelta(t, 0.8, NameSimilarity("word", "two words"), 1e-9)
+	require.Equal(t, 1.0, NameSimilarity("MOSCOW", "to moscow"))
+}
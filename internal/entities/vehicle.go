@@ -0,0 +1,41 @@
+package entities
+
+import (
+	"fmt"
+	"slices"
+	"task/pkg/decimal"
+)
+
+type VehicleStatus string
+
+const (
+	VehicleAvailable   VehicleStatus = "available"
+	VehicleMaintenance VehicleStatus = "maintenance"
+	VehicleRetired     VehicleStatus = "retired"
+)
+
+// Vehicle carries routes assigned to it.
+type Vehicle struct {
+	VehicleID int
+	Name      string
+	// Capacity is the largest load vehicle can carry, measured in CanonicalUnit
+	Capacity decimal.Decimal
+	// CargoTypes are codes of cargo types vehicle is allowed to carry
+	CargoTypes []string
+	Status     VehicleStatus
+}
+
+// Fits returns error describing why route can not be carried by the vehicle, nil if it can.
+// Status of the vehicle is not checked.
+func (v Vehicle) Fits(route Route) error {
+	if v.Capacity.Cmp(route.Load) < 0 {
+		return fmt.Errorf("capacity of vehicle %d (%s %s) is less than load of route %d (%s %s)",
+			v.VehicleID, v.Capacity, CanonicalUnit, route.RouteID, route.Load, CanonicalUnit)
+	}
+
+	if !slices.Contains(v.CargoTypes, route.CargoType) {
+		return fmt.Errorf("vehicle %d is not allowed to carry cargo type %q", v.VehicleID, route.CargoType)
+	}
+
+	return nil
+}
@@ -0,0 +1,52 @@
+package entities
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"task/pkg/decimal"
+	"testing"
+)
+
+func TestVehicleFits(t *testing.T) {
+	vehicle := Vehicle{
+		VehicleID:  7,
+		Capacity:   decimal.MustParse("20000"),
+		CargoTypes: []string{"gravel", "sand"},
+		Status:     VehicleAvailable,
+	}
+
+	testCases := []struct {
+		name  string
+		route Route
+		err   error
+	}{
+		{
+			name:  "fits",
+			route: Route{RouteID: 1, Load: decimal.MustParse("12100.5"), CargoType: "sand"},
+		},
+		{
+			name:  "load equals capacity",
+			route: Route{RouteID: 1, Load: decimal.MustParse("20000"), CargoType: "gravel"},
+		},
+		{
+			name:  "load exceeds capacity",
+			route: Route{RouteID: 1, Load: decimal.MustParse("20000.001"), CargoType: "sand"},
+			err:   fmt.Errorf("capacity of vehicle 7 (20000 kg) is less than load of route 1 (20000.001 kg)"),
+		},
+		{
+			name:  "cargo type is not allowed",
+			route: Route{RouteID: 1, Load: decimal.MustParse("100"), CargoType: "diesel"},
+			err:   fmt.Errorf(`vehicle 7 is not allowed to carry cargo type "diesel"`),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := vehicle.Fits(tc.route)
+			if tc.err != nil {
+				require.Equal(t, tc.err.Error(), err.Error())
+			} else {
+				require.Nil(t, err)
+			}
+		})
+	}
+}
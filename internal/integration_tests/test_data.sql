@@ -4,4 +4,9 @@ values (1, 'test1', 1.0, 'cargo1'),
        (3, 'test3', 3.0, 'cargo3'),
        (4, 'test4', 4.0, 'cargo4'),
        (5, 'test5', 5.0, 'cargo5'),
-       (6, 'test6', 6.0, 'cargo6');
\ No newline at end of file
+       (6, 'test6', 6.0, 'cargo6');
+
+insert into route_versions(route_id, route_name, load, cargo_type)
+select route_id, route_name, load, cargo_type
+from routes
+order by route_id;
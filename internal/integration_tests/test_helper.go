@@ -6,15 +6,15 @@ import (
 	"fmt"
 	"github.com/golang-migrate/migrate/v4"
 	_ "github.com/golang-migrate/migrate/v4/database/postgres"
-	_ "github.com/golang-migrate/migrate/v4/source/file"
-	"github.com/jackc/pgx/v5"
+	_ "github.com/golang-migrate/migrate/v4/database/sqlite"
+	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/testcontainers/testcontainers-go"
 	"github.com/testcontainers/testcontainers-go/wait"
 	"log"
 	"os"
 	"os/exec"
 	"path/filepath"
-	"runtime"
+	"task/migrations"
 	"time"
 )
 
@@ -25,7 +25,7 @@ const (
 )
 
 type TestDatabase struct {
-	DbInstance *pgx.Conn
+	DbInstance *pgxpool.Pool
 	DbAddress  string
 	container  testcontainers.Container
 }
@@ -52,11 +52,11 @@ func SetupTestDatabase() *TestDatabase {
 
 func (tdb *TestDatabase) TearDown() {
 	ctx := context.Background()
-	tdb.DbInstance.Close(ctx)
+	tdb.DbInstance.Close()
 	_ = tdb.container.Terminate(ctx)
 }
 
-func createContainer(ctx context.Context) (testcontainers.Container, *pgx.Conn, string, error) {
+func createContainer(ctx context.Context) (testcontainers.Container, *pgxpool.Pool, string, error) {
 	var env = map[string]string{
 		"POSTGRES_PASSWORD": DbPass,
 		"POSTGRES_USER":     DbUser,
@@ -89,7 +89,7 @@ func createContainer(ctx context.Context) (testcontainers.Container, *pgx.Conn,
 
 	dbAddr := fmt.Sprintf("localhost:%s", p.Port())
 	connStr := fmt.Sprintf("postgres://%s:%s@%s/%s?sslmode=disable", DbUser, DbPass, dbAddr, DbName)
-	db, err := pgx.Connect(ctx, connStr)
+	db, err := pgxpool.New(ctx, connStr)
 	if err != nil {
 		return container, db, dbAddr, fmt.Errorf("failed to establish database connection: %v", err)
 	}
@@ -98,15 +98,35 @@ func createContainer(ctx context.Context) (testcontainers.Container, *pgx.Conn,
 }
 
 func migrateDb(dbAddr string) error {
-	_, path, _, ok := runtime.Caller(0)
-	if !ok {
-		return fmt.Errorf("failed to get path")
+	src, err := migrations.Postgres()
+	if err != nil {
+		return err
 	}
-	_ = path
-	pathToMigrationFiles := filepath.Join("..", "..", "migrations")
 
 	databaseURL := fmt.Sprintf("postgres://%s:%s@%s/%s?sslmode=disable", DbUser, DbPass, dbAddr, DbName)
-	m, err := migrate.New(fmt.Sprintf("file:%s", pathToMigrationFiles), databaseURL)
+	m, err := migrate.NewWithSourceInstance("iofs", src, databaseURL)
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+
+	err = m.Up()
+	if err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return err
+	}
+
+	return nil
+}
+
+// MigrateSQLite applies SQLite migrations to database file at path, creating the file
+// if it does not exist.
+func MigrateSQLite(path string) error {
+	src, err := migrations.SQLite()
+	if err != nil {
+		return err
+	}
+
+	m, err := migrate.NewWithSourceInstance("iofs", src, fmt.Sprintf("sqlite://%s", path))
 	if err != nil {
 		return err
 	}
@@ -120,7 +140,7 @@ func migrateDb(dbAddr string) error {
 	return nil
 }
 
-func SeedTestData(db *pgx.Conn) error {
+func SeedTestData(db *pgxpool.Pool) error {
 	filePath := filepath.Join("..", "integration_tests", "test_data.sql")
 	f, err := os.Open(filePath)
 	if err != nil {
@@ -140,7 +160,7 @@ func SeedTestData(db *pgx.Conn) error {
 	return nil
 }
 
-func executeTestDataScript(db *pgx.Conn, filePath string) error {
+func executeTestDataScript(db *pgxpool.Pool, filePath string) error {
 	scriptContent, err := os.ReadFile(filePath)
 	if err != nil {
 		return fmt.Errorf("sql script reading: %w", err)
@@ -0,0 +1,128 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: cargo.go
+//
+// Generated by this command:
+//
+//	mockgen -source=cargo.go -destination=../mocks/cargo.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	entities "task/internal/entities"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockCargoTypeRepo is a mock of CargoTypeRepo interface.
+type MockCargoTypeRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockCargoTypeRepoMockRecorder
+}
+
+// MockCargoTypeRepoMockRecorder is the mock recorder for MockCargoTypeRepo.
+type MockCargoTypeRepoMockRecorder struct {
+	mock *MockCargoTypeRepo
+}
+
+// NewMockCargoTypeRepo creates a new mock instance.
+func NewMockCargoTypeRepo(ctrl *gomock.Controller) *MockCargoTypeRepo {
+	mock := &MockCargoTypeRepo{ctrl: ctrl}
+	mock.recorder = &MockCargoTypeRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCargoTypeRepo) EXPECT() *MockCargoTypeRepoMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockCargoTypeRepo) Create(ctx context.Context, cargoType entities.CargoType) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, cargoType)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockCargoTypeRepoMockRecorder) Create(ctx, cargoType any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockCargoTypeRepo)(nil).Create), ctx, cargoType)
+}
+
+// Delete mocks base method.
+func (m *MockCargoTypeRepo) Delete(ctx context.Context, code string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, code)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockCargoTypeRepoMockRecorder) Delete(ctx, code any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockCargoTypeRepo)(nil).Delete), ctx, code)
+}
+
+// GetByCode mocks base method.
+func (m *MockCargoTypeRepo) GetByCode(ctx context.Context, code string) (entities.CargoType, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByCode", ctx, code)
+	ret0, _ := ret[0].(entities.CargoType)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByCode indicates an expected call of GetByCode.
+func (mr *MockCargoTypeRepoMockRecorder) GetByCode(ctx, code any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByCode", reflect.TypeOf((*MockCargoTypeRepo)(nil).GetByCode), ctx, code)
+}
+
+// InUse mocks base method.
+func (m *MockCargoTypeRepo) InUse(ctx context.Context, code string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InUse", ctx, code)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InUse indicates an expected call of InUse.
+func (mr *MockCargoTypeRepoMockRecorder) InUse(ctx, code any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InUse", reflect.TypeOf((*MockCargoTypeRepo)(nil).InUse), ctx, code)
+}
+
+// List mocks base method.
+func (m *MockCargoTypeRepo) List(ctx context.Context) ([]entities.CargoType, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx)
+	ret0, _ := ret[0].([]entities.CargoType)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockCargoTypeRepoMockRecorder) List(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockCargoTypeRepo)(nil).List), ctx)
+}
+
+// Update mocks base method.
+func (m *MockCargoTypeRepo) Update(ctx context.Context, cargoType entities.CargoType) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, cargoType)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockCargoTypeRepoMockRecorder) Update(ctx, cargoType any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockCargoTypeRepo)(nil).Update), ctx, cargoType)
+}
@@ -0,0 +1,128 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: job.go
+//
+// Generated by this command:
+//
+//	mockgen -source=job.go -destination=../mocks/job.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	entities "task/internal/entities"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockJobRepo is a mock of JobRepo interface.
+type MockJobRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockJobRepoMockRecorder
+}
+
+// MockJobRepoMockRecorder is the mock recorder for MockJobRepo.
+type MockJobRepoMockRecorder struct {
+	mock *MockJobRepo
+}
+
+// NewMockJobRepo creates a new mock instance.
+func NewMockJobRepo(ctrl *gomock.Controller) *MockJobRepo {
+	mock := &MockJobRepo{ctrl: ctrl}
+	mock.recorder = &MockJobRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockJobRepo) EXPECT() *MockJobRepoMockRecorder {
+	return m.recorder
+}
+
+// CreateDeleteJob mocks base method.
+func (m *MockJobRepo) CreateDeleteJob(ctx context.Context, ids []int) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateDeleteJob", ctx, ids)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateDeleteJob indicates an expected call of CreateDeleteJob.
+func (mr *MockJobRepoMockRecorder) CreateDeleteJob(ctx, ids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateDeleteJob", reflect.TypeOf((*MockJobRepo)(nil).CreateDeleteJob), ctx, ids)
+}
+
+// FailDeleteJob mocks base method.
+func (m *MockJobRepo) FailDeleteJob(ctx context.Context, id int64, jobErr string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FailDeleteJob", ctx, id, jobErr)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FailDeleteJob indicates an expected call of FailDeleteJob.
+func (mr *MockJobRepoMockRecorder) FailDeleteJob(ctx, id, jobErr any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FailDeleteJob", reflect.TypeOf((*MockJobRepo)(nil).FailDeleteJob), ctx, id, jobErr)
+}
+
+// FinishDeleteJob mocks base method.
+func (m *MockJobRepo) FinishDeleteJob(ctx context.Context, id int64, deletedIds []int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FinishDeleteJob", ctx, id, deletedIds)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FinishDeleteJob indicates an expected call of FinishDeleteJob.
+func (mr *MockJobRepoMockRecorder) FinishDeleteJob(ctx, id, deletedIds any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FinishDeleteJob", reflect.TypeOf((*MockJobRepo)(nil).FinishDeleteJob), ctx, id, deletedIds)
+}
+
+// GetDeleteJob mocks base method.
+func (m *MockJobRepo) GetDeleteJob(ctx context.Context, id int64) (entities.DeleteJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeleteJob", ctx, id)
+	ret0, _ := ret[0].(entities.DeleteJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeleteJob indicates an expected call of GetDeleteJob.
+func (mr *MockJobRepoMockRecorder) GetDeleteJob(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeleteJob", reflect.TypeOf((*MockJobRepo)(nil).GetDeleteJob), ctx, id)
+}
+
+// ListUnfinishedDeleteJobs mocks base method.
+func (m *MockJobRepo) ListUnfinishedDeleteJobs(ctx context.Context) ([]entities.DeleteJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUnfinishedDeleteJobs", ctx)
+	ret0, _ := ret[0].([]entities.DeleteJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUnfinishedDeleteJobs indicates an expected call of ListUnfinishedDeleteJobs.
+func (mr *MockJobRepoMockRecorder) ListUnfinishedDeleteJobs(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUnfinishedDeleteJobs", reflect.TypeOf((*MockJobRepo)(nil).ListUnfinishedDeleteJobs), ctx)
+}
+
+// StartDeleteJob mocks base method.
+func (m *MockJobRepo) StartDeleteJob(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StartDeleteJob", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StartDeleteJob indicates an expected call of StartDeleteJob.
+func (mr *MockJobRepoMockRecorder) StartDeleteJob(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartDeleteJob", reflect.TypeOf((*MockJobRepo)(nil).StartDeleteJob), ctx, id)
+}
@@ -13,6 +13,7 @@ import (
 	context "context"
 	reflect "reflect"
 	entities "task/internal/entities"
+	repositories "task/internal/repositories"
 
 	gomock "go.uber.org/mock/gomock"
 )
@@ -40,20 +41,49 @@ func (m *MockRouteRepo) EXPECT() *MockRouteRepoMockRecorder {
 	return m.recorder
 }
 
-// DeleteById mocks base method.
-func (m *MockRouteRepo) DeleteById(ctx context.Context, ids []int) error {
+// Assign mocks base method.
+func (m *MockRouteRepo) Assign(ctx context.Context, routeID, vehicleID int) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteById", ctx, ids)
+	ret := m.ctrl.Call(m, "Assign", ctx, routeID, vehicleID)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
+// Assign indicates an expected call of Assign.
+func (mr *MockRouteRepoMockRecorder) Assign(ctx, routeID, vehicleID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Assign", reflect.TypeOf((*MockRouteRepo)(nil).Assign), ctx, routeID, vehicleID)
+}
+
+// DeleteById mocks base method.
+func (m *MockRouteRepo) DeleteById(ctx context.Context, ids []int) ([]int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteById", ctx, ids)
+	ret0, _ := ret[0].([]int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
 // DeleteById indicates an expected call of DeleteById.
 func (mr *MockRouteRepoMockRecorder) DeleteById(ctx, ids any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteById", reflect.TypeOf((*MockRouteRepo)(nil).DeleteById), ctx, ids)
 }
 
+// Export mocks base method.
+func (m *MockRouteRepo) Export(ctx context.Context, filter entities.RouteFilter, fn func(entities.Route) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Export", ctx, filter, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Export indicates an expected call of Export.
+func (mr *MockRouteRepoMockRecorder) Export(ctx, filter, fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Export", reflect.TypeOf((*MockRouteRepo)(nil).Export), ctx, filter, fn)
+}
+
 // GetById mocks base method.
 func (m *MockRouteRepo) GetById(ctx context.Context, id int) (entities.Route, error) {
 	m.ctrl.T.Helper()
@@ -69,6 +99,66 @@ func (mr *MockRouteRepoMockRecorder) GetById(ctx, id any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetById", reflect.TypeOf((*MockRouteRepo)(nil).GetById), ctx, id)
 }
 
+// History mocks base method.
+func (m *MockRouteRepo) History(ctx context.Context, id int) ([]entities.RouteVersion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "History", ctx, id)
+	ret0, _ := ret[0].([]entities.RouteVersion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// History indicates an expected call of History.
+func (mr *MockRouteRepoMockRecorder) History(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "History", reflect.TypeOf((*MockRouteRepo)(nil).History), ctx, id)
+}
+
+// Import mocks base method.
+func (m *MockRouteRepo) Import(ctx context.Context, src repositories.ImportSource) (entities.ImportReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Import", ctx, src)
+	ret0, _ := ret[0].(entities.ImportReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Import indicates an expected call of Import.
+func (mr *MockRouteRepoMockRecorder) Import(ctx, src any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Import", reflect.TypeOf((*MockRouteRepo)(nil).Import), ctx, src)
+}
+
+// List mocks base method.
+func (m *MockRouteRepo) List(ctx context.Context, filter entities.RouteFilter) ([]entities.Route, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, filter)
+	ret0, _ := ret[0].([]entities.Route)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockRouteRepoMockRecorder) List(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockRouteRepo)(nil).List), ctx, filter)
+}
+
+// Near mocks base method.
+func (m *MockRouteRepo) Near(ctx context.Context, point entities.Waypoint, radius float64) ([]entities.Route, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Near", ctx, point, radius)
+	ret0, _ := ret[0].([]entities.Route)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Near indicates an expected call of Near.
+func (mr *MockRouteRepoMockRecorder) Near(ctx, point, radius any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Near", reflect.TypeOf((*MockRouteRepo)(nil).Near), ctx, point, radius)
+}
+
 // Register mocks base method.
 func (m *MockRouteRepo) Register(ctx context.Context, route entities.Route) (int, error) {
 	m.ctrl.T.Helper()
@@ -83,3 +173,142 @@ func (mr *MockRouteRepoMockRecorder) Register(ctx, route any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Register", reflect.TypeOf((*MockRouteRepo)(nil).Register), ctx, route)
 }
+
+// RegisterBatch mocks base method.
+func (m *MockRouteRepo) RegisterBatch(ctx context.Context, routes []entities.Route) ([]int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegisterBatch", ctx, routes)
+	ret0, _ := ret[0].([]int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RegisterBatch indicates an expected call of RegisterBatch.
+func (mr *MockRouteRepoMockRecorder) RegisterBatch(ctx, routes any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterBatch", reflect.TypeOf((*MockRouteRepo)(nil).RegisterBatch), ctx, routes)
+}
+
+// Search mocks base method.
+func (m *MockRouteRepo) Search(ctx context.Context, query string, limit int) ([]entities.Route, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Search", ctx, query, limit)
+	ret0, _ := ret[0].([]entities.Route)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Search indicates an expected call of Search.
+func (mr *MockRouteRepoMockRecorder) Search(ctx, query, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockRouteRepo)(nil).Search), ctx, query, limit)
+}
+
+// Stats mocks base method.
+func (m *MockRouteRepo) Stats(ctx context.Context) ([]entities.RouteStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Stats", ctx)
+	ret0, _ := ret[0].([]entities.RouteStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Stats indicates an expected call of Stats.
+func (mr *MockRouteRepoMockRecorder) Stats(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stats", reflect.TypeOf((*MockRouteRepo)(nil).Stats), ctx)
+}
+
+// Update mocks base method.
+func (m *MockRouteRepo) Update(ctx context.Context, route entities.Route) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, route)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockRouteRepoMockRecorder) Update(ctx, route any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockRouteRepo)(nil).Update), ctx, route)
+}
+
+// Within mocks base method.
+func (m *MockRouteRepo) Within(ctx context.Context, box entities.BBox) ([]entities.Route, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Within", ctx, box)
+	ret0, _ := ret[0].([]entities.Route)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Within indicates an expected call of Within.
+func (mr *MockRouteRepoMockRecorder) Within(ctx, box any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Within", reflect.TypeOf((*MockRouteRepo)(nil).Within), ctx, box)
+}
+
+// MockImportSource is a mock of ImportSource interface.
+type MockImportSource struct {
+	ctrl     *gomock.Controller
+	recorder *MockImportSourceMockRecorder
+}
+
+// MockImportSourceMockRecorder is the mock recorder for MockImportSource.
+type MockImportSourceMockRecorder struct {
+	mock *MockImportSource
+}
+
+// NewMockImportSource creates a new mock instance.
+func NewMockImportSource(ctrl *gomock.Controller) *MockImportSource {
+	mock := &MockImportSource{ctrl: ctrl}
+	mock.recorder = &MockImportSourceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockImportSource) EXPECT() *MockImportSourceMockRecorder {
+	return m.recorder
+}
+
+// Err mocks base method.
+func (m *MockImportSource) Err() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Err")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Err indicates an expected call of Err.
+func (mr *MockImportSourceMockRecorder) Err() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Err", reflect.TypeOf((*MockImportSource)(nil).Err))
+}
+
+// Next mocks base method.
+func (m *MockImportSource) Next() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Next")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// Next indicates an expected call of Next.
+func (mr *MockImportSourceMockRecorder) Next() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Next", reflect.TypeOf((*MockImportSource)(nil).Next))
+}
+
+// Route mocks base method.
+func (m *MockImportSource) Route() entities.ImportedRoute {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Route")
+	ret0, _ := ret[0].(entities.ImportedRoute)
+	return ret0
+}
+
+// Route indicates an expected call of Route.
+func (mr *MockImportSourceMockRecorder) Route() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Route", reflect.TypeOf((*MockImportSource)(nil).Route))
+}
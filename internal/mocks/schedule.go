@@ -0,0 +1,99 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: schedule.go
+//
+// Generated by this command:
+//
+//	mockgen -source=schedule.go -destination=../mocks/schedule.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	entities "task/internal/entities"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockScheduleRepo is a mock of ScheduleRepo interface.
+type MockScheduleRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockScheduleRepoMockRecorder
+}
+
+// MockScheduleRepoMockRecorder is the mock recorder for MockScheduleRepo.
+type MockScheduleRepoMockRecorder struct {
+	mock *MockScheduleRepo
+}
+
+// NewMockScheduleRepo creates a new mock instance.
+func NewMockScheduleRepo(ctrl *gomock.Controller) *MockScheduleRepo {
+	mock := &MockScheduleRepo{ctrl: ctrl}
+	mock.recorder = &MockScheduleRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockScheduleRepo) EXPECT() *MockScheduleRepoMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockScheduleRepo) Delete(ctx context.Context, routeID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, routeID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockScheduleRepoMockRecorder) Delete(ctx, routeID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockScheduleRepo)(nil).Delete), ctx, routeID)
+}
+
+// Get mocks base method.
+func (m *MockScheduleRepo) Get(ctx context.Context, routeID int) (entities.Schedule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, routeID)
+	ret0, _ := ret[0].(entities.Schedule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockScheduleRepoMockRecorder) Get(ctx, routeID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockScheduleRepo)(nil).Get), ctx, routeID)
+}
+
+// List mocks base method.
+func (m *MockScheduleRepo) List(ctx context.Context, routeIDs []int) ([]entities.Schedule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, routeIDs)
+	ret0, _ := ret[0].([]entities.Schedule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockScheduleRepoMockRecorder) List(ctx, routeIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockScheduleRepo)(nil).List), ctx, routeIDs)
+}
+
+// Set mocks base method.
+func (m *MockScheduleRepo) Set(ctx context.Context, schedule entities.Schedule) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Set", ctx, schedule)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Set indicates an expected call of Set.
+func (mr *MockScheduleRepoMockRecorder) Set(ctx, schedule any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Set", reflect.TypeOf((*MockScheduleRepo)(nil).Set), ctx, schedule)
+}
@@ -0,0 +1,114 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: vehicle.go
+//
+// Generated by this command:
+//
+//	mockgen -source=vehicle.go -destination=../mocks/vehicle.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	entities "task/internal/entities"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockVehicleRepo is a mock of VehicleRepo interface.
+type MockVehicleRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockVehicleRepoMockRecorder
+}
+
+// MockVehicleRepoMockRecorder is the mock recorder for MockVehicleRepo.
+type MockVehicleRepoMockRecorder struct {
+	mock *MockVehicleRepo
+}
+
+// NewMockVehicleRepo creates a new mock instance.
+func NewMockVehicleRepo(ctrl *gomock.Controller) *MockVehicleRepo {
+	mock := &MockVehicleRepo{ctrl: ctrl}
+	mock.recorder = &MockVehicleRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockVehicleRepo) EXPECT() *MockVehicleRepoMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockVehicleRepo) Create(ctx context.Context, vehicle entities.Vehicle) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, vehicle)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockVehicleRepoMockRecorder) Create(ctx, vehicle any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockVehicleRepo)(nil).Create), ctx, vehicle)
+}
+
+// Delete mocks base method.
+func (m *MockVehicleRepo) Delete(ctx context.Context, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockVehicleRepoMockRecorder) Delete(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockVehicleRepo)(nil).Delete), ctx, id)
+}
+
+// GetById mocks base method.
+func (m *MockVehicleRepo) GetById(ctx context.Context, id int) (entities.Vehicle, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetById", ctx, id)
+	ret0, _ := ret[0].(entities.Vehicle)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetById indicates an expected call of GetById.
+func (mr *MockVehicleRepoMockRecorder) GetById(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetById", reflect.TypeOf((*MockVehicleRepo)(nil).GetById), ctx, id)
+}
+
+// List mocks base method.
+func (m *MockVehicleRepo) List(ctx context.Context) ([]entities.Vehicle, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx)
+	ret0, _ := ret[0].([]entities.Vehicle)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockVehicleRepoMockRecorder) List(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockVehicleRepo)(nil).List), ctx)
+}
+
+// Update mocks base method.
+func (m *MockVehicleRepo) Update(ctx context.Context, vehicle entities.Vehicle) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, vehicle)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockVehicleRepoMockRecorder) Update(ctx, vehicle any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockVehicleRepo)(nil).Update), ctx, vehicle)
+}
@@ -0,0 +1,243 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"task/internal/entities"
+)
+
+//go:generate mockgen -source=cargo.go -destination=../mocks/cargo.go -package=mocks
+type CargoTypeRepo interface {
+	Create(ctx context.Context, cargoType entities.CargoType) error
+	GetByCode(ctx context.Context, code string) (entities.CargoType, error)
+	List(ctx context.Context) ([]entities.CargoType, error)
+	Update(ctx context.Context, cargoType entities.CargoType) error
+	Delete(ctx context.Context, code string) error
+	// InUse reports whether any actual route has given cargo type.
+	InUse(ctx context.Context, code string) (bool, error)
+}
+
+type cargoTypeRepo struct {
+	db Querier
+}
+
+func NewCargoTypeRepo(db Querier) CargoTypeRepo {
+	return &cargoTypeRepo{
+		db: db,
+	}
+}
+
+const selectCargoTypes = `select
+		c.code,
+		c.display_name,
+		c.unit,
+		c.density,
+		c.hazard_class,
+		coalesce(array_agg(a.alias order by a.alias) filter (where a.alias is not null), '{}')
+	from cargo_types c
+		left join cargo_type_aliases a on a.code = c.code`
+
+// Create inserts cargo type with its aliases. Taken code or alias is reported
+// as unique violation, see IsUniqueViolation.
+func (r *cargoTypeRepo) Create(ctx context.Context, cargoType entities.CargoType) (err error) {
+	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			rollbackErr := tx.Rollback(ctx)
+			if rollbackErr != nil {
+				err = fmt.Errorf("rollback err: %w; handled err: %v", rollbackErr, err)
+			}
+		}
+	}()
+
+	_, err = tx.Exec(
+		ctx,
+		`insert into cargo_types(code, display_name, unit, density, hazard_class)
+			values($1, $2, $3, $4, $5)`,
+		cargoType.Code,
+		cargoType.DisplayName,
+		cargoType.Unit,
+		cargoType.Density,
+		cargoType.HazardClass,
+	)
+	if err != nil {
+		return fmt.Errorf("inserting cargo type: %w", err)
+	}
+
+	err = insertAliases(ctx, tx, cargoType)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (r *cargoTypeRepo) GetByCode(ctx context.Context, code string) (cargoType entities.CargoType, err error) {
+	err = r.db.QueryRow(
+		ctx,
+		selectCargoTypes+`
+			where c.code = $1
+			group by c.code`,
+		code,
+	).Scan(
+		&cargoType.Code,
+		&cargoType.DisplayName,
+		&cargoType.Unit,
+		&cargoType.Density,
+		&cargoType.HazardClass,
+		&cargoType.Aliases,
+	)
+	if err != nil {
+		return entities.CargoType{}, fmt.Errorf("getting cargo type by code: %w", err)
+	}
+
+	return cargoType, nil
+}
+
+func (r *cargoTypeRepo) List(ctx context.Context) (cargoTypes []entities.CargoType, err error) {
+	rows, err := r.db.Query(
+		ctx,
+		selectCargoTypes+`
+			group by c.code
+			order by c.code`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("listing cargo types: %w", err)
+	}
+	defer rows.Close()
+
+	cargoTypes = make([]entities.CargoType, 0)
+	for rows.Next() {
+		var cargoType entities.CargoType
+		err = rows.Scan(
+			&cargoType.Code,
+			&cargoType.DisplayName,
+			&cargoType.Unit,
+			&cargoType.Density,
+			&cargoType.HazardClass,
+			&cargoType.Aliases,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scanning cargo type: %w", err)
+		}
+		cargoTypes = append(cargoTypes, cargoType)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("listing cargo types: %w", err)
+	}
+
+	return cargoTypes, nil
+}
+
+// Update replaces attributes and aliases of existing cargo type.
+func (r *cargoTypeRepo) Update(ctx context.Context, cargoType entities.CargoType) (err error) {
+	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			rollbackErr := tx.Rollback(ctx)
+			if rollbackErr != nil {
+				err = fmt.Errorf("rollback err: %w; handled err: %v", rollbackErr, err)
+			}
+		}
+	}()
+
+	tag, err := tx.Exec(
+		ctx,
+		`update cargo_types set
+				display_name = $2,
+				unit = $3,
+				density = $4,
+				hazard_class = $5
+			where code = $1`,
+		cargoType.Code,
+		cargoType.DisplayName,
+		cargoType.Unit,
+		cargoType.Density,
+		cargoType.HazardClass,
+	)
+	if err != nil {
+		return fmt.Errorf("updating cargo type: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("updating cargo type: %w", pgx.ErrNoRows)
+	}
+
+	_, err = tx.Exec(ctx, `delete from cargo_type_aliases where code = $1`, cargoType.Code)
+	if err != nil {
+		return fmt.Errorf("deleting cargo type aliases: %w", err)
+	}
+
+	err = insertAliases(ctx, tx, cargoType)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func insertAliases(ctx context.Context, tx pgx.Tx, cargoType entities.CargoType) (err error) {
+	if len(cargoType.Aliases) == 0 {
+		return nil
+	}
+
+	_, err = tx.Exec(
+		ctx,
+		`insert into cargo_type_aliases(alias, code)
+			select unnest($2::varchar[]), $1`,
+		cargoType.Code,
+		cargoType.Aliases,
+	)
+	if err != nil {
+		return fmt.Errorf("inserting cargo type aliases: %w", err)
+	}
+
+	return nil
+}
+
+func (r *cargoTypeRepo) Delete(ctx context.Context, code string) (err error) {
+	tag, err := r.db.Exec(ctx, `delete from cargo_types where code = $1`, code)
+	if err != nil {
+		return fmt.Errorf("deleting cargo type: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("deleting cargo type: %w", pgx.ErrNoRows)
+	}
+
+	return nil
+}
+
+func (r *cargoTypeRepo) InUse(ctx context.Context, code string) (inUse bool, err error) {
+	err = r.db.QueryRow(
+		ctx,
+		`select exists(
+			select 1
+			from routes
+			where cargo_type = $1 and is_actual
+		)`,
+		code,
+	).Scan(&inUse)
+	if err != nil {
+		return false, fmt.Errorf("checking cargo type usage: %w", err)
+	}
+
+	return inUse, nil
+}
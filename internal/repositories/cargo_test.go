@@ -0,0 +1,76 @@
+package repositories
+
+import (
+	"context"
+	"github.com/stretchr/testify/require"
+	"task/internal/entities"
+	"testing"
+)
+
+func TestCargoTypes(t *testing.T) {
+	repo := NewCargoTypeRepo(testDbInstance)
+	ctx := context.Background()
+
+	sand := entities.CargoType{
+		Code:        "sand",
+		DisplayName: "Sand",
+		Unit:        entities.UnitTonne,
+		Aliases:     []string{"quartz sand", "beach sand"},
+	}
+	petrol := entities.CargoType{
+		Code:        "petrol",
+		DisplayName: "Petrol",
+		Unit:        entities.UnitLitre,
+		Density:     745,
+		HazardClass: "3",
+	}
+
+	err := repo.Create(ctx, sand)
+	require.Nil(t, err)
+	err = repo.Create(ctx, petrol)
+	require.Nil(t, err)
+
+	err = repo.Create(ctx, entities.CargoType{Code: "sandstone", DisplayName: "Sandstone", Unit: entities.UnitTonne, Aliases: []string{"beach sand"}})
+	require.True(t, IsUniqueViolation(err))
+
+	found, err := repo.GetByCode(ctx, "sand")
+	require.Nil(t, err)
+	sand.Aliases = []string{"beach sand", "quartz sand"}
+	require.Equal(t, sand, found)
+
+	petrol.Aliases = []string{}
+	cargoTypes, err := repo.List(ctx)
+	require.Nil(t, err)
+	require.Equal(t, []entities.CargoType{petrol, sand}, cargoTypes)
+
+	sand.HazardClass = "9"
+	sand.Density = 1600
+	sand.Aliases = []string{"river sand"}
+	err = repo.Update(ctx, sand)
+	require.Nil(t, err)
+
+	found, err = repo.GetByCode(ctx, "sand")
+	require.Nil(t, err)
+	require.Equal(t, sand, found)
+
+	err = repo.Update(ctx, entities.CargoType{Code: "water", DisplayName: "Water", Unit: entities.UnitLitre})
+	require.ErrorIs(t, err, ErrNotFound)
+
+	// routes of test data refer to cargo1..cargo6
+	inUse, err := repo.InUse(ctx, "cargo1")
+	require.Nil(t, err)
+	require.True(t, inUse)
+
+	inUse, err = repo.InUse(ctx, "sand")
+	require.Nil(t, err)
+	require.False(t, inUse)
+
+	err = repo.Delete(ctx, "petrol")
+	require.Nil(t, err)
+
+	err = repo.Delete(ctx, "petrol")
+	require.ErrorIs(t, err, ErrNotFound)
+
+	_, err = repo.GetByCode(ctx, "petrol")
+	require.ErrorIs(t, err, ErrNotFound)
+}
@@ -0,0 +1,32 @@
+package repositories_test
+
+import (
+	"context"
+	"github.com/stretchr/testify/require"
+	"task/internal/entities"
+	"task/internal/repositories"
+	"task/internal/repositories/repotest"
+	"task/pkg/decimal"
+	"testing"
+)
+
+// TestRouteRepoContract runs after tests of the package and truncates their data.
+func TestRouteRepoContract(t *testing.T) {
+	db := repositories.SharedDB()
+
+	repotest.TestRouteRepo(t, func(t *testing.T) repositories.RouteRepo {
+		ctx := context.Background()
+		_, err := db.Exec(ctx, `truncate routes, route_versions, vehicles restart identity cascade`)
+		require.Nil(t, err)
+
+		vehicleID, err := repositories.NewVehicleRepo(db).Create(ctx, entities.Vehicle{
+			Name:     "Truck",
+			Capacity: decimal.MustParse("1000"),
+			Status:   entities.VehicleAvailable,
+		})
+		require.Nil(t, err)
+		require.Equal(t, repotest.VehicleID, vehicleID)
+
+		return repositories.NewRouteRepo(db)
+	})
+}
@@ -0,0 +1,78 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// ErrNotFound is returned when requested entity does not exist. It is pgx.ErrNoRows,
+// so errors of postgres queries need no translation.
+var ErrNotFound = pgx.ErrNoRows
+
+// Querier is the part of pgx API used by repositories. It is implemented by *pgxpool.Pool,
+// which is safe for concurrent use, as well as by *pgx.Conn.
+type Querier interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	BeginTx(ctx context.Context, txOptions pgx.TxOptions) (pgx.Tx, error)
+}
+
+// Result codes of SQLite errors, see https://www.sqlite.org/rescode.html.
+const (
+	sqliteBusy                 = 5
+	sqliteLocked               = 6
+	sqliteConstraintPrimaryKey = 1555
+	sqliteConstraintUnique     = 2067
+)
+
+// sqliteError is implemented by errors of SQLite driver. Code returns extended result code.
+type sqliteError interface {
+	error
+	Code() int
+}
+
+// IsTransient reports whether operation failed with error which may disappear on retry:
+// connection problems, timeouts, serialization failures and deadlocks, or locked SQLite database.
+func IsTransient(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var liteErr sqliteError
+	if errors.As(err, &liteErr) {
+		code := liteErr.Code() & 0xff
+		return code == sqliteBusy || code == sqliteLocked
+	}
+
+	if pgconn.SafeToRetry(err) || pgconn.Timeout(err) {
+		return true
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && len(pgErr.Code) >= 2 {
+		switch pgErr.Code[:2] {
+		case "08", // connection exception
+			"40", // transaction rollback
+			"53": // insufficient resources
+			return true
+		}
+	}
+
+	var connErr *pgconn.ConnectError
+	return errors.As(err, &connErr)
+}
+
+// IsUniqueViolation reports whether operation failed because of unique or primary key constraint.
+func IsUniqueViolation(err error) bool {
+	var liteErr sqliteError
+	if errors.As(err, &liteErr) {
+		code := liteErr.Code()
+		return code == sqliteConstraintPrimaryKey || code == sqliteConstraintUnique
+	}
+
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
@@ -0,0 +1,37 @@
+package repositories
+
+import (
+	"fmt"
+	"github.com/jackc/pgx/v5/pgtype"
+	"math/big"
+	"task/pkg/decimal"
+)
+
+// decimalColumn is decimal.Decimal stored in numeric column.
+type decimalColumn decimal.Decimal
+
+func decimalArg(d decimal.Decimal) pgtype.Numeric {
+	coef, exp := d.Coef()
+	return pgtype.Numeric{Int: big.NewInt(coef), Exp: exp, Valid: true}
+}
+
+func (c *decimalColumn) ScanNumeric(v pgtype.Numeric) error {
+	if !v.Valid {
+		return fmt.Errorf("cannot scan NULL into decimal")
+	}
+	if v.NaN || v.InfinityModifier != pgtype.Finite {
+		return fmt.Errorf("cannot scan non-finite numeric into decimal")
+	}
+	if v.Int == nil {
+		*c = decimalColumn{}
+		return nil
+	}
+
+	d, err := decimal.NewFromBig(v.Int, v.Exp)
+	if err != nil {
+		return fmt.Errorf("scanning decimal: %w", err)
+	}
+
+	*c = decimalColumn(d)
+	return nil
+}
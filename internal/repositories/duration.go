@@ -0,0 +1,23 @@
+package repositories
+
+import (
+	"fmt"
+	"github.com/jackc/pgx/v5/pgtype"
+	"time"
+)
+
+// secondsColumn is time.Duration stored in integer column as whole seconds.
+type secondsColumn time.Duration
+
+func secondsArg(d time.Duration) int64 {
+	return int64(d / time.Second)
+}
+
+func (c *secondsColumn) ScanInt64(v pgtype.Int8) error {
+	if !v.Valid {
+		return fmt.Errorf("cannot scan NULL into duration")
+	}
+
+	*c = secondsColumn(time.Duration(v.Int64) * time.Second)
+	return nil
+}
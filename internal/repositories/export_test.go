@@ -0,0 +1,8 @@
+package repositories
+
+import "github.com/jackc/pgx/v5/pgxpool"
+
+// SharedDB returns database of package tests to external test package.
+func SharedDB() *pgxpool.Pool {
+	return testDbInstance
+}
@@ -0,0 +1,226 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"task/internal/entities"
+)
+
+// importMerge merges staged routes into routes with the same conflict semantics as Register:
+// a taken id marks the stored route as not actual and the imported one gets a new id. Reissued
+// ids are numbered in the order of lines above both stored and imported ids, so they never collide.
+var importMerge = []struct {
+	name  string
+	query string
+}{
+	{
+		name: "assigning route ids",
+		query: `with max_id as (
+				select greatest(
+					(select coalesce(max(route_id), 0) from routes),
+					(select coalesce(max(route_id), 0) from routes_staging)
+				) as route_id
+			), ranked as (
+				select
+					s.line,
+					row_number() over (partition by s.route_id order by s.line) = 1
+						and not exists (select 1 from routes r where r.route_id = s.route_id) as free
+				from routes_staging s
+			), numbered as (
+				select line, free, count(*) filter (where not free) over (order by line) as reissue_no
+				from ranked
+			)
+			update routes_staging s set
+				new_route_id = case when n.free then s.route_id else m.route_id + n.reissue_no end
+			from numbered n, max_id m
+			where n.line = s.line`,
+	},
+	{
+		// the same chain walk as in recordVersion, for all stored routes losing their ids at once
+		name: "finding last versions of superseded routes",
+		query: `with recursive chain(route_id, version_id, superseded_by) as (
+				select v.route_id, v.version_id, v.superseded_by
+				from route_versions v
+				where v.version_id in (
+					select max(version_id)
+					from route_versions
+					where route_id in (select route_id from routes_staging where new_route_id <> route_id)
+					group by route_id
+				)
+				union all
+				select c.route_id, v.version_id, v.superseded_by
+				from route_versions v
+					join chain c on v.version_id = c.superseded_by
+			), tails as (
+				select route_id, max(version_id) as version_id
+				from chain
+				where superseded_by is null
+				group by route_id
+			)
+			update routes_staging s set prev_version_id = t.version_id
+			from tails t
+			where s.route_id = t.route_id
+				and s.new_route_id <> s.route_id
+				and s.line = (select min(line) from routes_staging f where f.route_id = s.route_id)`,
+	},
+	{
+		// as in recordVersion, route owning the last version of the chain is superseded too
+		name: "superseding stored routes",
+		query: `update routes set is_actual = false
+			where route_id in (select route_id from routes_staging where new_route_id <> route_id)
+				or route_id in (
+					select v.route_id
+					from route_versions v
+						join routes_staging s on s.prev_version_id = v.version_id
+				)`,
+	},
+	{
+		// imported route is not actual if a later line requests the same id, since that line supersedes it
+		name: "inserting routes",
+		query: `insert into routes(route_id, route_name, load, cargo_type, waypoints, distance_m, duration_s, is_actual)
+			select
+				s.new_route_id,
+				s.route_name,
+				s.load,
+				s.cargo_type,
+				s.waypoints,
+				s.distance_m,
+				s.duration_s,
+				not exists (
+					select 1
+					from routes_staging later
+					where later.route_id = s.route_id and later.line > s.line
+				)
+			from routes_staging s
+			order by s.line`,
+	},
+	{
+		name: "inserting route versions",
+		query: `with inserted as (
+				insert into route_versions(route_id, route_name, load, cargo_type, waypoints)
+					select new_route_id, route_name, load, cargo_type, waypoints
+					from routes_staging
+					order by line
+					returning version_id, route_id
+			)
+			update routes_staging s set version_id = i.version_id
+			from inserted i
+			where i.route_id = s.new_route_id`,
+	},
+	{
+		// reissued route supersedes previous line with the same id, or the stored route for the first one
+		name: "superseding route versions",
+		query: `with links as (
+				select
+					coalesce(lag(version_id) over (partition by route_id order by line), prev_version_id) as prev_id,
+					version_id,
+					new_route_id <> route_id as reissued
+				from routes_staging
+			)
+			update route_versions v set
+				superseded_by = l.version_id,
+				superseded_at = now()
+			from links l
+			where l.reissued and v.version_id = l.prev_id`,
+	},
+}
+
+// Import loads routes into staging table with COPY and then merges them into routes with a few
+// set-based statements, see importMerge. Import is atomic.
+func (r *routeRepo) Import(ctx context.Context, src ImportSource) (report entities.ImportReport, err error) {
+	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return entities.ImportReport{}, fmt.Errorf("begin transaction: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			rollbackErr := tx.Rollback(ctx)
+			if rollbackErr != nil {
+				err = fmt.Errorf("rollback err: %w; handled err: %v", rollbackErr, err)
+			}
+		}
+	}()
+
+	_, err = tx.Exec(
+		ctx,
+		`create temp table routes_staging(
+			line int primary key,
+			route_id int not null,
+			route_name varchar(128) not null,
+			load numeric not null,
+			cargo_type varchar(64) not null,
+			waypoints jsonb not null,
+			distance_m double precision not null,
+			duration_s bigint not null,
+			new_route_id int,
+			version_id bigint,
+			prev_version_id bigint
+		) on commit drop`,
+	)
+	if err != nil {
+		return entities.ImportReport{}, fmt.Errorf("creating staging table: %w", err)
+	}
+
+	_, err = tx.CopyFrom(
+		ctx,
+		pgx.Identifier{"routes_staging"},
+		[]string{"line", "route_id", "route_name", "load", "cargo_type", "waypoints", "distance_m", "duration_s"},
+		&copySource{src: src},
+	)
+	if err != nil {
+		return entities.ImportReport{}, fmt.Errorf("copying routes to staging table: %w", err)
+	}
+
+	for _, step := range importMerge {
+		_, err = tx.Exec(ctx, step.query)
+		if err != nil {
+			return entities.ImportReport{}, fmt.Errorf("%s: %w", step.name, err)
+		}
+	}
+
+	err = tx.QueryRow(
+		ctx,
+		`select count(*), count(*) filter (where new_route_id <> route_id)
+			from routes_staging`,
+	).Scan(&report.Imported, &report.Reissued)
+	if err != nil {
+		return entities.ImportReport{}, fmt.Errorf("counting imported routes: %w", err)
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return entities.ImportReport{}, fmt.Errorf("commit transaction: %w", err)
+	}
+	r.bumpRevision(ctx)
+
+	return report, nil
+}
+
+// copySource adapts ImportSource to pgx.CopyFromSource.
+type copySource struct {
+	src ImportSource
+}
+
+func (c *copySource) Next() bool {
+	return c.src.Next()
+}
+
+func (c *copySource) Values() ([]any, error) {
+	row := c.src.Route()
+	return []any{
+		row.Line,
+		row.Route.RouteID,
+		row.Route.RouteName,
+		decimalArg(row.Route.Load),
+		row.Route.CargoType,
+		waypointsArg(row.Route.Waypoints),
+		row.Route.Distance,
+		secondsArg(row.Route.Duration),
+	}, nil
+}
+
+func (c *copySource) Err() error {
+	return c.src.Err()
+}
@@ -0,0 +1,299 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"task/internal/entities"
+)
+
+//go:generate mockgen -source=job.go -destination=../mocks/job.go -package=mocks
+type JobRepo interface {
+	CreateDeleteJob(ctx context.Context, ids []int) (int64, error)
+	GetDeleteJob(ctx context.Context, id int64) (entities.DeleteJob, error)
+	ListUnfinishedDeleteJobs(ctx context.Context) ([]entities.DeleteJob, error)
+	StartDeleteJob(ctx context.Context, id int64) error
+	FinishDeleteJob(ctx context.Context, id int64, deletedIds []int) error
+	FailDeleteJob(ctx context.Context, id int64, jobErr string) error
+}
+
+type jobRepo struct {
+	db Querier
+}
+
+func NewJobRepo(db Querier) JobRepo {
+	return &jobRepo{
+		db: db,
+	}
+}
+
+func (r *jobRepo) CreateDeleteJob(ctx context.Context, ids []int) (jobId int64, err error) {
+	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return 0, fmt.Errorf("begin transaction: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			rollbackErr := tx.Rollback(ctx)
+			if rollbackErr != nil {
+				err = fmt.Errorf("rollback err: %w; handled err: %v", rollbackErr, err)
+			}
+		}
+	}()
+
+	err = tx.QueryRow(
+		ctx,
+		`insert into delete_jobs(status)
+			values($1)
+			returning job_id`,
+		entities.JobPending,
+	).Scan(&jobId)
+	if err != nil {
+		return 0, fmt.Errorf("creating delete job: %w", err)
+	}
+
+	_, err = tx.Exec(
+		ctx,
+		`insert into delete_job_items(job_id, route_id, status)
+			select $1, unnest($2::int[]), $3
+			on conflict do nothing`,
+		jobId,
+		ids,
+		entities.DeletePending,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("creating delete job items: %w", err)
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return jobId, nil
+}
+
+func (r *jobRepo) GetDeleteJob(ctx context.Context, id int64) (job entities.DeleteJob, err error) {
+	var jobErr *string
+	err = r.db.QueryRow(
+		ctx,
+		`select
+				job_id,
+				status,
+				attempts,
+				error,
+				created_at,
+				updated_at
+			from delete_jobs
+			where job_id = $1`,
+		id,
+	).Scan(
+		&job.JobID,
+		&job.Status,
+		&job.Attempts,
+		&jobErr,
+		&job.CreatedAt,
+		&job.UpdatedAt,
+	)
+	if err != nil {
+		return entities.DeleteJob{}, fmt.Errorf("getting delete job by id: %w", err)
+	}
+	if jobErr != nil {
+		job.Error = *jobErr
+	}
+
+	job.Items, err = r.jobItems(ctx, id)
+	if err != nil {
+		return entities.DeleteJob{}, err
+	}
+
+	return job, nil
+}
+
+func (r *jobRepo) ListUnfinishedDeleteJobs(ctx context.Context) (jobs []entities.DeleteJob, err error) {
+	rows, err := r.db.Query(
+		ctx,
+		`select job_id
+			from delete_jobs
+			where status in ($1, $2)
+			order by job_id`,
+		entities.JobPending,
+		entities.JobRunning,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("listing unfinished delete jobs: %w", err)
+	}
+
+	ids, err := pgx.CollectRows(rows, pgx.RowTo[int64])
+	if err != nil {
+		return nil, fmt.Errorf("listing unfinished delete jobs: %w", err)
+	}
+
+	for _, id := range ids {
+		job, err := r.GetDeleteJob(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+
+	return jobs, nil
+}
+
+func (r *jobRepo) StartDeleteJob(ctx context.Context, id int64) (err error) {
+	tag, err := r.db.Exec(
+		ctx,
+		`update delete_jobs set
+				status = $2,
+				attempts = attempts + 1,
+				updated_at = now()
+			where job_id = $1`,
+		id,
+		entities.JobRunning,
+	)
+	if err != nil {
+		return fmt.Errorf("starting delete job: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("starting delete job: %w", pgx.ErrNoRows)
+	}
+
+	return nil
+}
+
+func (r *jobRepo) FinishDeleteJob(ctx context.Context, id int64, deletedIds []int) (err error) {
+	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			rollbackErr := tx.Rollback(ctx)
+			if rollbackErr != nil {
+				err = fmt.Errorf("rollback err: %w; handled err: %v", rollbackErr, err)
+			}
+		}
+	}()
+
+	_, err = tx.Exec(
+		ctx,
+		`update delete_job_items set
+				status = case when route_id = any($2) then $3 else $4 end
+			where job_id = $1`,
+		id,
+		deletedIds,
+		entities.DeleteDone,
+		entities.DeleteNotFound,
+	)
+	if err != nil {
+		return fmt.Errorf("updating delete job items: %w", err)
+	}
+
+	err = r.setStatus(ctx, tx, id, entities.JobSucceeded, nil)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (r *jobRepo) FailDeleteJob(ctx context.Context, id int64, jobErr string) (err error) {
+	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			rollbackErr := tx.Rollback(ctx)
+			if rollbackErr != nil {
+				err = fmt.Errorf("rollback err: %w; handled err: %v", rollbackErr, err)
+			}
+		}
+	}()
+
+	_, err = tx.Exec(
+		ctx,
+		`update delete_job_items set
+				status = $2
+			where job_id = $1`,
+		id,
+		entities.DeleteFailed,
+	)
+	if err != nil {
+		return fmt.Errorf("updating delete job items: %w", err)
+	}
+
+	err = r.setStatus(ctx, tx, id, entities.JobFailed, &jobErr)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (r *jobRepo) setStatus(ctx context.Context, tx pgx.Tx, id int64, status entities.JobStatus, jobErr *string) error {
+	tag, err := tx.Exec(
+		ctx,
+		`update delete_jobs set
+				status = $2,
+				error = $3,
+				updated_at = now()
+			where job_id = $1`,
+		id,
+		status,
+		jobErr,
+	)
+	if err != nil {
+		return fmt.Errorf("updating delete job status: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("updating delete job status: %w", pgx.ErrNoRows)
+	}
+
+	return nil
+}
+
+func (r *jobRepo) jobItems(ctx context.Context, id int64) (items []entities.DeleteJobItem, err error) {
+	rows, err := r.db.Query(
+		ctx,
+		`select
+				route_id,
+				status
+			from delete_job_items
+			where job_id = $1
+			order by route_id`,
+		id,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("getting delete job items: %w", err)
+	}
+	defer rows.Close()
+
+	items = make([]entities.DeleteJobItem, 0)
+	for rows.Next() {
+		var item entities.DeleteJobItem
+		err = rows.Scan(&item.RouteID, &item.Status)
+		if err != nil {
+			return nil, fmt.Errorf("scanning delete job item: %w", err)
+		}
+		items = append(items, item)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("getting delete job items: %w", err)
+	}
+
+	return items, nil
+}
@@ -0,0 +1,64 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"task/internal/entities"
+	"testing"
+)
+
+func TestDeleteJob(t *testing.T) {
+	repo := NewJobRepo(testDbInstance)
+	ctx := context.Background()
+
+	jobId, err := repo.CreateDeleteJob(ctx, []int{10, 11, 11})
+	require.Nil(t, err)
+
+	job, err := repo.GetDeleteJob(ctx, jobId)
+	require.Nil(t, err)
+	require.Equal(t, entities.JobPending, job.Status)
+	require.Equal(t, []entities.DeleteJobItem{
+		{RouteID: 10, Status: entities.DeletePending},
+		{RouteID: 11, Status: entities.DeletePending},
+	}, job.Items)
+
+	unfinished, err := repo.ListUnfinishedDeleteJobs(ctx)
+	require.Nil(t, err)
+	require.Len(t, unfinished, 1)
+	require.Equal(t, jobId, unfinished[0].JobID)
+
+	err = repo.StartDeleteJob(ctx, jobId)
+	require.Nil(t, err)
+
+	err = repo.FinishDeleteJob(ctx, jobId, []int{11})
+	require.Nil(t, err)
+
+	job, err = repo.GetDeleteJob(ctx, jobId)
+	require.Nil(t, err)
+	require.Equal(t, entities.JobSucceeded, job.Status)
+	require.Equal(t, 1, job.Attempts)
+	require.Equal(t, []entities.DeleteJobItem{
+		{RouteID: 10, Status: entities.DeleteNotFound},
+		{RouteID: 11, Status: entities.DeleteDone},
+	}, job.Items)
+
+	failedJobId, err := repo.CreateDeleteJob(ctx, []int{12})
+	require.Nil(t, err)
+
+	err = repo.FailDeleteJob(ctx, failedJobId, "some error")
+	require.Nil(t, err)
+
+	job, err = repo.GetDeleteJob(ctx, failedJobId)
+	require.Nil(t, err)
+	require.Equal(t, entities.JobFailed, job.Status)
+	require.Equal(t, "some error", job.Error)
+	require.Equal(t, entities.DeleteFailed, job.Items[0].Status)
+
+	unfinished, err = repo.ListUnfinishedDeleteJobs(ctx)
+	require.Nil(t, err)
+	require.Empty(t, unfinished)
+
+	_, err = repo.GetDeleteJob(ctx, 1000)
+	require.Equal(t, fmt.Errorf("getting delete job by id: no rows in result set").Error(), err.Error())
+}
@@ -0,0 +1,462 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"task/internal/entities"
+	"time"
+	"unicode/utf8"
+)
+
+// Column limits of routes table, checked by memoryRouteRepo the same way database does.
+const (
+	maxRouteNameLength = 128
+	maxCargoTypeLength = 64
+)
+
+// memoryRouteRepo keeps routes in process memory. It has the same semantics as routeRepo,
+// including conflict resolution of Register and version history, and is safe for concurrent use.
+// It lets services and handlers run without database, e.g. in tests.
+type memoryRouteRepo struct {
+	mu     sync.RWMutex
+	routes map[int]entities.Route
+	// versions are ordered by id, version with id N is at index N-1
+	versions []entities.RouteVersion
+}
+
+func NewMemoryRouteRepo() RouteRepo {
+	return &memoryRouteRepo{
+		routes: make(map[int]entities.Route),
+	}
+}
+
+func (r *memoryRouteRepo) Register(ctx context.Context, route entities.Route) (routeId int, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	err = checkRouteColumns(route)
+	if err != nil {
+		return 0, fmt.Errorf("register route: %w", err)
+	}
+
+	return r.register(route, time.Now()), nil
+}
+
+// RegisterBatch registers all routes or none of them.
+func (r *memoryRouteRepo) RegisterBatch(ctx context.Context, routes []entities.Route) (routeIds []int, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, route := range routes {
+		err = checkRouteColumns(route)
+		if err != nil {
+			return nil, fmt.Errorf("route #%d: register route: %w", i, err)
+		}
+	}
+
+	now := time.Now()
+	routeIds = make([]int, 0, len(routes))
+	for _, route := range routes {
+		routeIds = append(routeIds, r.register(route, now))
+	}
+
+	return routeIds, nil
+}
+
+// register stores valid route. If route id is already taken, existing route is marked as not actual
+// and the new one gets next free id.
+func (r *memoryRouteRepo) register(route entities.Route, now time.Time) (routeId int) {
+	routeId = route.RouteID
+	supersededRouteId := -1
+	if existing, ok := r.routes[route.RouteID]; ok {
+		existing.IsActual = false
+		r.routes[route.RouteID] = existing
+
+		for id := range r.routes {
+			routeId = max(routeId, id+1)
+		}
+		supersededRouteId = route.RouteID
+	}
+
+	route = cloneRoute(route)
+	route.RouteID = routeId
+	route.IsActual = true
+	route.VehicleID = nil
+	r.routes[routeId] = route
+
+	r.recordVersion(route, supersededRouteId, now)
+
+	return routeId
+}
+
+func (r *memoryRouteRepo) GetById(ctx context.Context, id int) (route entities.Route, err error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	route, ok := r.routes[id]
+	if !ok {
+		return entities.Route{}, fmt.Errorf("getting route by id: %w", ErrNotFound)
+	}
+
+	return cloneRoute(route), nil
+}
+
+func (r *memoryRouteRepo) List(ctx context.Context, filter entities.RouteFilter) (routes []entities.Route, err error) {
+	routes = r.filter(filter)
+	if routes == nil {
+		routes = make([]entities.Route, 0)
+	}
+
+	return routes, nil
+}
+
+// Export calls fn for every route matching the filter. Routes are read at once, so fn may use the repository.
+func (r *memoryRouteRepo) Export(ctx context.Context, filter entities.RouteFilter, fn func(entities.Route) error) (err error) {
+	for _, route := range r.filter(filter) {
+		err = fn(route)
+		if err != nil {
+			return fmt.Errorf("exporting routes: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// filter returns copies of routes matching the filter ordered by id.
+func (r *memoryRouteRepo) filter(filter entities.RouteFilter) (routes []entities.Route) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, id := range r.sortedIds() {
+		route := r.routes[id]
+		if !matchesFilter(route, filter) {
+			continue
+		}
+
+		routes = append(routes, cloneRoute(route))
+		if filter.Limit > 0 && len(routes) == filter.Limit {
+			break
+		}
+	}
+
+	return routes
+}
+
+func matchesFilter(route entities.Route, filter entities.RouteFilter) bool {
+	switch {
+	case filter.RouteIDs != nil && !slices.Contains(filter.RouteIDs, route.RouteID),
+		filter.AfterID != nil && route.RouteID <= *filter.AfterID,
+		filter.CargoType != "" && route.CargoType != filter.CargoType,
+		filter.IsActual != nil && route.IsActual != *filter.IsActual,
+		filter.MinLoad != nil && route.Load.Cmp(*filter.MinLoad) < 0,
+		filter.MaxLoad != nil && route.Load.Cmp(*filter.MaxLoad) > 0,
+		filter.NamePrefix != "" && !strings.HasPrefix(route.RouteName, filter.NamePrefix),
+		filter.VehicleID != nil && (route.VehicleID == nil || *route.VehicleID != *filter.VehicleID):
+		return false
+	default:
+		return true
+	}
+}
+
+func (r *memoryRouteRepo) Update(ctx context.Context, route entities.Route) (err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	err = checkRouteColumns(route)
+	if err != nil {
+		return fmt.Errorf("updating route: %w", err)
+	}
+
+	existing, ok := r.routes[route.RouteID]
+	if !ok || !existing.IsActual {
+		return fmt.Errorf("updating route: %w", ErrNotFound)
+	}
+
+	existing.RouteName = route.RouteName
+	existing.Load = route.Load
+	existing.CargoType = route.CargoType
+	existing.Waypoints = cloneWaypoints(route.Waypoints)
+	existing.Distance = route.Distance
+	existing.Duration = route.Duration
+	r.routes[route.RouteID] = existing
+
+	r.recordVersion(existing, route.RouteID, time.Now())
+
+	return nil
+}
+
+// Assign binds vehicle to actual route. Memory storage has no vehicles, so the vehicle is not checked.
+func (r *memoryRouteRepo) Assign(ctx context.Context, routeID int, vehicleID int) (err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	route, ok := r.routes[routeID]
+	if !ok || !route.IsActual {
+		return fmt.Errorf("assigning vehicle: %w", ErrNotFound)
+	}
+
+	route.VehicleID = &vehicleID
+	r.routes[routeID] = route
+
+	return nil
+}
+
+func (r *memoryRouteRepo) Stats(ctx context.Context) (stats []entities.RouteStats, err error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	routes := make([]entities.Route, 0, len(r.routes))
+	for _, route := range r.routes {
+		routes = append(routes, route)
+	}
+
+	stats, err = entities.AggregateStats(routes)
+	if err != nil {
+		return nil, fmt.Errorf("getting route stats: %w", err)
+	}
+
+	return stats, nil
+}
+
+func (r *memoryRouteRepo) History(ctx context.Context, id int) (versions []entities.RouteVersion, err error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	predecessors := make(map[int64][]int64)
+	linked := make(map[int64]bool)
+	var queue []int64
+	for _, version := range r.versions {
+		if version.SupersededBy != nil {
+			predecessors[*version.SupersededBy] = append(predecessors[*version.SupersededBy], version.VersionID)
+		}
+		if version.Route.RouteID == id {
+			linked[version.VersionID] = true
+			queue = append(queue, version.VersionID)
+		}
+	}
+
+	// versions of the route are linked with their predecessors and successors transitively
+	for len(queue) > 0 {
+		versionId := queue[0]
+		queue = queue[1:]
+
+		next := predecessors[versionId]
+		if supersededBy := r.versions[versionId-1].SupersededBy; supersededBy != nil {
+			next = append(slices.Clip(next), *supersededBy)
+		}
+		for _, nextId := range next {
+			if !linked[nextId] {
+				linked[nextId] = true
+				queue = append(queue, nextId)
+			}
+		}
+	}
+
+	if len(linked) == 0 {
+		return nil, fmt.Errorf("getting route history: %w", ErrNotFound)
+	}
+
+	for _, version := range r.versions {
+		if linked[version.VersionID] {
+			versions = append(versions, cloneVersion(version))
+		}
+	}
+	slices.SortStableFunc(versions, func(a, b entities.RouteVersion) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+
+	return versions, nil
+}
+
+// recordVersion appends route snapshot to versions. If supersededRouteId is non-negative,
+// the last version in the chain of that route is linked to the new one, and the route owning
+// that version is marked as not actual unless it is the route being recorded.
+func (r *memoryRouteRepo) recordVersion(route entities.Route, supersededRouteId int, now time.Time) {
+	tail := -1
+	if supersededRouteId >= 0 {
+		for i, version := range r.versions {
+			if version.Route.RouteID == supersededRouteId {
+				tail = i
+			}
+		}
+		for tail >= 0 && r.versions[tail].SupersededBy != nil {
+			tail = int(*r.versions[tail].SupersededBy - 1)
+		}
+	}
+
+	versionId := int64(len(r.versions) + 1)
+	r.versions = append(r.versions, entities.RouteVersion{
+		VersionID: versionId,
+		Route: entities.Route{
+			RouteID:   route.RouteID,
+			RouteName: route.RouteName,
+			Load:      route.Load,
+			CargoType: route.CargoType,
+			Waypoints: cloneWaypoints(route.Waypoints),
+		},
+		CreatedAt: now,
+	})
+
+	if tail >= 0 {
+		r.versions[tail].SupersededBy = &versionId
+		r.versions[tail].SupersededAt = &now
+
+		tailRouteId := r.versions[tail].Route.RouteID
+		if tailRoute, ok := r.routes[tailRouteId]; ok && tailRouteId != route.RouteID {
+			tailRoute.IsActual = false
+			r.routes[tailRouteId] = tailRoute
+		}
+	}
+}
+
+// DeleteById deletes routes and returns ids of those which existed. History of routes is kept.
+func (r *memoryRouteRepo) DeleteById(ctx context.Context, ids []int) (deletedIds []int, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	deletedIds = make([]int, 0, len(ids))
+	for _, id := range ids {
+		if _, ok := r.routes[id]; ok {
+			delete(r.routes, id)
+			deletedIds = append(deletedIds, id)
+		}
+	}
+
+	return deletedIds, nil
+}
+
+// Import reads all routes of the source and registers them in the order of lines. Import is atomic.
+func (r *memoryRouteRepo) Import(ctx context.Context, src ImportSource) (report entities.ImportReport, err error) {
+	var rows []entities.ImportedRoute
+	for src.Next() {
+		rows = append(rows, src.Route())
+	}
+	if err = src.Err(); err != nil {
+		return entities.ImportReport{}, fmt.Errorf("reading import source: %w", err)
+	}
+	slices.SortStableFunc(rows, func(a, b entities.ImportedRoute) int {
+		return a.Line - b.Line
+	})
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, row := range rows {
+		err = checkRouteColumns(row.Route)
+		if err != nil {
+			return entities.ImportReport{}, fmt.Errorf("line %d: register route: %w", row.Line, err)
+		}
+	}
+
+	now := time.Now()
+	for _, row := range rows {
+		routeId := r.register(row.Route, now)
+
+		report.Imported++
+		if routeId != row.Route.RouteID {
+			report.Reissued++
+		}
+	}
+
+	return report, nil
+}
+
+// Near returns actual routes passing within radius meters of the point, ordered by id.
+func (r *memoryRouteRepo) Near(ctx context.Context, point entities.Waypoint, radius float64) ([]entities.Route, error) {
+	return r.actualMatching(func(route entities.Route) bool {
+		return route.PassesNear(point, radius)
+	}), nil
+}
+
+// Within returns actual routes passing through the box, ordered by id.
+func (r *memoryRouteRepo) Within(ctx context.Context, box entities.BBox) ([]entities.Route, error) {
+	return r.actualMatching(func(route entities.Route) bool {
+		return route.Crosses(box)
+	}), nil
+}
+
+func (r *memoryRouteRepo) actualMatching(match func(entities.Route) bool) (routes []entities.Route) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	routes = make([]entities.Route, 0)
+	for _, id := range r.sortedIds() {
+		route := r.routes[id]
+		if route.IsActual && len(route.Waypoints) > 0 && match(route) {
+			routes = append(routes, cloneRoute(route))
+		}
+	}
+
+	return routes
+}
+
+// Search ranks actual routes with entities.SearchRank, which approximates database search.
+func (r *memoryRouteRepo) Search(ctx context.Context, query string, limit int) (routes []entities.Route, err error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	actual := make([]entities.Route, 0, len(r.routes))
+	for _, route := range r.routes {
+		if route.IsActual {
+			actual = append(actual, cloneRoute(route))
+		}
+	}
+
+	return entities.RankRoutes(query, actual, limit), nil
+}
+
+func (r *memoryRouteRepo) sortedIds() []int {
+	ids := make([]int, 0, len(r.routes))
+	for id := range r.routes {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+
+	return ids
+}
+
+// checkRouteColumns fails for routes which do not fit into columns of routes table.
+func checkRouteColumns(route entities.Route) error {
+	if utf8.RuneCountInString(route.RouteName) > maxRouteNameLength {
+		return fmt.Errorf("route name is longer than %d characters", maxRouteNameLength)
+	}
+	if utf8.RuneCountInString(route.CargoType) > maxCargoTypeLength {
+		return fmt.Errorf("cargo type is longer than %d characters", maxCargoTypeLength)
+	}
+
+	return nil
+}
+
+// cloneRoute makes a copy of route sharing no memory with the original.
+func cloneRoute(route entities.Route) entities.Route {
+	route.Waypoints = cloneWaypoints(route.Waypoints)
+	if route.VehicleID != nil {
+		vehicleID := *route.VehicleID
+		route.VehicleID = &vehicleID
+	}
+
+	return route
+}
+
+// cloneWaypoints returns nil for no waypoints, as they are read from database.
+func cloneWaypoints(waypoints []entities.Waypoint) []entities.Waypoint {
+	if len(waypoints) == 0 {
+		return nil
+	}
+
+	return slices.Clone(waypoints)
+}
+
+func cloneVersion(version entities.RouteVersion) entities.RouteVersion {
+	version.Route = cloneRoute(version.Route)
+	version.Route.IsActual = version.SupersededBy == nil
+	if version.SupersededBy != nil {
+		supersededBy, supersededAt := *version.SupersededBy, *version.SupersededAt
+		version.SupersededBy, version.SupersededAt = &supersededBy, &supersededAt
+	}
+
+	return version
+}
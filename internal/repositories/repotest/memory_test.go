@@ -0,0 +1,47 @@
+package repotest
+
+import (
+	"context"
+	"github.com/stretchr/testify/require"
+	"sync"
+	"task/internal/entities"
+	"task/internal/repositories"
+	"testing"
+)
+
+func TestMemoryRouteRepo(t *testing.T) {
+	TestRouteRepo(t, func(t *testing.T) repositories.RouteRepo {
+		return repositories.NewMemoryRouteRepo()
+	})
+}
+
+func TestMemoryRouteRepoConcurrentRegister(t *testing.T) {
+	repo := repositories.NewMemoryRouteRepo()
+
+	const workers = 20
+	ids := make([]int, workers)
+	var wg sync.WaitGroup
+	for i := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			routeId, err := repo.Register(context.Background(), testRoute(1, "concurrent", "1", "sand"))
+			require.Nil(t, err)
+			ids[i] = routeId
+		}()
+	}
+	wg.Wait()
+
+	// every route gets its own id, only the last one in the chain stays actual
+	require.ElementsMatch(t, []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20}, ids)
+
+	isActual := true
+	routes, err := repo.List(context.Background(), entities.RouteFilter{IsActual: &isActual})
+	require.Nil(t, err)
+	require.Len(t, routes, 1)
+	require.Equal(t, workers, routes[0].RouteID)
+
+	versions, err := repo.History(context.Background(), 1)
+	require.Nil(t, err)
+	require.Len(t, versions, workers)
+}
@@ -0,0 +1,755 @@
+// Package repotest holds contract tests which every implementation of repositories interfaces must pass.
+package repotest
+
+import (
+	"context"
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"task/internal/entities"
+	"task/internal/repositories"
+	"task/pkg/decimal"
+	"testing"
+	"time"
+)
+
+// VehicleID is id of vehicle routes are assigned to in Assign tests. Implementations checking
+// that vehicle exists should create it in RouteRepoFactory.
+const VehicleID = 1
+
+// RouteRepoFactory returns empty repository. It is called once per test.
+type RouteRepoFactory func(t *testing.T) repositories.RouteRepo
+
+// TestRouteRepo checks that repository follows RouteRepo contract.
+func TestRouteRepo(t *testing.T, newRepo RouteRepoFactory) {
+	tests := []struct {
+		name string
+		test func(t *testing.T, repo repositories.RouteRepo)
+	}{
+		{"Register", testRegister},
+		{"RegisterBatch", testRegisterBatch},
+		{"GetById", testGetById},
+		{"List", testList},
+		{"Export", testExport},
+		{"Update", testUpdate},
+		{"Assign", testAssign},
+		{"History", testHistory},
+		{"DeleteById", testDeleteById},
+		{"Import", testImport},
+		{"Stats", testStats},
+		{"StatsOverflow", testStatsOverflow},
+		{"Spatial", testSpatial},
+		{"Search", testSearch},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.test(t, newRepo(t))
+		})
+	}
+}
+
+func testRoute(id int, name string, load string, cargoType string) entities.Route {
+	return entities.Route{
+		RouteID:   id,
+		RouteName: name,
+		Load:      decimal.MustParse(load),
+		CargoType: cargoType,
+	}
+}
+
+func register(t *testing.T, repo repositories.RouteRepo, routes ...entities.Route) {
+	for _, route := range routes {
+		_, err := repo.Register(context.Background(), route)
+		require.Nil(t, err)
+	}
+}
+
+func routeIds(routes []entities.Route) []int {
+	ids := make([]int, 0, len(routes))
+	for _, route := range routes {
+		ids = append(ids, route.RouteID)
+	}
+	return ids
+}
+
+func testRegister(t *testing.T, repo repositories.RouteRepo) {
+	ctx := context.Background()
+	register(t, repo, testRoute(1, "first", "1", "sand"), testRoute(5, "fifth", "5", "sand"))
+
+	testCases := []struct {
+		name       string
+		data       entities.Route
+		expectedID int
+	}{
+		{
+			name:       "free id",
+			data:       testRoute(3, "third", "3.5", "gravel"),
+			expectedID: 3,
+		},
+		{
+			name:       "taken id is reissued after the largest one",
+			data:       testRoute(1, "first again", "10", "gravel"),
+			expectedID: 6,
+		},
+		{
+			name:       "reissued id is taken again",
+			data:       testRoute(6, "sixth", "6", "gravel"),
+			expectedID: 7,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			routeId, err := repo.Register(ctx, tc.data)
+			require.Nil(t, err)
+			require.Equal(t, tc.expectedID, routeId)
+
+			found, err := repo.GetById(ctx, routeId)
+			require.Nil(t, err)
+			require.Equal(t, tc.data.RouteName, found.RouteName)
+			require.Equal(t, tc.data.Load, found.Load)
+			require.Equal(t, tc.data.CargoType, found.CargoType)
+			require.True(t, found.IsActual)
+
+			if routeId != tc.data.RouteID {
+				superseded, err := repo.GetById(ctx, tc.data.RouteID)
+				require.Nil(t, err)
+				require.False(t, superseded.IsActual)
+			}
+		})
+	}
+
+	_, err := repo.Register(ctx, testRoute(8, strings.Repeat("x", 200), "1", "sand"))
+	require.NotNil(t, err)
+	_, err = repo.GetById(ctx, 8)
+	require.ErrorIs(t, err, repositories.ErrNotFound)
+}
+
+func testRegisterBatch(t *testing.T, repo repositories.RouteRepo) {
+	ctx := context.Background()
+	register(t, repo, testRoute(1, "first", "1", "sand"))
+
+	ids, err := repo.RegisterBatch(ctx, []entities.Route{
+		testRoute(1, "batch1", "1", "sand"),
+		testRoute(2, "batch2", "2", "sand"),
+		testRoute(2, "batch3", "3", "sand"),
+	})
+	require.Nil(t, err)
+	require.Equal(t, []int{2, 3, 4}, ids)
+
+	routes, err := repo.List(ctx, entities.RouteFilter{})
+	require.Nil(t, err)
+	require.Equal(t, []int{1, 2, 3, 4}, routeIds(routes))
+	require.Equal(t, []bool{false, false, false, true}, actuality(routes))
+
+	// failed item rolls back whole batch
+	_, err = repo.RegisterBatch(ctx, []entities.Route{
+		testRoute(4, "batch4", "1", "sand"),
+		testRoute(10, strings.Repeat("x", 200), "1", "sand"),
+	})
+	require.NotNil(t, err)
+
+	after, err := repo.List(ctx, entities.RouteFilter{})
+	require.Nil(t, err)
+	require.Equal(t, routes, after)
+}
+
+func actuality(routes []entities.Route) []bool {
+	res := make([]bool, 0, len(routes))
+	for _, route := range routes {
+		res = append(res, route.IsActual)
+	}
+	return res
+}
+
+func testGetById(t *testing.T, repo repositories.RouteRepo) {
+	route := entities.Route{
+		RouteID:   1,
+		RouteName: "Moscow - Tver",
+		Load:      decimal.MustParse("1000.125"),
+		CargoType: "sand",
+		Waypoints: []entities.Waypoint{
+			{Lat: 55.7558, Lon: 37.6173, StopName: "Moscow", StopType: entities.StopDepot},
+			{Lat: 56.8587, Lon: 35.9176, StopName: "Tver", StopType: entities.StopDropoff},
+		},
+		Distance: 100000,
+		Duration: 90 * time.Minute,
+	}
+	register(t, repo, route)
+
+	testCases := []struct {
+		name     string
+		id       int
+		expected entities.Route
+		wantErr  bool
+		err      error
+	}{
+		{
+			name: "success",
+			id:   1,
+			expected: func() entities.Route {
+				route.IsActual = true
+				return route
+			}(),
+		},
+		{
+			name:    "not found",
+			id:      2,
+			wantErr: true,
+			err:     fmt.Errorf("getting route by id: no rows in result set"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			route, err := repo.GetById(context.Background(), tc.id)
+
+			if tc.wantErr {
+				require.Equal(t, tc.err.Error(), err.Error())
+				require.ErrorIs(t, err, repositories.ErrNotFound)
+			} else {
+				require.Nil(t, err)
+				require.Equal(t, tc.expected, route)
+			}
+		})
+	}
+}
+
+func testList(t *testing.T, repo repositories.RouteRepo) {
+	register(t, repo,
+		testRoute(1, "test1", "1", "cargo1"),
+		testRoute(2, "test2", "2", "cargo2"),
+		testRoute(3, "test3", "3", "cargo3"),
+		testRoute(4, "after_delete", "4", "cargo4"),
+		testRoute(5, "Test5", "6", "cargo6"),
+		testRoute(2, "test2_reissued", "2", "cargo2"),
+	)
+	require.Nil(t, repo.Assign(context.Background(), 3, VehicleID))
+
+	afterID := 1
+	isActual := true
+	minLoad, maxLoad := decimal.MustParse("2.5"), decimal.MustParse("6.0")
+	vehicleID := VehicleID
+
+	testCases := []struct {
+		name        string
+		filter      entities.RouteFilter
+		expectedIDs []int
+	}{
+		{
+			name:        "no filter",
+			filter:      entities.RouteFilter{},
+			expectedIDs: []int{1, 2, 3, 4, 5, 6},
+		},
+		{
+			name:        "case sensitive name prefix",
+			filter:      entities.RouteFilter{NamePrefix: "test"},
+			expectedIDs: []int{1, 2, 3, 6},
+		},
+		{
+			name:        "name prefix with wildcard symbol",
+			filter:      entities.RouteFilter{NamePrefix: "after_"},
+			expectedIDs: []int{4},
+		},
+		{
+			name:        "only actual after cursor",
+			filter:      entities.RouteFilter{AfterID: &afterID, IsActual: &isActual, NamePrefix: "test"},
+			expectedIDs: []int{3, 6},
+		},
+		{
+			name:        "load range with limit",
+			filter:      entities.RouteFilter{MinLoad: &minLoad, MaxLoad: &maxLoad, Limit: 2},
+			expectedIDs: []int{3, 4},
+		},
+		{
+			name:        "cargo type",
+			filter:      entities.RouteFilter{CargoType: "cargo2"},
+			expectedIDs: []int{2, 6},
+		},
+		{
+			name:        "route ids",
+			filter:      entities.RouteFilter{RouteIDs: []int{5, 1, 100}},
+			expectedIDs: []int{1, 5},
+		},
+		{
+			name:        "empty route ids",
+			filter:      entities.RouteFilter{RouteIDs: []int{}},
+			expectedIDs: []int{},
+		},
+		{
+			name:        "vehicle",
+			filter:      entities.RouteFilter{VehicleID: &vehicleID},
+			expectedIDs: []int{3},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			routes, err := repo.List(context.Background(), tc.filter)
+			require.Nil(t, err)
+			require.Equal(t, tc.expectedIDs, routeIds(routes))
+		})
+	}
+}
+
+func testExport(t *testing.T, repo repositories.RouteRepo) {
+	register(t, repo,
+		testRoute(3, "test3", "3", "sand"),
+		testRoute(1, "test1", "1", "sand"),
+		testRoute(2, "test2", "2", "gravel"),
+	)
+
+	var exported []entities.Route
+	err := repo.Export(context.Background(), entities.RouteFilter{CargoType: "sand"}, func(route entities.Route) error {
+		exported = append(exported, route)
+		return nil
+	})
+	require.Nil(t, err)
+	require.Equal(t, []int{1, 3}, routeIds(exported))
+
+	calls := 0
+	err = repo.Export(context.Background(), entities.RouteFilter{}, func(route entities.Route) error {
+		calls++
+		return fmt.Errorf("writing route %d", route.RouteID)
+	})
+	require.Equal(t, "exporting routes: writing route 1", err.Error())
+	require.Equal(t, 1, calls)
+}
+
+func testUpdate(t *testing.T, repo repositories.RouteRepo) {
+	ctx := context.Background()
+	register(t, repo, testRoute(1, "first", "1", "sand"), testRoute(1, "reissued", "1", "sand"))
+	require.Nil(t, repo.Assign(ctx, 2, VehicleID))
+
+	testCases := []struct {
+		name     string
+		data     entities.Route
+		isActual bool
+		wantErr  bool
+		err      error
+	}{
+		{
+			name: "actual route keeps vehicle",
+			data: entities.Route{
+				RouteID:   2,
+				RouteName: "updated",
+				Load:      decimal.MustParse("30.5"),
+				CargoType: "gravel",
+				Waypoints: []entities.Waypoint{{Lat: 55.7558, Lon: 37.6173}, {Lat: 56.8587, Lon: 35.9176}},
+				Distance:  100000,
+				Duration:  time.Hour,
+			},
+			isActual: true,
+		},
+		{
+			name:    "route is not actual",
+			data:    testRoute(1, "updated", "3", "sand"),
+			wantErr: true,
+			err:     fmt.Errorf("updating route: no rows in result set"),
+		},
+		{
+			name:    "not found",
+			data:    testRoute(5, "updated", "3", "sand"),
+			wantErr: true,
+			err:     fmt.Errorf("updating route: no rows in result set"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := repo.Update(ctx, tc.data)
+
+			if tc.wantErr {
+				require.Equal(t, tc.err.Error(), err.Error())
+				require.ErrorIs(t, err, repositories.ErrNotFound)
+			} else {
+				require.Nil(t, err)
+				found, err := repo.GetById(ctx, tc.data.RouteID)
+				require.Nil(t, err)
+
+				expected := tc.data
+				expected.IsActual = tc.isActual
+				expected.VehicleID = found.VehicleID
+				require.Equal(t, expected, found)
+			}
+		})
+	}
+
+	found, err := repo.GetById(ctx, 2)
+	require.Nil(t, err)
+	require.NotNil(t, found.VehicleID)
+	require.Equal(t, VehicleID, *found.VehicleID)
+
+	found, err = repo.GetById(ctx, 1)
+	require.Nil(t, err)
+	require.Equal(t, "first", found.RouteName)
+}
+
+func testAssign(t *testing.T, repo repositories.RouteRepo) {
+	ctx := context.Background()
+	register(t, repo, testRoute(1, "first", "1", "sand"), testRoute(1, "reissued", "1", "sand"))
+
+	testCases := []struct {
+		name    string
+		routeID int
+		wantErr bool
+		err     error
+	}{
+		{
+			name:    "success",
+			routeID: 2,
+		},
+		{
+			name:    "route is not actual",
+			routeID: 1,
+			wantErr: true,
+			err:     fmt.Errorf("assigning vehicle: no rows in result set"),
+		},
+		{
+			name:    "route not found",
+			routeID: 3,
+			wantErr: true,
+			err:     fmt.Errorf("assigning vehicle: no rows in result set"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := repo.Assign(ctx, tc.routeID, VehicleID)
+
+			if tc.wantErr {
+				require.Equal(t, tc.err.Error(), err.Error())
+				require.ErrorIs(t, err, repositories.ErrNotFound)
+			} else {
+				require.Nil(t, err)
+				found, err := repo.GetById(ctx, tc.routeID)
+				require.Nil(t, err)
+				require.NotNil(t, found.VehicleID)
+				require.Equal(t, VehicleID, *found.VehicleID)
+			}
+		})
+	}
+
+	// a new route registered in place of assigned one comes without vehicle
+	routeId, err := repo.Register(ctx, testRoute(2, "reissued again", "1", "sand"))
+	require.Nil(t, err)
+	found, err := repo.GetById(ctx, routeId)
+	require.Nil(t, err)
+	require.Nil(t, found.VehicleID)
+}
+
+func testHistory(t *testing.T, repo repositories.RouteRepo) {
+	ctx := context.Background()
+	register(t, repo,
+		testRoute(1, "first", "1", "sand"),
+		testRoute(2, "second", "2", "sand"),
+		testRoute(1, "first reissued", "1", "sand"),
+		testRoute(1, "first reissued again", "1", "sand"),
+	)
+	require.Nil(t, repo.Update(ctx, testRoute(2, "second updated", "2", "sand")))
+
+	// route 3 took id 1 first, so the next reissue of id 1 supersedes it as well
+	for id, actual := range map[int]bool{1: false, 3: false, 4: true} {
+		route, err := repo.GetById(ctx, id)
+		require.Nil(t, err)
+		require.Equal(t, actual, route.IsActual, "route %d", id)
+	}
+
+	testCases := []struct {
+		name             string
+		id               int
+		expectedRouteIDs []int
+		expectedNames    []string
+		wantErr          bool
+		err              error
+	}{
+		{
+			name:             "reissued route (by old id)",
+			id:               1,
+			expectedRouteIDs: []int{1, 3, 4},
+			expectedNames:    []string{"first", "first reissued", "first reissued again"},
+		},
+		{
+			name:             "reissued route (by middle id)",
+			id:               3,
+			expectedRouteIDs: []int{1, 3, 4},
+			expectedNames:    []string{"first", "first reissued", "first reissued again"},
+		},
+		{
+			name:             "updated route",
+			id:               2,
+			expectedRouteIDs: []int{2, 2},
+			expectedNames:    []string{"second", "second updated"},
+		},
+		{
+			name:    "not found",
+			id:      100,
+			wantErr: true,
+			err:     fmt.Errorf("getting route history: no rows in result set"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			versions, err := repo.History(ctx, tc.id)
+
+			if tc.wantErr {
+				require.Equal(t, tc.err.Error(), err.Error())
+				require.ErrorIs(t, err, repositories.ErrNotFound)
+			} else {
+				require.Nil(t, err)
+				require.Len(t, versions, len(tc.expectedRouteIDs))
+				for i, version := range versions {
+					require.Equal(t, tc.expectedRouteIDs[i], version.Route.RouteID)
+					require.Equal(t, tc.expectedNames[i], version.Route.RouteName)
+					if i < len(versions)-1 {
+						require.Equal(t, versions[i+1].VersionID, *version.SupersededBy)
+						require.NotNil(t, version.SupersededAt)
+						require.False(t, version.Route.IsActual)
+					}
+				}
+				last := versions[len(versions)-1]
+				require.Nil(t, last.SupersededBy)
+				require.Nil(t, last.SupersededAt)
+				require.True(t, last.Route.IsActual)
+			}
+		})
+	}
+}
+
+func testDeleteById(t *testing.T, repo repositories.RouteRepo) {
+	ctx := context.Background()
+	register(t, repo,
+		testRoute(1, "first", "1", "sand"),
+		testRoute(2, "second", "2", "sand"),
+		testRoute(3, "third", "3", "sand"),
+	)
+
+	deleted, err := repo.DeleteById(ctx, []int{1, 3, 100})
+	require.Nil(t, err)
+	require.ElementsMatch(t, []int{1, 3}, deleted)
+
+	deleted, err = repo.DeleteById(ctx, []int{1})
+	require.Nil(t, err)
+	require.Empty(t, deleted)
+
+	routes, err := repo.List(ctx, entities.RouteFilter{})
+	require.Nil(t, err)
+	require.Equal(t, []int{2}, routeIds(routes))
+
+	// history outlives route
+	versions, err := repo.History(ctx, 1)
+	require.Nil(t, err)
+	require.Len(t, versions, 1)
+
+	// reissued id is computed from remaining routes
+	routeId, err := repo.Register(ctx, testRoute(2, "second reissued", "2", "sand"))
+	require.Nil(t, err)
+	require.Equal(t, 3, routeId)
+}
+
+// importSource yields rows and then fails with err if it is set.
+type importSource struct {
+	rows []entities.ImportedRoute
+	pos  int
+	err  error
+}
+
+func (s *importSource) Next() bool {
+	s.pos++
+	return s.pos <= len(s.rows)
+}
+
+func (s *importSource) Route() entities.ImportedRoute {
+	return s.rows[s.pos-1]
+}
+
+func (s *importSource) Err() error {
+	return s.err
+}
+
+func testImport(t *testing.T, repo repositories.RouteRepo) {
+	ctx := context.Background()
+	register(t, repo, testRoute(1, "first", "1", "sand"))
+
+	report, err := repo.Import(ctx, &importSource{rows: []entities.ImportedRoute{
+		{Line: 3, Route: testRoute(2, "imported3", "3", "sand")},
+		{Line: 2, Route: testRoute(2, "imported2", "2", "sand")},
+		{Line: 4, Route: testRoute(1, "imported4", "4", "sand")},
+	}})
+	require.Nil(t, err)
+	require.Equal(t, entities.ImportReport{Imported: 3, Reissued: 2}, report)
+
+	routes, err := repo.List(ctx, entities.RouteFilter{})
+	require.Nil(t, err)
+	// lines are merged in order, not in order of reading
+	require.Equal(t, []int{1, 2, 3, 4}, routeIds(routes))
+	require.Equal(t, []string{"first", "imported2", "imported3", "imported4"}, []string{
+		routes[0].RouteName, routes[1].RouteName, routes[2].RouteName, routes[3].RouteName,
+	})
+
+	testCases := []struct {
+		name string
+		src  *importSource
+	}{
+		{
+			name: "invalid row",
+			src: &importSource{rows: []entities.ImportedRoute{
+				{Line: 2, Route: testRoute(10, "imported", "1", "sand")},
+				{Line: 3, Route: testRoute(11, strings.Repeat("x", 200), "1", "sand")},
+			}},
+		},
+		{
+			name: "source error",
+			src: &importSource{
+				rows: []entities.ImportedRoute{{Line: 2, Route: testRoute(10, "imported", "1", "sand")}},
+				err:  fmt.Errorf("broken file"),
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := repo.Import(ctx, tc.src)
+			require.NotNil(t, err)
+
+			after, err := repo.List(ctx, entities.RouteFilter{})
+			require.Nil(t, err)
+			require.Equal(t, routes, after)
+		})
+	}
+}
+
+func testStats(t *testing.T, repo repositories.RouteRepo) {
+	register(t, repo,
+		testRoute(1, "first", "7", "sand"),
+		testRoute(1, "first reissued", "1.5", "sand"),
+		testRoute(3, "third", "2", "sand"),
+		testRoute(4, "fourth", "0.25", "gravel"),
+	)
+
+	stats, err := repo.Stats(context.Background())
+	require.Nil(t, err)
+
+	d := decimal.MustParse
+	require.Equal(t, []entities.RouteStats{
+		{CargoType: "gravel", IsActual: true, Count: 1, TotalLoad: d("0.25"), AvgLoad: d("0.25"), MinLoad: d("0.25"), MaxLoad: d("0.25")},
+		{CargoType: "sand", IsActual: true, Count: 2, TotalLoad: d("3.5"), AvgLoad: d("1.8"), MinLoad: d("1.5"), MaxLoad: d("2")},
+		{CargoType: "sand", IsActual: false, Count: 1, TotalLoad: d("7"), AvgLoad: d("7"), MinLoad: d("7"), MaxLoad: d("7")},
+	}, stats)
+}
+
+func testStatsOverflow(t *testing.T, repo repositories.RouteRepo) {
+	register(t, repo,
+		testRoute(1, "first", "9000000000000000000", "sand"),
+		testRoute(2, "second", "9000000000000000000", "sand"),
+	)
+
+	_, err := repo.Stats(context.Background())
+	require.ErrorIs(t, err, decimal.ErrOverflow)
+	require.ErrorContains(t, err, `total load of routes with cargo type "sand" is too large`)
+}
+
+func testSpatial(t *testing.T, repo repositories.RouteRepo) {
+	ctx := context.Background()
+	moscow := entities.Waypoint{Lat: 55.7558, Lon: 37.6173}
+	tver := entities.Waypoint{Lat: 56.8587, Lon: 35.9176}
+	kazan := entities.Waypoint{Lat: 55.7961, Lon: 49.1064}
+	samara := entities.Waypoint{Lat: 53.1959, Lon: 50.1002}
+
+	withWaypoints := func(route entities.Route, waypoints ...entities.Waypoint) entities.Route {
+		route.Waypoints = waypoints
+		return route
+	}
+	register(t, repo,
+		withWaypoints(testRoute(1, "Moscow - Tver", "1", "sand"), moscow, tver),
+		withWaypoints(testRoute(2, "Kazan - Samara", "1", "sand"), kazan, samara),
+		testRoute(3, "no waypoints", "1", "sand"),
+	)
+
+	routes, err := repo.Near(ctx, tver, 5000)
+	require.Nil(t, err)
+	require.Equal(t, []int{1}, routeIds(routes))
+
+	routes, err = repo.Near(ctx, entities.Waypoint{Lat: 0, Lon: 0}, 5000)
+	require.Nil(t, err)
+	require.Empty(t, routes)
+
+	routes, err = repo.Within(ctx, entities.BBox{MinLat: 53, MinLon: 48, MaxLat: 56, MaxLon: 51})
+	require.Nil(t, err)
+	require.Equal(t, []int{2}, routeIds(routes))
+
+	routes, err = repo.Within(ctx, entities.BBox{MinLat: -90, MinLon: -180, MaxLat: 90, MaxLon: 180})
+	require.Nil(t, err)
+	require.Equal(t, []int{1, 2}, routeIds(routes))
+
+	// routes which are not actual are skipped, the new one is found in their place
+	register(t, repo, withWaypoints(testRoute(2, "Kazan - Samara again", "1", "sand"), kazan, samara))
+
+	routes, err = repo.Within(ctx, entities.BBox{MinLat: 53, MinLon: 48, MaxLat: 56, MaxLon: 51})
+	require.Nil(t, err)
+	require.Equal(t, []int{4}, routeIds(routes))
+
+	routes, err = repo.Near(ctx, samara, 1000)
+	require.Nil(t, err)
+	require.Equal(t, []int{4}, routeIds(routes))
+
+	_, err = repo.DeleteById(ctx, []int{1})
+	require.Nil(t, err)
+
+	routes, err = repo.Near(ctx, tver, 5000)
+	require.Nil(t, err)
+	require.Empty(t, routes)
+}
+
+func testSearch(t *testing.T, repo repositories.RouteRepo) {
+	ctx := context.Background()
+	register(t, repo,
+		testRoute(1, "Moscow - Tver gravel", "1", "sand"),
+		testRoute(2, "Tver - Moscow", "1", "sand"),
+		testRoute(3, "Kazan loop", "1", "sand"),
+	)
+
+	testCases := []struct {
+		name        string
+		query       string
+		limit       int
+		expectedIDs []int
+	}{
+		{
+			name:        "exact word",
+			query:       "gravel",
+			limit:       10,
+			expectedIDs: []int{1},
+		},
+		{
+			name:        "words in other order",
+			query:       "tver moscow",
+			limit:       10,
+			expectedIDs: []int{1, 2},
+		},
+		{
+			name:        "typos",
+			query:       "Mosow tvr",
+			limit:       10,
+			expectedIDs: []int{1, 2},
+		},
+		{
+			name:        "unrelated",
+			query:       "vladivostok",
+			limit:       10,
+			expectedIDs: []int{},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			routes, err := repo.Search(ctx, tc.query, tc.limit)
+			require.Nil(t, err)
+			require.ElementsMatch(t, tc.expectedIDs, routeIds(routes))
+		})
+	}
+
+	routes, err := repo.Search(ctx, "Mosow tvr", 1)
+	require.Nil(t, err)
+	require.Len(t, routes, 1)
+
+	// search ignores routes which are not actual
+	register(t, repo, testRoute(3, "Kazan ring", "1", "sand"))
+
+	routes, err = repo.Search(ctx, "kazan loop", 10)
+	require.Nil(t, err)
+	require.NotContains(t, routeIds(routes), 3)
+}
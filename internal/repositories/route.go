@@ -2,25 +2,64 @@ package repositories
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"strconv"
+	"strings"
 	"task/internal/entities"
+	"task/pkg/decimal"
 )
 
 //go:generate mockgen -source=route.go -destination=../mocks/route.go -package=mocks
 type RouteRepo interface {
 	Register(ctx context.Context, route entities.Route) (int, error)
+	RegisterBatch(ctx context.Context, routes []entities.Route) ([]int, error)
 	GetById(ctx context.Context, id int) (entities.Route, error)
-	DeleteById(ctx context.Context, ids []int) error
+	List(ctx context.Context, filter entities.RouteFilter) ([]entities.Route, error)
+	Export(ctx context.Context, filter entities.RouteFilter, fn func(entities.Route) error) error
+	// Update changes actual route. Route which is missing or not actual any more, e.g. because it
+	// was reissued after caller had read it, is reported as ErrNotFound.
+	Update(ctx context.Context, route entities.Route) error
+	// Assign binds vehicle to actual route. Implementations storing vehicles check that it is
+	// available and its capacity fits load of the route in the same statement, and fail with
+	// ErrVehicleUnfit otherwise. Cargo types of the vehicle are left to caller.
+	Assign(ctx context.Context, routeID int, vehicleID int) error
+	// Stats returns load statistics of routes grouped by cargo type and actuality.
+	Stats(ctx context.Context) ([]entities.RouteStats, error)
+	History(ctx context.Context, id int) ([]entities.RouteVersion, error)
+	DeleteById(ctx context.Context, ids []int) ([]int, error)
+	Import(ctx context.Context, src ImportSource) (entities.ImportReport, error)
+	Near(ctx context.Context, point entities.Waypoint, radius float64) ([]entities.Route, error)
+	Within(ctx context.Context, box entities.BBox) ([]entities.Route, error)
+	// Search returns at most limit actual routes with names matching query, the most relevant first.
+	Search(ctx context.Context, query string, limit int) ([]entities.Route, error)
 }
 
+// ImportSource yields routes for Import, similar to pgx.CopyFromSource.
+type ImportSource interface {
+	// Next advances to the next route. It returns false when there are no more routes or an error occurred.
+	Next() bool
+	Route() entities.ImportedRoute
+	Err() error
+}
+
+// ErrVehicleUnfit is returned by Assign if vehicle is not available or its capacity is less than load
+// of the route, e.g. because one of them was changed after caller had checked them.
+var ErrVehicleUnfit = errors.New("vehicle is not available or can not carry load of the route")
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
 type routeRepo struct {
-	db *pgx.Conn
+	db    Querier
+	index *spatialIndex
 }
 
-func NewRouteRepo(db *pgx.Conn) RouteRepo {
+func NewRouteRepo(db Querier) RouteRepo {
 	return &routeRepo{
-		db: db,
+		db:    db,
+		index: newSpatialIndex(),
 	}
 }
 
@@ -39,11 +78,63 @@ func (r *routeRepo) Register(ctx context.Context, route entities.Route) (routeId
 		}
 	}()
 
+	routeId, err = register(ctx, tx, route)
+	if err != nil {
+		return 0, err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("commit transaction: %w", err)
+	}
+	r.bumpRevision(ctx)
+
+	return routeId, nil
+}
+
+// RegisterBatch registers all routes in one transaction, so either all of them are stored or none.
+// Conflicts are resolved the same way as in Register.
+func (r *routeRepo) RegisterBatch(ctx context.Context, routes []entities.Route) (routeIds []int, err error) {
+	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("begin transaction: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			rollbackErr := tx.Rollback(ctx)
+			if rollbackErr != nil {
+				err = fmt.Errorf("rollback err: %w; handled err: %v", rollbackErr, err)
+			}
+		}
+	}()
+
+	routeIds = make([]int, 0, len(routes))
+	for i, route := range routes {
+		routeId, err := register(ctx, tx, route)
+		if err != nil {
+			return nil, fmt.Errorf("route #%d: %w", i, err)
+		}
+		routeIds = append(routeIds, routeId)
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("commit transaction: %w", err)
+	}
+	r.bumpRevision(ctx)
+
+	return routeIds, nil
+}
+
+// register inserts route. If route id is already taken, existing route is marked as not actual
+// and the new one gets next free id.
+func register(ctx context.Context, tx pgx.Tx, route entities.Route) (routeId int, err error) {
 	err = tx.QueryRow(
 		ctx,
 		`with try as (
-			insert into routes(route_id, route_name, load, cargo_type)
-				values($1, $2, $3, $4)
+			insert into routes(route_id, route_name, load, cargo_type, waypoints, distance_m, duration_s)
+				values($1, $2, $3, $4, $5, $6, $7)
 				on conflict(route_id) do update set
 					is_actual = false
 				returning route_id, (xmax = 0) as inserted
@@ -51,8 +142,8 @@ func (r *routeRepo) Register(ctx context.Context, route entities.Route) (routeId
 			select max(route_id) + 1 as route_id
 			from routes
 		), insert_new as (
-			insert into routes(route_id, route_name, load, cargo_type)
-				select new_id.route_id, $2, $3, $4
+			insert into routes(route_id, route_name, load, cargo_type, waypoints, distance_m, duration_s)
+				select new_id.route_id, $2, $3, $4, $5, $6, $7
 				from new_id
 				where not exists (select 1 from try where inserted)
 				returning route_id
@@ -64,16 +155,25 @@ func (r *routeRepo) Register(ctx context.Context, route entities.Route) (routeId
 				) as inserted_id`,
 		route.RouteID,
 		route.RouteName,
-		route.Load,
+		decimalArg(route.Load),
 		route.CargoType,
+		waypointsArg(route.Waypoints),
+		route.Distance,
+		secondsArg(route.Duration),
 	).Scan(&routeId)
 	if err != nil {
 		return 0, fmt.Errorf("register route: %w", err)
 	}
 
-	err = tx.Commit(ctx)
+	supersededRouteId := -1
+	if routeId != route.RouteID {
+		supersededRouteId = route.RouteID
+	}
+	route.RouteID = routeId
+
+	err = recordVersion(ctx, tx, route, supersededRouteId)
 	if err != nil {
-		return 0, fmt.Errorf("commit transaction: %w", err)
+		return 0, err
 	}
 
 	return routeId, nil
@@ -87,16 +187,24 @@ func (r *routeRepo) GetById(ctx context.Context, id int) (route entities.Route,
     			route_name, 
     			load, 
        			cargo_type,
-       			is_actual
+       			is_actual,
+       			waypoints,
+       			distance_m,
+       			duration_s,
+       			vehicle_id
 			from routes
 			where route_id=$1`,
 		id,
 	).Scan(
 		&route.RouteID,
 		&route.RouteName,
-		&route.Load,
+		(*decimalColumn)(&route.Load),
 		&route.CargoType,
 		&route.IsActual,
+		(*waypointsColumn)(&route.Waypoints),
+		&route.Distance,
+		(*secondsColumn)(&route.Duration),
+		&route.VehicleID,
 	)
 	if err != nil {
 		return entities.Route{}, fmt.Errorf("getting route by id: %w", err)
@@ -105,15 +213,510 @@ func (r *routeRepo) GetById(ctx context.Context, id int) (route entities.Route,
 	return route, nil
 }
 
-func (r *routeRepo) DeleteById(ctx context.Context, ids []int) (err error) {
-	_, err = r.db.Exec(
+func (r *routeRepo) List(ctx context.Context, filter entities.RouteFilter) (routes []entities.Route, err error) {
+	routes = make([]entities.Route, 0, filter.Limit)
+	err = r.queryRoutes(ctx, filter, func(route entities.Route) error {
+		routes = append(routes, route)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("listing routes: %w", err)
+	}
+
+	return routes, nil
+}
+
+// Export calls fn for every route matching the filter, reading them from database cursor one by one.
+// Zero limit means no limit.
+func (r *routeRepo) Export(ctx context.Context, filter entities.RouteFilter, fn func(entities.Route) error) (err error) {
+	err = r.queryRoutes(ctx, filter, fn)
+	if err != nil {
+		return fmt.Errorf("exporting routes: %w", err)
+	}
+
+	return nil
+}
+
+func (r *routeRepo) queryRoutes(ctx context.Context, filter entities.RouteFilter, fn func(entities.Route) error) (err error) {
+	var (
+		conds []string
+		args  []any
+	)
+	addCond := func(cond string, arg any) {
+		args = append(args, arg)
+		conds = append(conds, fmt.Sprintf(cond, len(args)))
+	}
+
+	if filter.RouteIDs != nil {
+		addCond("route_id = any($%d)", filter.RouteIDs)
+	}
+	if filter.AfterID != nil {
+		addCond("route_id > $%d", *filter.AfterID)
+	}
+	if filter.CargoType != "" {
+		addCond("cargo_type = $%d", filter.CargoType)
+	}
+	if filter.IsActual != nil {
+		addCond("is_actual = $%d", *filter.IsActual)
+	}
+	if filter.MinLoad != nil {
+		addCond("load >= $%d", decimalArg(*filter.MinLoad))
+	}
+	if filter.MaxLoad != nil {
+		addCond("load <= $%d", decimalArg(*filter.MaxLoad))
+	}
+	if filter.NamePrefix != "" {
+		addCond(`route_name like $%d escape '\'`, likeEscaper.Replace(filter.NamePrefix)+"%")
+	}
+	if filter.VehicleID != nil {
+		addCond("vehicle_id = $%d", *filter.VehicleID)
+	}
+
+	query := `select
+				route_id,
+				route_name,
+				load,
+				cargo_type,
+				is_actual,
+				waypoints,
+				distance_m,
+				duration_s,
+				vehicle_id
+			from routes`
+	if len(conds) > 0 {
+		query += " where " + strings.Join(conds, " and ")
+	}
+	query += " order by route_id"
+	if filter.Limit > 0 {
+		args = append(args, filter.Limit)
+		query += fmt.Sprintf(" limit $%d", len(args))
+	}
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var route entities.Route
+		err = rows.Scan(
+			&route.RouteID,
+			&route.RouteName,
+			(*decimalColumn)(&route.Load),
+			&route.CargoType,
+			&route.IsActual,
+			(*waypointsColumn)(&route.Waypoints),
+			&route.Distance,
+			(*secondsColumn)(&route.Duration),
+			&route.VehicleID,
+		)
+		if err != nil {
+			return fmt.Errorf("scanning route: %w", err)
+		}
+
+		err = fn(route)
+		if err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+func (r *routeRepo) Update(ctx context.Context, route entities.Route) (err error) {
+	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			rollbackErr := tx.Rollback(ctx)
+			if rollbackErr != nil {
+				err = fmt.Errorf("rollback err: %w; handled err: %v", rollbackErr, err)
+			}
+		}
+	}()
+
+	// renaming a route keeps spatial indexes, only changed waypoints bump the revision
+	var moved bool
+	err = tx.QueryRow(
+		ctx,
+		`with old as (
+				select route_id, waypoints
+				from routes
+				where route_id = $1 and is_actual
+				for update
+			)
+			update routes r set
+				route_name = $2,
+				load = $3,
+				cargo_type = $4,
+				waypoints = $5,
+				distance_m = $6,
+				duration_s = $7
+			from old
+			where r.route_id = old.route_id
+			returning old.waypoints is distinct from r.waypoints`,
+		route.RouteID,
+		route.RouteName,
+		decimalArg(route.Load),
+		route.CargoType,
+		waypointsArg(route.Waypoints),
+		route.Distance,
+		secondsArg(route.Duration),
+	).Scan(&moved)
+	if err != nil {
+		return fmt.Errorf("updating route: %w", err)
+	}
+
+	err = recordVersion(ctx, tx, route, route.RouteID)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+	if moved {
+		r.bumpRevision(ctx)
+	}
+
+	return nil
+}
+
+func (r *routeRepo) Assign(ctx context.Context, routeID int, vehicleID int) (err error) {
+	// vehicle row is locked, so it can not be changed until the route is assigned
+	var assigned, found bool
+	err = r.db.QueryRow(
+		ctx,
+		`with route as (
+				select route_id, load
+				from routes
+				where route_id = $1 and is_actual
+				for update
+			), assigned as (
+				update routes r set vehicle_id = $2
+				from route
+				where r.route_id = route.route_id and exists (
+					select 1
+					from vehicles v
+					where v.vehicle_id = $2 and v.status = 'available' and v.capacity >= route.load
+					for share
+				)
+				returning r.route_id
+			)
+			select exists (select 1 from assigned), exists (select 1 from route)`,
+		routeID,
+		vehicleID,
+	).Scan(&assigned, &found)
+	if err != nil {
+		return fmt.Errorf("assigning vehicle: %w", err)
+	}
+	if !found {
+		return fmt.Errorf("assigning vehicle: %w", pgx.ErrNoRows)
+	}
+	if !assigned {
+		return fmt.Errorf("assigning vehicle: %w", ErrVehicleUnfit)
+	}
+
+	return nil
+}
+
+func (r *routeRepo) Stats(ctx context.Context) (stats []entities.RouteStats, err error) {
+	rows, err := r.db.Query(
+		ctx,
+		`select
+				cargo_type,
+				is_actual,
+				count(1),
+				sum(load),
+				round(avg(load), max(scale(trim_scale(load)))),
+				min(load),
+				max(load)
+			from routes
+			group by cargo_type, is_actual
+			order by cargo_type, is_actual desc`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("getting route stats: %w", err)
+	}
+	defer rows.Close()
+
+	stats = make([]entities.RouteStats, 0)
+	for rows.Next() {
+		var (
+			item  entities.RouteStats
+			total pgtype.Numeric
+		)
+		err = rows.Scan(
+			&item.CargoType,
+			&item.IsActual,
+			&item.Count,
+			&total,
+			(*decimalColumn)(&item.AvgLoad),
+			(*decimalColumn)(&item.MinLoad),
+			(*decimalColumn)(&item.MaxLoad),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scanning route stats: %w", err)
+		}
+
+		// sum of loads may not fit into decimal even though every load does
+		item.TotalLoad, err = decimal.NewFromBig(total.Int, total.Exp)
+		if err != nil {
+			return nil, fmt.Errorf("total load of routes with cargo type %q is too large: %w", item.CargoType, err)
+		}
+		stats = append(stats, item)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("getting route stats: %w", err)
+	}
+
+	return stats, nil
+}
+
+// Search finds routes by full-text match of route name or by its trigram word similarity to query,
+// which tolerates typos. Routes are ranked by sum of both.
+func (r *routeRepo) Search(ctx context.Context, query string, limit int) (routes []entities.Route, err error) {
+	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return nil, fmt.Errorf("begin transaction: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			rollbackErr := tx.Rollback(ctx)
+			if rollbackErr != nil {
+				err = fmt.Errorf("rollback err: %w; handled err: %v", rollbackErr, err)
+			}
+		}
+	}()
+
+	// threshold of <% operator is lowered only for this transaction
+	_, err = tx.Exec(
+		ctx,
+		`select set_config('pg_trgm.word_similarity_threshold', $1, true)`,
+		strconv.FormatFloat(entities.SearchSimilarityThreshold, 'f', -1, 64),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("setting similarity threshold: %w", err)
+	}
+
+	rows, err := tx.Query(
+		ctx,
+		`select route_id
+			from routes, websearch_to_tsquery('simple', $1) query
+			where is_actual
+				and (route_name_tsv @@ query or $1 <% route_name)
+			order by ts_rank(route_name_tsv, query) + word_similarity($1, route_name) desc, route_id
+			limit $2`,
+		query,
+		limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("searching routes: %w", err)
+	}
+
+	ids, err := pgx.CollectRows(rows, pgx.RowTo[int])
+	if err != nil {
+		return nil, fmt.Errorf("searching routes: %w", err)
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("commit transaction: %w", err)
+	}
+
+	routes = make([]entities.Route, 0, len(ids))
+	if len(ids) == 0 {
+		return routes, nil
+	}
+
+	found := make(map[int]entities.Route, len(ids))
+	err = r.queryRoutes(ctx, entities.RouteFilter{RouteIDs: ids}, func(route entities.Route) error {
+		found[route.RouteID] = route
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("searching routes: %w", err)
+	}
+
+	// routes deleted after ranking are skipped
+	for _, id := range ids {
+		if route, ok := found[id]; ok {
+			routes = append(routes, route)
+		}
+	}
+
+	return routes, nil
+}
+
+func (r *routeRepo) History(ctx context.Context, id int) (versions []entities.RouteVersion, err error) {
+	rows, err := r.db.Query(
 		ctx,
-		`delete from routes where route_id = any($1)`,
+		`with recursive seed as (
+			select version_id
+			from route_versions
+			where route_id = $1
+		), predecessors(version_id) as (
+			select version_id from seed
+			union
+			select v.version_id
+			from route_versions v
+				join predecessors p on v.superseded_by = p.version_id
+		), successors(version_id) as (
+			select version_id from seed
+			union
+			select v.superseded_by
+			from route_versions v
+				join successors s on v.version_id = s.version_id
+			where v.superseded_by is not null
+		)
+		select
+			version_id,
+			route_id,
+			route_name,
+			load,
+			cargo_type,
+			waypoints,
+			created_at,
+			superseded_by,
+			superseded_at
+		from route_versions
+		where version_id in (
+			select version_id from predecessors
+			union
+			select version_id from successors
+		)
+		order by created_at, version_id`,
+		id,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("getting route history: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var version entities.RouteVersion
+		err = rows.Scan(
+			&version.VersionID,
+			&version.Route.RouteID,
+			&version.Route.RouteName,
+			(*decimalColumn)(&version.Route.Load),
+			&version.Route.CargoType,
+			(*waypointsColumn)(&version.Route.Waypoints),
+			&version.CreatedAt,
+			&version.SupersededBy,
+			&version.SupersededAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scanning route version: %w", err)
+		}
+		version.Route.IsActual = version.SupersededBy == nil
+		versions = append(versions, version)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("getting route history: %w", err)
+	}
+
+	if len(versions) == 0 {
+		return nil, fmt.Errorf("getting route history: %w", pgx.ErrNoRows)
+	}
+
+	return versions, nil
+}
+
+// recordVersion appends route snapshot to route_versions. If supersededRouteId is non-negative,
+// the last version in the chain of that route is linked to the new one, and the route owning
+// that version is marked as not actual unless it is the route being recorded. So every chain
+// ends with the only actual version.
+func recordVersion(ctx context.Context, tx pgx.Tx, route entities.Route, supersededRouteId int) (err error) {
+	var versionId int64
+	err = tx.QueryRow(
+		ctx,
+		`insert into route_versions(route_id, route_name, load, cargo_type, waypoints)
+			values($1, $2, $3, $4, $5)
+			returning version_id`,
+		route.RouteID,
+		route.RouteName,
+		decimalArg(route.Load),
+		route.CargoType,
+		waypointsArg(route.Waypoints),
+	).Scan(&versionId)
+	if err != nil {
+		return fmt.Errorf("inserting route version: %w", err)
+	}
+
+	if supersededRouteId < 0 {
+		return nil
+	}
+
+	// tail is closed only if it is still open, a concurrent transaction could have closed it
+	// after the chain was read; then the chain is walked again up to the new tail
+	for {
+		var closed, found bool
+		err = tx.QueryRow(
+			ctx,
+			`with recursive chain(version_id, superseded_by) as (
+				select version_id, superseded_by
+				from route_versions
+				where version_id = (
+					select max(version_id)
+					from route_versions
+					where route_id = $1 and version_id <> $2
+				)
+				union all
+				select v.version_id, v.superseded_by
+				from route_versions v
+					join chain c on v.version_id = c.superseded_by
+			), tail as (
+				select max(version_id) as version_id
+				from chain
+				where superseded_by is null
+			), closed as (
+				update route_versions v set
+					superseded_by = $2,
+					superseded_at = now()
+				from tail
+				where v.version_id = tail.version_id and v.superseded_by is null
+				returning v.route_id
+			), deactivated as (
+				update routes set is_actual = false
+				where route_id in (select route_id from closed) and route_id <> $3
+			)
+			select exists (select 1 from closed), exists (select 1 from tail where version_id is not null)`,
+			supersededRouteId,
+			versionId,
+			route.RouteID,
+		).Scan(&closed, &found)
+		if err != nil {
+			return fmt.Errorf("superseding route version: %w", err)
+		}
+		if closed || !found {
+			return nil
+		}
+	}
+}
+
+// DeleteById deletes routes and returns ids of those which existed.
+func (r *routeRepo) DeleteById(ctx context.Context, ids []int) (deletedIds []int, err error) {
+	rows, err := r.db.Query(
+		ctx,
+		`delete from routes where route_id = any($1) returning route_id`,
 		ids,
 	)
 	if err != nil {
-		return fmt.Errorf("deleting route by id: %w", err)
+		return nil, fmt.Errorf("deleting route by id: %w", err)
 	}
 
-	return nil
+	deletedIds, err = pgx.CollectRows(rows, pgx.RowTo[int])
+	if err != nil {
+		return nil, fmt.Errorf("deleting route by id: %w", err)
+	}
+	r.index.remove(deletedIds...)
+
+	return deletedIds, nil
 }
@@ -3,20 +3,23 @@ package repositories
 import (
 	"context"
 	"fmt"
-	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/stretchr/testify/require"
 	"log"
 	"os"
+	"strings"
 	"task/internal/entities"
 	"task/internal/integration_tests"
+	"task/pkg/decimal"
 	"testing"
+	"time"
 )
 
 // for running tests use "go test -cover ./..." from root
 
 const eps = 1e-6
 
-var testDbInstance *pgx.Conn
+var testDbInstance *pgxpool.Pool
 
 func TestMain(m *testing.M) {
 	testDB := integration_tests.SetupTestDatabase()
@@ -33,24 +36,27 @@ func TestDeleteById(t *testing.T) {
 	repo := NewRouteRepo(testDbInstance)
 
 	testCases := []struct {
-		name    string
-		ids     []int
-		wantErr bool
-		err     error
+		name            string
+		ids             []int
+		expectedDeleted []int
+		wantErr         bool
+		err             error
 	}{
 		{
-			name: "success",
-			ids:  []int{4, 5},
+			name:            "success",
+			ids:             []int{4, 5, 100},
+			expectedDeleted: []int{4, 5},
 		},
 	}
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			err := repo.DeleteById(context.Background(), tc.ids)
+			deleted, err := repo.DeleteById(context.Background(), tc.ids)
 
 			if tc.wantErr {
 				require.Equal(t, tc.err.Error(), err.Error())
 			} else {
 				require.Nil(t, err)
+				require.ElementsMatch(t, tc.expectedDeleted, deleted)
 			}
 		})
 	}
@@ -71,7 +77,7 @@ func TestRegister(t *testing.T) {
 			data: entities.Route{
 				RouteID:   4,
 				RouteName: "after_delete_route",
-				Load:      1000.0,
+				Load:      decimal.MustParse("1000.125"),
 				CargoType: "cargo_type",
 			},
 		},
@@ -80,7 +86,7 @@ func TestRegister(t *testing.T) {
 			data: entities.Route{
 				RouteID:   2,
 				RouteName: "already_existing_id",
-				Load:      2000.0,
+				Load:      decimal.MustParse("2000"),
 				CargoType: "cargo_type_2",
 			},
 			newPos: 7,
@@ -100,7 +106,7 @@ func TestRegister(t *testing.T) {
 				require.Nil(t, err)
 				require.Equal(t, tc.data.RouteID, foundInDB.RouteID)
 				require.Equal(t, tc.data.RouteName, foundInDB.RouteName)
-				require.InEpsilon(t, tc.data.Load, foundInDB.Load, eps)
+				require.Equal(t, tc.data.Load, foundInDB.Load)
 				require.Equal(t, tc.data.CargoType, foundInDB.CargoType)
 			}
 		})
@@ -123,7 +129,7 @@ func TestGetById(t *testing.T) {
 			expected: entities.Route{
 				RouteID:   6,
 				RouteName: "test6",
-				Load:      6.0,
+				Load:      decimal.MustParse("6"),
 				CargoType: "cargo6",
 			},
 		},
@@ -144,9 +150,521 @@ func TestGetById(t *testing.T) {
 				require.Nil(t, err)
 				require.Equal(t, tc.expected.RouteID, route.RouteID)
 				require.Equal(t, tc.expected.RouteName, route.RouteName)
-				require.InEpsilon(t, tc.expected.Load, route.Load, eps)
+				require.Equal(t, tc.expected.Load, route.Load)
 				require.Equal(t, tc.expected.CargoType, route.CargoType)
 			}
 		})
 	}
 }
+
+func TestList(t *testing.T) {
+	repo := NewRouteRepo(testDbInstance)
+
+	afterID := 1
+	isActual := true
+	minLoad, maxLoad := decimal.MustParse("2.5"), decimal.MustParse("6.0")
+
+	testCases := []struct {
+		name        string
+		filter      entities.RouteFilter
+		expectedIDs []int
+		wantErr     bool
+		err         error
+	}{
+		{
+			name:        "name prefix",
+			filter:      entities.RouteFilter{NamePrefix: "test", Limit: 10},
+			expectedIDs: []int{1, 2, 3, 6},
+		},
+		{
+			name:        "name prefix with wildcard symbol",
+			filter:      entities.RouteFilter{NamePrefix: "after_", Limit: 10},
+			expectedIDs: []int{4},
+		},
+		{
+			name:        "only actual after cursor",
+			filter:      entities.RouteFilter{AfterID: &afterID, IsActual: &isActual, NamePrefix: "test", Limit: 10},
+			expectedIDs: []int{3, 6},
+		},
+		{
+			name:        "load range with limit",
+			filter:      entities.RouteFilter{MinLoad: &minLoad, MaxLoad: &maxLoad, Limit: 1},
+			expectedIDs: []int{3},
+		},
+		{
+			name:        "cargo type",
+			filter:      entities.RouteFilter{CargoType: "cargo6", Limit: 10},
+			expectedIDs: []int{6},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			routes, err := repo.List(context.Background(), tc.filter)
+
+			if tc.wantErr {
+				require.Equal(t, tc.err.Error(), err.Error())
+			} else {
+				require.Nil(t, err)
+				ids := make([]int, 0, len(routes))
+				for _, route := range routes {
+					ids = append(ids, route.RouteID)
+				}
+				require.Equal(t, tc.expectedIDs, ids)
+			}
+		})
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	repo := NewRouteRepo(testDbInstance)
+
+	testCases := []struct {
+		name    string
+		data    entities.Route
+		wantErr bool
+		err     error
+	}{
+		{
+			name: "success",
+			data: entities.Route{
+				RouteID:   3,
+				RouteName: "updated_route",
+				Load:      decimal.MustParse("30"),
+				CargoType: "cargo3",
+			},
+		},
+		{
+			name: "not found",
+			data: entities.Route{
+				RouteID:   5,
+				RouteName: "updated_route",
+				Load:      decimal.MustParse("30"),
+				CargoType: "cargo3",
+			},
+			wantErr: true,
+			err:     fmt.Errorf("updating route: no rows in result set"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := repo.Update(context.Background(), tc.data)
+
+			if tc.wantErr {
+				require.Equal(t, tc.err.Error(), err.Error())
+			} else {
+				require.Nil(t, err)
+				foundInDB, err := repo.GetById(context.Background(), tc.data.RouteID)
+				require.Nil(t, err)
+				require.Equal(t, tc.data.RouteName, foundInDB.RouteName)
+				require.Equal(t, tc.data.Load, foundInDB.Load)
+				require.Equal(t, tc.data.CargoType, foundInDB.CargoType)
+				require.True(t, foundInDB.IsActual)
+			}
+		})
+	}
+}
+
+func TestHistory(t *testing.T) {
+	repo := NewRouteRepo(testDbInstance)
+
+	testCases := []struct {
+		name             string
+		id               int
+		expectedRouteIDs []int
+		expectedNames    []string
+		wantErr          bool
+		err              error
+	}{
+		{
+			name:             "reissued route (by old id)",
+			id:               2,
+			expectedRouteIDs: []int{2, 7},
+			expectedNames:    []string{"test2", "already_existing_id"},
+		},
+		{
+			name:             "reissued route (by new id)",
+			id:               7,
+			expectedRouteIDs: []int{2, 7},
+			expectedNames:    []string{"test2", "already_existing_id"},
+		},
+		{
+			name:             "updated route",
+			id:               3,
+			expectedRouteIDs: []int{3, 3},
+			expectedNames:    []string{"test3", "updated_route"},
+		},
+		{
+			name:    "not found",
+			id:      100,
+			wantErr: true,
+			err:     fmt.Errorf("getting route history: no rows in result set"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			versions, err := repo.History(context.Background(), tc.id)
+
+			if tc.wantErr {
+				require.Equal(t, tc.err.Error(), err.Error())
+			} else {
+				require.Nil(t, err)
+				require.Len(t, versions, len(tc.expectedRouteIDs))
+				for i, version := range versions {
+					require.Equal(t, tc.expectedRouteIDs[i], version.Route.RouteID)
+					require.Equal(t, tc.expectedNames[i], version.Route.RouteName)
+				}
+				require.Equal(t, versions[1].VersionID, *versions[0].SupersededBy)
+				require.NotNil(t, versions[0].SupersededAt)
+				require.Nil(t, versions[1].SupersededBy)
+			}
+		})
+	}
+}
+
+func TestRegisterBatch(t *testing.T) {
+	repo := NewRouteRepo(testDbInstance)
+
+	testCases := []struct {
+		name        string
+		data        []entities.Route
+		expectedIDs []int
+		wantErr     bool
+	}{
+		{
+			name: "success (conflict inside batch)",
+			data: []entities.Route{
+				{RouteID: 20, RouteName: "batch1", Load: decimal.MustParse("1"), CargoType: "cargo"},
+				{RouteID: 20, RouteName: "batch2", Load: decimal.MustParse("2"), CargoType: "cargo"},
+			},
+			expectedIDs: []int{20, 21},
+		},
+		{
+			name: "failed item rolls back batch",
+			data: []entities.Route{
+				{RouteID: 30, RouteName: "batch3", Load: decimal.MustParse("1"), CargoType: "cargo"},
+				{RouteID: 31, RouteName: strings.Repeat("x", 200), Load: decimal.MustParse("1"), CargoType: "cargo"},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			routeIds, err := repo.RegisterBatch(context.Background(), tc.data)
+
+			if tc.wantErr {
+				require.NotNil(t, err)
+				for _, route := range tc.data {
+					_, err = repo.GetById(context.Background(), route.RouteID)
+					require.ErrorIs(t, err, ErrNotFound)
+				}
+			} else {
+				require.Nil(t, err)
+				require.Equal(t, tc.expectedIDs, routeIds)
+				for i, routeId := range routeIds {
+					foundInDB, err := repo.GetById(context.Background(), routeId)
+					require.Nil(t, err)
+					require.Equal(t, tc.data[i].RouteName, foundInDB.RouteName)
+				}
+			}
+		})
+	}
+}
+
+type sliceSource struct {
+	rows []entities.ImportedRoute
+	pos  int
+}
+
+func (s *sliceSource) Next() bool {
+	s.pos++
+	return s.pos <= len(s.rows)
+}
+
+func (s *sliceSource) Route() entities.ImportedRoute {
+	return s.rows[s.pos-1]
+}
+
+func (s *sliceSource) Err() error {
+	return nil
+}
+
+func TestImport(t *testing.T) {
+	repo := NewRouteRepo(testDbInstance)
+
+	src := &sliceSource{rows: []entities.ImportedRoute{
+		{Line: 2, Route: entities.Route{RouteID: 40, RouteName: "imported1", Load: decimal.MustParse("1"), CargoType: "cargo"}},
+		{Line: 3, Route: entities.Route{RouteID: 40, RouteName: "imported2", Load: decimal.MustParse("2"), CargoType: "cargo"}},
+	}}
+
+	report, err := repo.Import(context.Background(), src)
+	require.Nil(t, err)
+	require.Equal(t, 2, report.Imported)
+	require.Equal(t, 1, report.Reissued)
+
+	old, err := repo.GetById(context.Background(), 40)
+	require.Nil(t, err)
+	require.Equal(t, "imported1", old.RouteName)
+	require.False(t, old.IsActual)
+
+	reissued, err := repo.GetById(context.Background(), 41)
+	require.Nil(t, err)
+	require.Equal(t, "imported2", reissued.RouteName)
+	require.True(t, reissued.IsActual)
+
+	// reissued route supersedes the one imported from previous line
+	versions, err := repo.History(context.Background(), 41)
+	require.Nil(t, err)
+	require.Len(t, versions, 2)
+	require.Equal(t, 40, versions[0].Route.RouteID)
+	require.Equal(t, &versions[1].VersionID, versions[0].SupersededBy)
+	require.Equal(t, 41, versions[1].Route.RouteID)
+	require.Nil(t, versions[1].SupersededBy)
+}
+
+func TestExport(t *testing.T) {
+	repo := NewRouteRepo(testDbInstance)
+
+	// routes of other tests are filtered out by name prefix
+	for _, route := range []entities.Route{
+		{RouteID: 80, RouteName: "export_first", Load: decimal.MustParse("1"), CargoType: "cargo"},
+		{RouteID: 81, RouteName: "export_second", Load: decimal.MustParse("2"), CargoType: "cargo"},
+		{RouteID: 80, RouteName: "export_reissued", Load: decimal.MustParse("3"), CargoType: "cargo"},
+	} {
+		_, err := repo.Register(context.Background(), route)
+		require.Nil(t, err)
+	}
+
+	export := func(filter entities.RouteFilter) (names []string) {
+		filter.NamePrefix = "export_"
+		err := repo.Export(context.Background(), filter, func(route entities.Route) error {
+			names = append(names, route.RouteName)
+			return nil
+		})
+		require.Nil(t, err)
+		return names
+	}
+
+	isActual := false
+	require.Equal(t, []string{"export_first"}, export(entities.RouteFilter{IsActual: &isActual}))
+	require.Equal(t, []string{"export_first", "export_second", "export_reissued"}, export(entities.RouteFilter{}))
+	require.Equal(t, []string{"export_first", "export_second"}, export(entities.RouteFilter{Limit: 2}))
+
+	stopErr := fmt.Errorf("stop")
+	err := repo.Export(context.Background(), entities.RouteFilter{}, func(route entities.Route) error {
+		return stopErr
+	})
+	require.ErrorIs(t, err, stopErr)
+}
+
+func TestWaypoints(t *testing.T) {
+	repo := NewRouteRepo(testDbInstance)
+
+	waypoints := []entities.Waypoint{
+		{Lat: 55.75, Lon: 37.61, StopName: "depot", StopType: entities.StopDepot},
+		{Lat: 59.93, Lon: 30.31},
+	}
+
+	routeId, err := repo.Register(context.Background(), entities.Route{
+		RouteID:   50,
+		RouteName: "with_waypoints",
+		Load:      decimal.MustParse("1"),
+		CargoType: "cargo",
+		Waypoints: waypoints,
+	})
+	require.Nil(t, err)
+	require.Equal(t, 50, routeId)
+
+	route, err := repo.GetById(context.Background(), 50)
+	require.Nil(t, err)
+	require.Equal(t, waypoints, route.Waypoints)
+
+	route.Waypoints = waypoints[1:]
+	err = repo.Update(context.Background(), route)
+	require.Nil(t, err)
+
+	versions, err := repo.History(context.Background(), 50)
+	require.Nil(t, err)
+	require.Len(t, versions, 2)
+	require.Equal(t, waypoints, versions[0].Route.Waypoints)
+	require.Equal(t, waypoints[1:], versions[1].Route.Waypoints)
+}
+
+func TestDistance(t *testing.T) {
+	repo := NewRouteRepo(testDbInstance)
+
+	_, err := repo.Register(context.Background(), entities.Route{
+		RouteID:   51,
+		RouteName: "with_distance",
+		Load:      decimal.MustParse("1"),
+		CargoType: "cargo",
+		Distance:  12345.5,
+		Duration:  time.Hour + time.Second,
+	})
+	require.Nil(t, err)
+
+	route, err := repo.GetById(context.Background(), 51)
+	require.Nil(t, err)
+	require.InDelta(t, 12345.5, route.Distance, eps)
+	require.Equal(t, time.Hour+time.Second, route.Duration)
+
+	route.Distance = 100
+	route.Duration = time.Minute
+	err = repo.Update(context.Background(), route)
+	require.Nil(t, err)
+
+	route, err = repo.GetById(context.Background(), 51)
+	require.Nil(t, err)
+	require.InDelta(t, 100, route.Distance, eps)
+	require.Equal(t, time.Minute, route.Duration)
+}
+
+func TestSpatial(t *testing.T) {
+	repo := NewRouteRepo(testDbInstance)
+
+	_, err := repo.Register(context.Background(), entities.Route{
+		RouteID:   52,
+		RouteName: "moscow_tver",
+		Load:      decimal.MustParse("1"),
+		CargoType: "cargo",
+		Waypoints: []entities.Waypoint{
+			{Lat: 55.75, Lon: 37.61},
+			{Lat: 56.86, Lon: 35.92},
+		},
+	})
+	require.Nil(t, err)
+
+	routeIds := func(routes []entities.Route) []int {
+		ids := make([]int, 0, len(routes))
+		for _, route := range routes {
+			ids = append(ids, route.RouteID)
+		}
+		return ids
+	}
+
+	routes, err := repo.Near(context.Background(), entities.Waypoint{Lat: 56.305, Lon: 36.765}, 5000)
+	require.Nil(t, err)
+	require.Equal(t, []int{52}, routeIds(routes))
+
+	// route 50 consists of single point after TestWaypoints
+	routes, err = repo.Near(context.Background(), entities.Waypoint{Lat: 59.93, Lon: 30.31}, 1000)
+	require.Nil(t, err)
+	require.Equal(t, []int{50}, routeIds(routes))
+
+	routes, err = repo.Within(context.Background(), entities.BBox{MinLat: 56.8, MinLon: 35.8, MaxLat: 56.9, MaxLon: 36.0})
+	require.Nil(t, err)
+	require.Equal(t, []int{52}, routeIds(routes))
+
+	routes, err = repo.Within(context.Background(), entities.BBox{MinLat: 0, MinLon: 0, MaxLat: 1, MaxLon: 1})
+	require.Nil(t, err)
+	require.Empty(t, routes)
+
+	_, err = repo.DeleteById(context.Background(), []int{52})
+	require.Nil(t, err)
+
+	routes, err = repo.Near(context.Background(), entities.Waypoint{Lat: 56.305, Lon: 36.765}, 5000)
+	require.Nil(t, err)
+	require.Empty(t, routes)
+}
+
+func TestStats(t *testing.T) {
+	repo := NewRouteRepo(testDbInstance)
+
+	type key struct {
+		cargoType string
+		isActual  bool
+	}
+	groups := make(map[key][]decimal.Decimal)
+	err := repo.Export(context.Background(), entities.RouteFilter{}, func(route entities.Route) error {
+		k := key{route.CargoType, route.IsActual}
+		groups[k] = append(groups[k], route.Load)
+		return nil
+	})
+	require.Nil(t, err)
+
+	stats, err := repo.Stats(context.Background())
+	require.Nil(t, err)
+	require.Len(t, stats, len(groups))
+
+	for i, item := range stats {
+		if i > 0 {
+			prev := stats[i-1]
+			require.True(t, prev.CargoType < item.CargoType || prev.CargoType == item.CargoType && prev.IsActual && !item.IsActual)
+		}
+
+		loads := groups[key{item.CargoType, item.IsActual}]
+		require.Equal(t, len(loads), item.Count)
+
+		total := decimal.Decimal{}
+		scale := int32(0)
+		minLoad, maxLoad := loads[0], loads[0]
+		for _, load := range loads {
+			total, err = total.Add(load)
+			require.Nil(t, err)
+			scale = max(scale, load.Scale())
+			if load.Cmp(minLoad) < 0 {
+				minLoad = load
+			}
+			if load.Cmp(maxLoad) > 0 {
+				maxLoad = load
+			}
+		}
+		avg, err := total.Div(decimal.New(int64(len(loads)), 0), scale)
+		require.Nil(t, err)
+
+		require.Equal(t, total, item.TotalLoad)
+		require.Equal(t, avg, item.AvgLoad)
+		require.Equal(t, minLoad, item.MinLoad)
+		require.Equal(t, maxLoad, item.MaxLoad)
+	}
+}
+
+func TestSearch(t *testing.T) {
+	repo := NewRouteRepo(testDbInstance)
+
+	named := []entities.Route{
+		{RouteID: 70, RouteName: "Moscow - Tver gravel", Load: decimal.MustParse("1"), CargoType: "cargo"},
+		{RouteID: 71, RouteName: "Tver - Moscow", Load: decimal.MustParse("1"), CargoType: "cargo"},
+		{RouteID: 72, RouteName: "Kazan loop", Load: decimal.MustParse("1"), CargoType: "cargo"},
+	}
+	for _, route := range named {
+		_, err := repo.Register(context.Background(), route)
+		require.Nil(t, err)
+	}
+
+	routeIds := func(routes []entities.Route) []int {
+		ids := make([]int, 0, len(routes))
+		for _, route := range routes {
+			ids = append(ids, route.RouteID)
+		}
+		return ids
+	}
+
+	routes, err := repo.Search(context.Background(), "gravel", 10)
+	require.Nil(t, err)
+	require.Equal(t, []int{70}, routeIds(routes))
+	require.Equal(t, "Moscow - Tver gravel", routes[0].RouteName)
+
+	routes, err = repo.Search(context.Background(), "tver moscow", 10)
+	require.Nil(t, err)
+	require.ElementsMatch(t, []int{70, 71}, routeIds(routes))
+
+	routes, err = repo.Search(context.Background(), "Mosow tvr", 10)
+	require.Nil(t, err)
+	require.ElementsMatch(t, []int{70, 71}, routeIds(routes))
+
+	routes, err = repo.Search(context.Background(), "Mosow tvr", 1)
+	require.Nil(t, err)
+	require.Len(t, routes, 1)
+
+	routes, err = repo.Search(context.Background(), "vladivostok", 10)
+	require.Nil(t, err)
+	require.Empty(t, routes)
+
+	// search ignores routes which are not actual
+	_, err = repo.Register(context.Background(), entities.Route{RouteID: 72, RouteName: "Kazan ring", Load: decimal.MustParse("1"), CargoType: "cargo"})
+	require.Nil(t, err)
+
+	routes, err = repo.Search(context.Background(), "kazan loop", 10)
+	require.Nil(t, err)
+	require.NotContains(t, routeIds(routes), 72)
+}
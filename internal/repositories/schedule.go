@@ -0,0 +1,146 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"task/internal/entities"
+)
+
+//go:generate mockgen -source=schedule.go -destination=../mocks/schedule.go -package=mocks
+type ScheduleRepo interface {
+	// Set creates or replaces schedule of the route.
+	Set(ctx context.Context, schedule entities.Schedule) error
+	Get(ctx context.Context, routeID int) (entities.Schedule, error)
+	// List returns schedules of actual routes, only of given routes if routeIDs is not nil.
+	List(ctx context.Context, routeIDs []int) ([]entities.Schedule, error)
+	Delete(ctx context.Context, routeID int) error
+}
+
+type scheduleRepo struct {
+	db Querier
+}
+
+func NewScheduleRepo(db Querier) ScheduleRepo {
+	return &scheduleRepo{
+		db: db,
+	}
+}
+
+const selectSchedules = `select
+		s.route_id,
+		s.departure_from,
+		s.departure_to,
+		s.arrival_from,
+		s.arrival_to,
+		s.recurrence,
+		s.timezone,
+		s.valid_from,
+		s.valid_until
+	from route_schedules s`
+
+func (r *scheduleRepo) Set(ctx context.Context, schedule entities.Schedule) (err error) {
+	_, err = r.db.Exec(
+		ctx,
+		`insert into route_schedules(route_id, departure_from, departure_to, arrival_from, arrival_to, recurrence, timezone, valid_from, valid_until)
+			values($1, $2, $3, $4, $5, $6, $7, $8, $9)
+			on conflict(route_id) do update set
+				departure_from = excluded.departure_from,
+				departure_to = excluded.departure_to,
+				arrival_from = excluded.arrival_from,
+				arrival_to = excluded.arrival_to,
+				recurrence = excluded.recurrence,
+				timezone = excluded.timezone,
+				valid_from = excluded.valid_from,
+				valid_until = excluded.valid_until`,
+		schedule.RouteID,
+		secondsArg(schedule.Departure.From),
+		secondsArg(schedule.Departure.To),
+		secondsArg(schedule.Arrival.From),
+		secondsArg(schedule.Arrival.To),
+		schedule.Recurrence.String(),
+		schedule.Timezone,
+		schedule.ValidFrom,
+		schedule.ValidUntil,
+	)
+	if err != nil {
+		return fmt.Errorf("setting route schedule: %w", err)
+	}
+
+	return nil
+}
+
+func (r *scheduleRepo) Get(ctx context.Context, routeID int) (schedule entities.Schedule, err error) {
+	rows, err := r.db.Query(ctx, selectSchedules+` where s.route_id = $1`, routeID)
+	if err != nil {
+		return entities.Schedule{}, fmt.Errorf("getting route schedule: %w", err)
+	}
+
+	schedule, err = pgx.CollectExactlyOneRow(rows, scanSchedule)
+	if err != nil {
+		return entities.Schedule{}, fmt.Errorf("getting route schedule: %w", err)
+	}
+
+	return schedule, nil
+}
+
+func (r *scheduleRepo) List(ctx context.Context, routeIDs []int) (schedules []entities.Schedule, err error) {
+	query := selectSchedules + `
+		join routes using(route_id)
+		where routes.is_actual`
+	var args []any
+	if routeIDs != nil {
+		query += ` and s.route_id = any($1)`
+		args = append(args, routeIDs)
+	}
+	query += ` order by s.route_id`
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("listing route schedules: %w", err)
+	}
+
+	schedules, err = pgx.CollectRows(rows, scanSchedule)
+	if err != nil {
+		return nil, fmt.Errorf("listing route schedules: %w", err)
+	}
+
+	return schedules, nil
+}
+
+func (r *scheduleRepo) Delete(ctx context.Context, routeID int) (err error) {
+	tag, err := r.db.Exec(ctx, `delete from route_schedules where route_id = $1`, routeID)
+	if err != nil {
+		return fmt.Errorf("deleting route schedule: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("deleting route schedule: %w", pgx.ErrNoRows)
+	}
+
+	return nil
+}
+
+func scanSchedule(row pgx.CollectableRow) (schedule entities.Schedule, err error) {
+	var recurrence string
+	err = row.Scan(
+		&schedule.RouteID,
+		(*secondsColumn)(&schedule.Departure.From),
+		(*secondsColumn)(&schedule.Departure.To),
+		(*secondsColumn)(&schedule.Arrival.From),
+		(*secondsColumn)(&schedule.Arrival.To),
+		&recurrence,
+		&schedule.Timezone,
+		&schedule.ValidFrom,
+		&schedule.ValidUntil,
+	)
+	if err != nil {
+		return entities.Schedule{}, fmt.Errorf("scanning route schedule: %w", err)
+	}
+
+	schedule.Recurrence, err = entities.ParseRecurrence(recurrence)
+	if err != nil {
+		return entities.Schedule{}, fmt.Errorf("scanning route schedule: %w", err)
+	}
+
+	return schedule, nil
+}
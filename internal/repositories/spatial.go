@@ -0,0 +1,337 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"sync"
+	"task/internal/entities"
+)
+
+const (
+	// spatialCellSize is a side of index grid cell in degrees
+	spatialCellSize = 0.25
+	// spatialMaxCells limits number of cells a route is put into; routes spanning more cells
+	// are returned as candidates for every query
+	spatialMaxCells = 4096
+)
+
+type cellKey struct {
+	lat int
+	lon int
+}
+
+type cellRange struct {
+	minLat, minLon int
+	maxLat, maxLon int
+}
+
+func cellRangeOf(b entities.BBox) cellRange {
+	return cellRange{
+		minLat: int(math.Floor(b.MinLat / spatialCellSize)),
+		minLon: int(math.Floor(b.MinLon / spatialCellSize)),
+		maxLat: int(math.Floor(b.MaxLat / spatialCellSize)),
+		maxLon: int(math.Floor(b.MaxLon / spatialCellSize)),
+	}
+}
+
+func (c cellRange) size() int {
+	return (c.maxLat - c.minLat + 1) * (c.maxLon - c.minLon + 1)
+}
+
+func (c cellRange) contains(key cellKey) bool {
+	return key.lat >= c.minLat && key.lat <= c.maxLat && key.lon >= c.minLon && key.lon <= c.maxLon
+}
+
+// spatialIndex holds a grid of actual routes built at some value of routes_revision sequence.
+// Every write inserting routes or changing their waypoints bumps the sequence after commit, in any
+// process, so a query finding the revision ahead of the grid rebuilds it. The grid only narrows
+// the search, candidates should be checked against fresh rows from database.
+type spatialIndex struct {
+	// build is held while a new grid is built, so concurrent queries wait for one build
+	// instead of starting their own; queries served by the current grid do not wait
+	build    sync.Mutex
+	mu       sync.RWMutex
+	grid     *spatialGrid
+	revision int64
+}
+
+// spatialGrid is a grid of fixed-size cells holding ids of routes which segments pass through the cell.
+type spatialGrid struct {
+	cells    map[cellKey]map[int]struct{}
+	routes   map[int][]cellKey
+	oversize map[int]struct{}
+}
+
+func newSpatialIndex() *spatialIndex {
+	return &spatialIndex{}
+}
+
+func newSpatialGrid(routes []entities.Route) *spatialGrid {
+	grid := &spatialGrid{
+		cells:    make(map[cellKey]map[int]struct{}),
+		routes:   make(map[int][]cellKey, len(routes)),
+		oversize: make(map[int]struct{}),
+	}
+	for _, route := range routes {
+		grid.add(route)
+	}
+
+	return grid
+}
+
+// candidates returns sorted ids of routes which may cross the box. revision is called to get
+// current revision of routes, load is called to get all actual routes when the grid is behind it.
+func (idx *spatialIndex) candidates(
+	ctx context.Context,
+	box entities.BBox,
+	revision func(ctx context.Context) (int64, error),
+	load func(ctx context.Context) ([]entities.Route, error),
+) (ids []int, err error) {
+	rev, err := revision(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("getting routes revision: %w", err)
+	}
+
+	ids, ok := idx.lookup(box, rev)
+	if ok {
+		return ids, nil
+	}
+
+	idx.build.Lock()
+	defer idx.build.Unlock()
+
+	// grid could have been rebuilt while waiting for the previous build
+	ids, ok = idx.lookup(box, rev)
+	if ok {
+		return ids, nil
+	}
+
+	// routes are loaded after the revision was read, so the grid is at least as new as rev
+	routes, err := load(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("building spatial index: %w", err)
+	}
+	grid := newSpatialGrid(routes)
+
+	idx.mu.Lock()
+	idx.grid = grid
+	idx.revision = rev
+	idx.mu.Unlock()
+
+	ids, _ = idx.lookup(box, rev)
+	return ids, nil
+}
+
+// lookup returns candidates from the grid if it is built at revision rev or later.
+func (idx *spatialIndex) lookup(box entities.BBox, rev int64) (ids []int, ok bool) {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	if idx.grid == nil || idx.revision < rev {
+		return nil, false
+	}
+
+	return idx.grid.lookup(box), true
+}
+
+// remove drops deleted routes from the grid. Deletes do not bump the revision, since candidates
+// deleted by other processes are filtered out by the check against database anyway.
+func (idx *spatialIndex) remove(ids ...int) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	if idx.grid == nil {
+		return
+	}
+	for _, id := range ids {
+		idx.grid.delete(id)
+	}
+}
+
+func (g *spatialGrid) lookup(box entities.BBox) []int {
+	found := make(map[int]struct{})
+	for id := range g.oversize {
+		found[id] = struct{}{}
+	}
+
+	addCell := func(ids map[int]struct{}) {
+		for id := range ids {
+			found[id] = struct{}{}
+		}
+	}
+
+	rng := cellRangeOf(box)
+	if rng.size() > len(g.cells) {
+		for key, ids := range g.cells {
+			if rng.contains(key) {
+				addCell(ids)
+			}
+		}
+	} else {
+		for lat := rng.minLat; lat <= rng.maxLat; lat++ {
+			for lon := rng.minLon; lon <= rng.maxLon; lon++ {
+				addCell(g.cells[cellKey{lat: lat, lon: lon}])
+			}
+		}
+	}
+
+	ids := make([]int, 0, len(found))
+	for id := range found {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+
+	return ids
+}
+
+// add puts every segment of the route into cells covered by the segment bounding box.
+func (g *spatialGrid) add(route entities.Route) {
+	wps := route.Waypoints
+	if len(wps) == 1 {
+		// single point is indexed as degenerate segment
+		wps = []entities.Waypoint{wps[0], wps[0]}
+	}
+
+	var ranges []cellRange
+	total := 0
+	for i := 1; i < len(wps); i++ {
+		a, b := wps[i-1], wps[i]
+		rng := cellRangeOf(entities.BBox{
+			MinLat: math.Min(a.Lat, b.Lat),
+			MinLon: math.Min(a.Lon, b.Lon),
+			MaxLat: math.Max(a.Lat, b.Lat),
+			MaxLon: math.Max(a.Lon, b.Lon),
+		})
+		total += rng.size()
+		if total > spatialMaxCells {
+			g.oversize[route.RouteID] = struct{}{}
+			return
+		}
+		ranges = append(ranges, rng)
+	}
+
+	var keys []cellKey
+	for _, rng := range ranges {
+		for lat := rng.minLat; lat <= rng.maxLat; lat++ {
+			for lon := rng.minLon; lon <= rng.maxLon; lon++ {
+				key := cellKey{lat: lat, lon: lon}
+				ids, ok := g.cells[key]
+				if !ok {
+					ids = make(map[int]struct{})
+					g.cells[key] = ids
+				}
+				if _, ok = ids[route.RouteID]; !ok {
+					ids[route.RouteID] = struct{}{}
+					keys = append(keys, key)
+				}
+			}
+		}
+	}
+	if len(keys) > 0 {
+		g.routes[route.RouteID] = keys
+	}
+}
+
+func (g *spatialGrid) delete(id int) {
+	delete(g.oversize, id)
+	for _, key := range g.routes[id] {
+		delete(g.cells[key], id)
+		if len(g.cells[key]) == 0 {
+			delete(g.cells, key)
+		}
+	}
+	delete(g.routes, id)
+}
+
+// Near returns actual routes passing within radius meters of the point, ordered by id.
+func (r *routeRepo) Near(ctx context.Context, point entities.Waypoint, radius float64) (routes []entities.Route, err error) {
+	routes, err = r.spatialQuery(ctx, entities.BBoxAround(point, radius), func(route entities.Route) bool {
+		return route.PassesNear(point, radius)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("finding routes near point: %w", err)
+	}
+
+	return routes, nil
+}
+
+// Within returns actual routes passing through the box, ordered by id.
+func (r *routeRepo) Within(ctx context.Context, box entities.BBox) (routes []entities.Route, err error) {
+	routes, err = r.spatialQuery(ctx, box, func(route entities.Route) bool {
+		return route.Crosses(box)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("finding routes within box: %w", err)
+	}
+
+	return routes, nil
+}
+
+// spatialQuery reads candidates from the index and keeps those of them which are still actual
+// and match exact geometry check.
+func (r *routeRepo) spatialQuery(ctx context.Context, box entities.BBox, match func(entities.Route) bool) (routes []entities.Route, err error) {
+	ids, err := r.index.candidates(ctx, box, r.routesRevision, r.loadActualGeometry)
+	if err != nil {
+		return nil, err
+	}
+
+	routes = make([]entities.Route, 0)
+	if len(ids) == 0 {
+		return routes, nil
+	}
+
+	isActual := true
+	err = r.queryRoutes(ctx, entities.RouteFilter{RouteIDs: ids, IsActual: &isActual}, func(route entities.Route) error {
+		if match(route) {
+			routes = append(routes, route)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return routes, nil
+}
+
+func (r *routeRepo) routesRevision(ctx context.Context) (revision int64, err error) {
+	err = r.db.QueryRow(
+		ctx,
+		`select case when is_called then last_value else 0 end from routes_revision`,
+	).Scan(&revision)
+	return revision, err
+}
+
+// bumpRevision makes spatial indexes of all processes stale. It runs after commit of the change,
+// so a grid built at the new revision sees it; nextval neither blocks nor waits for other writers.
+// The change is already stored, so an error only delays the rebuild until the next bump and is
+// not reported to the caller.
+func (r *routeRepo) bumpRevision(ctx context.Context) {
+	_, _ = r.db.Exec(context.WithoutCancel(ctx), `select nextval('routes_revision')`)
+}
+
+func (r *routeRepo) loadActualGeometry(ctx context.Context) (routes []entities.Route, err error) {
+	rows, err := r.db.Query(
+		ctx,
+		`select route_id, waypoints
+			from routes
+			where is_actual and waypoints <> '[]'::jsonb`,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var route entities.Route
+		err = rows.Scan(&route.RouteID, (*waypointsColumn)(&route.Waypoints))
+		if err != nil {
+			return nil, fmt.Errorf("scanning route geometry: %w", err)
+		}
+		routes = append(routes, route)
+	}
+
+	return routes, rows.Err()
+}
@@ -0,0 +1,212 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"task/internal/entities"
+	"task/internal/repositories"
+)
+
+type cargoTypeRepo struct {
+	db *sql.DB
+}
+
+func NewCargoTypeRepo(db *sql.DB) repositories.CargoTypeRepo {
+	return &cargoTypeRepo{
+		db: db,
+	}
+}
+
+const selectCargoTypes = `select
+		c.code,
+		c.display_name,
+		c.unit,
+		c.density,
+		c.hazard_class,
+		(
+			select json_group_array(alias)
+			from (
+				select a.alias
+				from cargo_type_aliases a
+				where a.code = c.code
+				order by a.alias
+			)
+		)
+	from cargo_types c`
+
+func scanCargoType(row scanner) (cargoType entities.CargoType, err error) {
+	err = row.Scan(
+		&cargoType.Code,
+		&cargoType.DisplayName,
+		&cargoType.Unit,
+		&cargoType.Density,
+		&cargoType.HazardClass,
+		jsonColumn[[]string]{v: &cargoType.Aliases},
+	)
+	if err != nil {
+		return entities.CargoType{}, err
+	}
+
+	return cargoType, nil
+}
+
+// Create inserts cargo type with its aliases. Taken code or alias is reported
+// as unique violation, see repositories.IsUniqueViolation.
+func (r *cargoTypeRepo) Create(ctx context.Context, cargoType entities.CargoType) (err error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer rollback(tx, &err)
+
+	_, err = tx.ExecContext(
+		ctx,
+		`insert into cargo_types(code, display_name, unit, density, hazard_class)
+			values($1, $2, $3, $4, $5)`,
+		cargoType.Code,
+		cargoType.DisplayName,
+		cargoType.Unit,
+		cargoType.Density,
+		cargoType.HazardClass,
+	)
+	if err != nil {
+		return fmt.Errorf("inserting cargo type: %w", err)
+	}
+
+	err = insertAliases(ctx, tx, cargoType)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (r *cargoTypeRepo) GetByCode(ctx context.Context, code string) (cargoType entities.CargoType, err error) {
+	cargoType, err = scanCargoType(r.db.QueryRowContext(ctx, selectCargoTypes+` where c.code = $1`, code))
+	if err != nil {
+		return entities.CargoType{}, fmt.Errorf("getting cargo type by code: %w", noRows(err))
+	}
+
+	return cargoType, nil
+}
+
+func (r *cargoTypeRepo) List(ctx context.Context) (cargoTypes []entities.CargoType, err error) {
+	rows, err := r.db.QueryContext(ctx, selectCargoTypes+` order by c.code`)
+	if err != nil {
+		return nil, fmt.Errorf("listing cargo types: %w", err)
+	}
+	defer rows.Close()
+
+	cargoTypes = make([]entities.CargoType, 0)
+	for rows.Next() {
+		cargoType, err := scanCargoType(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanning cargo type: %w", err)
+		}
+		cargoTypes = append(cargoTypes, cargoType)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("listing cargo types: %w", err)
+	}
+
+	return cargoTypes, nil
+}
+
+// Update replaces attributes and aliases of existing cargo type.
+func (r *cargoTypeRepo) Update(ctx context.Context, cargoType entities.CargoType) (err error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer rollback(tx, &err)
+
+	res, err := tx.ExecContext(
+		ctx,
+		`update cargo_types set
+				display_name = $2,
+				unit = $3,
+				density = $4,
+				hazard_class = $5
+			where code = $1`,
+		cargoType.Code,
+		cargoType.DisplayName,
+		cargoType.Unit,
+		cargoType.Density,
+		cargoType.HazardClass,
+	)
+	if err != nil {
+		return fmt.Errorf("updating cargo type: %w", err)
+	}
+	if err = affected(res); err != nil {
+		return fmt.Errorf("updating cargo type: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `delete from cargo_type_aliases where code = $1`, cargoType.Code)
+	if err != nil {
+		return fmt.Errorf("deleting cargo type aliases: %w", err)
+	}
+
+	err = insertAliases(ctx, tx, cargoType)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func insertAliases(ctx context.Context, tx *sql.Tx, cargoType entities.CargoType) (err error) {
+	for _, alias := range cargoType.Aliases {
+		_, err = tx.ExecContext(
+			ctx,
+			`insert into cargo_type_aliases(alias, code)
+				values($1, $2)`,
+			alias,
+			cargoType.Code,
+		)
+		if err != nil {
+			return fmt.Errorf("inserting cargo type aliases: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (r *cargoTypeRepo) Delete(ctx context.Context, code string) (err error) {
+	res, err := r.db.ExecContext(ctx, `delete from cargo_types where code = $1`, code)
+	if err != nil {
+		return fmt.Errorf("deleting cargo type: %w", err)
+	}
+	if err = affected(res); err != nil {
+		return fmt.Errorf("deleting cargo type: %w", err)
+	}
+
+	return nil
+}
+
+func (r *cargoTypeRepo) InUse(ctx context.Context, code string) (inUse bool, err error) {
+	err = r.db.QueryRowContext(
+		ctx,
+		`select exists(
+			select 1
+			from routes
+			where cargo_type = $1 and is_actual
+		)`,
+		code,
+	).Scan(&inUse)
+	if err != nil {
+		return false, fmt.Errorf("checking cargo type usage: %w", err)
+	}
+
+	return inUse, nil
+}
@@ -0,0 +1,84 @@
+package sqlite
+
+import (
+	"context"
+	"github.com/stretchr/testify/require"
+	"task/internal/entities"
+	"task/internal/repositories"
+	"task/pkg/decimal"
+	"testing"
+)
+
+func TestCargoTypes(t *testing.T) {
+	db := newTestDB(t)
+	repo := NewCargoTypeRepo(db)
+	ctx := context.Background()
+
+	sand := entities.CargoType{
+		Code:        "sand",
+		DisplayName: "Sand",
+		Unit:        entities.UnitTonne,
+		Aliases:     []string{"quartz sand", "beach sand"},
+	}
+	petrol := entities.CargoType{
+		Code:        "petrol",
+		DisplayName: "Petrol",
+		Unit:        entities.UnitLitre,
+		Density:     745,
+		HazardClass: "3",
+	}
+
+	err := repo.Create(ctx, sand)
+	require.Nil(t, err)
+	err = repo.Create(ctx, petrol)
+	require.Nil(t, err)
+
+	err = repo.Create(ctx, entities.CargoType{Code: "sandstone", DisplayName: "Sandstone", Unit: entities.UnitTonne, Aliases: []string{"beach sand"}})
+	require.True(t, repositories.IsUniqueViolation(err))
+
+	err = repo.Create(ctx, entities.CargoType{Code: "sand", DisplayName: "Sand", Unit: entities.UnitTonne})
+	require.True(t, repositories.IsUniqueViolation(err))
+
+	found, err := repo.GetByCode(ctx, "sand")
+	require.Nil(t, err)
+	sand.Aliases = []string{"beach sand", "quartz sand"}
+	require.Equal(t, sand, found)
+
+	petrol.Aliases = []string{}
+	cargoTypes, err := repo.List(ctx)
+	require.Nil(t, err)
+	require.Equal(t, []entities.CargoType{petrol, sand}, cargoTypes)
+
+	sand.HazardClass = "9"
+	sand.Density = 1600
+	sand.Aliases = []string{"river sand"}
+	err = repo.Update(ctx, sand)
+	require.Nil(t, err)
+
+	found, err = repo.GetByCode(ctx, "sand")
+	require.Nil(t, err)
+	require.Equal(t, sand, found)
+
+	err = repo.Update(ctx, entities.CargoType{Code: "water", DisplayName: "Water", Unit: entities.UnitLitre})
+	require.ErrorIs(t, err, repositories.ErrNotFound)
+
+	_, err = NewRouteRepo(db).Register(ctx, entities.Route{RouteID: 1, RouteName: "sand route", Load: decimal.MustParse("1"), CargoType: "sand"})
+	require.Nil(t, err)
+
+	inUse, err := repo.InUse(ctx, "sand")
+	require.Nil(t, err)
+	require.True(t, inUse)
+
+	inUse, err = repo.InUse(ctx, "petrol")
+	require.Nil(t, err)
+	require.False(t, inUse)
+
+	err = repo.Delete(ctx, "petrol")
+	require.Nil(t, err)
+
+	err = repo.Delete(ctx, "petrol")
+	require.ErrorIs(t, err, repositories.ErrNotFound)
+
+	_, err = repo.GetByCode(ctx, "petrol")
+	require.ErrorIs(t, err, repositories.ErrNotFound)
+}
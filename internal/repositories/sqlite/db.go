@@ -0,0 +1,212 @@
+// Package sqlite implements repositories on top of SQLite database file, so the service runs
+// without PostgreSQL. Schema is created by migrations from migrations/sqlite.
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"task/internal/repositories"
+	"task/pkg/decimal"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+// connParams are applied to every connection. Foreign keys are off in SQLite by default,
+// WAL journal lets readers work along with a writer and busy timeout makes writers wait
+// for each other. Transactions take write lock at once, so they never fail upgrading read lock.
+const connParams = "_pragma=foreign_keys(1)&_pragma=journal_mode(WAL)&_pragma=busy_timeout(10000)&_txlock=immediate"
+
+// timeLayout keeps timestamps ordered as text.
+const timeLayout = "2006-01-02T15:04:05.000000000Z"
+
+const dateLayout = time.DateOnly
+
+// Open opens database file, creating it if it does not exist. path may be a file name
+// or "file:" URI with its own parameters.
+func Open(ctx context.Context, path string) (*sql.DB, error) {
+	sep := "?"
+	if strings.Contains(path, "?") {
+		sep = "&"
+	}
+
+	db, err := sql.Open("sqlite", path+sep+connParams)
+	if err != nil {
+		return nil, fmt.Errorf("opening database: %w", err)
+	}
+
+	err = db.PingContext(ctx)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("database ping: %w", err)
+	}
+
+	return db, nil
+}
+
+// rollback is deferred by methods using transactions, it rolls tx back if method fails.
+func rollback(tx *sql.Tx, err *error) {
+	if *err == nil {
+		return
+	}
+
+	rollbackErr := tx.Rollback()
+	if rollbackErr != nil {
+		*err = fmt.Errorf("rollback err: %w; handled err: %v", rollbackErr, *err)
+	}
+}
+
+// noRows replaces sql.ErrNoRows with repositories.ErrNotFound, so services recognize it.
+func noRows(err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return repositories.ErrNotFound
+	}
+
+	return err
+}
+
+// affected returns repositories.ErrNotFound if statement changed no rows.
+func affected(res sql.Result) error {
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return repositories.ErrNotFound
+	}
+
+	return nil
+}
+
+// inArgs returns placeholders for values starting from $first and the values as arguments.
+func inArgs[T any](first int, values []T) (placeholders string, args []any) {
+	marks := make([]string, 0, len(values))
+	for i, v := range values {
+		marks = append(marks, fmt.Sprintf("$%d", first+i))
+		args = append(args, v)
+	}
+
+	return strings.Join(marks, ", "), args
+}
+
+func timeArg(t time.Time) string {
+	return t.UTC().Format(timeLayout)
+}
+
+// timeColumn is time.Time stored as RFC 3339 string.
+type timeColumn time.Time
+
+func (c *timeColumn) Scan(src any) error {
+	s, ok := src.(string)
+	if !ok {
+		return fmt.Errorf("cannot scan %T into time", src)
+	}
+
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return fmt.Errorf("scanning time: %w", err)
+	}
+
+	*c = timeColumn(t)
+	return nil
+}
+
+// nullTimeColumn is nullable time.Time stored as RFC 3339 string.
+type nullTimeColumn struct {
+	t **time.Time
+}
+
+func (c nullTimeColumn) Scan(src any) error {
+	if src == nil {
+		*c.t = nil
+		return nil
+	}
+
+	var t timeColumn
+	err := t.Scan(src)
+	if err != nil {
+		return err
+	}
+
+	res := time.Time(t)
+	*c.t = &res
+	return nil
+}
+
+// decimalColumn is decimal.Decimal stored as decimal string.
+type decimalColumn decimal.Decimal
+
+func decimalArg(d decimal.Decimal) string {
+	return d.String()
+}
+
+// loadKeyArg returns load in the form of load_key column, see migration 000012. Negative
+// values are only compared with keys of positive loads, so "-" which sorts before digits is enough.
+func loadKeyArg(d decimal.Decimal) string {
+	if d.Sign() < 0 {
+		return "-"
+	}
+
+	intPart, fracPart, _ := strings.Cut(d.String(), ".")
+	return strings.Repeat("0", 19-len(intPart)) + intPart + fracPart + strings.Repeat("0", decimal.MaxScale-len(fracPart))
+}
+
+func (c *decimalColumn) Scan(src any) (err error) {
+	var d decimal.Decimal
+	switch v := src.(type) {
+	case string:
+		d, err = decimal.Parse(v)
+	case []byte:
+		d, err = decimal.Parse(string(v))
+	case int64:
+		d = decimal.New(v, 0)
+	default:
+		return fmt.Errorf("cannot scan %T into decimal", src)
+	}
+	if err != nil {
+		return fmt.Errorf("scanning decimal: %w", err)
+	}
+
+	*c = decimalColumn(d)
+	return nil
+}
+
+// secondsColumn is time.Duration stored in integer column as whole seconds.
+type secondsColumn time.Duration
+
+func secondsArg(d time.Duration) int64 {
+	return int64(d / time.Second)
+}
+
+func (c *secondsColumn) Scan(src any) error {
+	v, ok := src.(int64)
+	if !ok {
+		return fmt.Errorf("cannot scan %T into duration", src)
+	}
+
+	*c = secondsColumn(time.Duration(v) * time.Second)
+	return nil
+}
+
+// jsonColumn is a value stored as JSON text.
+type jsonColumn[T any] struct {
+	v *T
+}
+
+func (c jsonColumn[T]) Scan(src any) error {
+	var data []byte
+	switch v := src.(type) {
+	case string:
+		data = []byte(v)
+	case []byte:
+		data = v
+	default:
+		return fmt.Errorf("cannot scan %T into JSON", src)
+	}
+
+	return json.Unmarshal(data, c.v)
+}
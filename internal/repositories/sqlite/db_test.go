@@ -0,0 +1,49 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"github.com/stretchr/testify/require"
+	"path/filepath"
+	"slices"
+	"task/internal/integration_tests"
+	"task/pkg/decimal"
+	"testing"
+)
+
+// newTestDB returns migrated database in a file removed after the test.
+func newTestDB(t *testing.T) *sql.DB {
+	path := filepath.Join(t.TempDir(), "routes.db")
+	err := integration_tests.MigrateSQLite(path)
+	require.Nil(t, err)
+
+	db, err := Open(context.Background(), path)
+	require.Nil(t, err)
+	t.Cleanup(func() {
+		db.Close()
+	})
+
+	return db
+}
+
+func TestLoadKey(t *testing.T) {
+	db := newTestDB(t)
+
+	// keys computed by the generated column and by the application should be the same and
+	// order as the loads do
+	loads := []string{"0.000000000000000001", "0.5", "6", "9.99", "10", "100.5", "9223372036854775807"}
+	var keys []string
+	for i, load := range loads {
+		_, err := db.Exec(`insert into routes(route_id, route_name, load, cargo_type) values($1, 'test', $2, 'sand')`, i, load)
+		require.Nil(t, err)
+
+		var key string
+		err = db.QueryRow(`select load_key from routes where route_id = $1`, i).Scan(&key)
+		require.Nil(t, err)
+		require.Equal(t, loadKeyArg(decimal.MustParse(load)), key)
+		keys = append(keys, key)
+	}
+	require.True(t, slices.IsSorted(keys))
+
+	require.Less(t, loadKeyArg(decimal.MustParse("-1")), keys[0])
+}
@@ -0,0 +1,295 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"task/internal/entities"
+	"task/internal/repositories"
+	"time"
+)
+
+type jobRepo struct {
+	db *sql.DB
+}
+
+func NewJobRepo(db *sql.DB) repositories.JobRepo {
+	return &jobRepo{
+		db: db,
+	}
+}
+
+func (r *jobRepo) CreateDeleteJob(ctx context.Context, ids []int) (jobId int64, err error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer rollback(tx, &err)
+
+	now := timeArg(time.Now())
+	err = tx.QueryRowContext(
+		ctx,
+		`insert into delete_jobs(status, created_at, updated_at)
+			values($1, $2, $2)
+			returning job_id`,
+		entities.JobPending,
+		now,
+	).Scan(&jobId)
+	if err != nil {
+		return 0, fmt.Errorf("creating delete job: %w", err)
+	}
+
+	for _, id := range ids {
+		_, err = tx.ExecContext(
+			ctx,
+			`insert into delete_job_items(job_id, route_id, status)
+				values($1, $2, $3)
+				on conflict do nothing`,
+			jobId,
+			id,
+			entities.DeletePending,
+		)
+		if err != nil {
+			return 0, fmt.Errorf("creating delete job items: %w", err)
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return 0, fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return jobId, nil
+}
+
+func (r *jobRepo) GetDeleteJob(ctx context.Context, id int64) (job entities.DeleteJob, err error) {
+	var jobErr *string
+	err = r.db.QueryRowContext(
+		ctx,
+		`select
+				job_id,
+				status,
+				attempts,
+				error,
+				created_at,
+				updated_at
+			from delete_jobs
+			where job_id = $1`,
+		id,
+	).Scan(
+		&job.JobID,
+		&job.Status,
+		&job.Attempts,
+		&jobErr,
+		(*timeColumn)(&job.CreatedAt),
+		(*timeColumn)(&job.UpdatedAt),
+	)
+	if err != nil {
+		return entities.DeleteJob{}, fmt.Errorf("getting delete job by id: %w", noRows(err))
+	}
+	if jobErr != nil {
+		job.Error = *jobErr
+	}
+
+	job.Items, err = r.jobItems(ctx, id)
+	if err != nil {
+		return entities.DeleteJob{}, err
+	}
+
+	return job, nil
+}
+
+func (r *jobRepo) ListUnfinishedDeleteJobs(ctx context.Context) (jobs []entities.DeleteJob, err error) {
+	rows, err := r.db.QueryContext(
+		ctx,
+		`select job_id
+			from delete_jobs
+			where status in ($1, $2)
+			order by job_id`,
+		entities.JobPending,
+		entities.JobRunning,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("listing unfinished delete jobs: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		err = rows.Scan(&id)
+		if err != nil {
+			return nil, fmt.Errorf("listing unfinished delete jobs: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("listing unfinished delete jobs: %w", err)
+	}
+	rows.Close()
+
+	for _, id := range ids {
+		job, err := r.GetDeleteJob(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+
+	return jobs, nil
+}
+
+func (r *jobRepo) StartDeleteJob(ctx context.Context, id int64) (err error) {
+	res, err := r.db.ExecContext(
+		ctx,
+		`update delete_jobs set
+				status = $2,
+				attempts = attempts + 1,
+				updated_at = $3
+			where job_id = $1`,
+		id,
+		entities.JobRunning,
+		timeArg(time.Now()),
+	)
+	if err != nil {
+		return fmt.Errorf("starting delete job: %w", err)
+	}
+	if err = affected(res); err != nil {
+		return fmt.Errorf("starting delete job: %w", err)
+	}
+
+	return nil
+}
+
+func (r *jobRepo) FinishDeleteJob(ctx context.Context, id int64, deletedIds []int) (err error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer rollback(tx, &err)
+
+	_, err = tx.ExecContext(
+		ctx,
+		`update delete_job_items set
+				status = $2
+			where job_id = $1`,
+		id,
+		entities.DeleteNotFound,
+	)
+	if err != nil {
+		return fmt.Errorf("updating delete job items: %w", err)
+	}
+
+	if len(deletedIds) > 0 {
+		placeholders, args := inArgs(3, deletedIds)
+		_, err = tx.ExecContext(
+			ctx,
+			`update delete_job_items set
+					status = $2
+				where job_id = $1 and route_id in (`+placeholders+`)`,
+			append([]any{id, entities.DeleteDone}, args...)...,
+		)
+		if err != nil {
+			return fmt.Errorf("updating delete job items: %w", err)
+		}
+	}
+
+	err = setStatus(ctx, tx, id, entities.JobSucceeded, nil)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (r *jobRepo) FailDeleteJob(ctx context.Context, id int64, jobErr string) (err error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer rollback(tx, &err)
+
+	_, err = tx.ExecContext(
+		ctx,
+		`update delete_job_items set
+				status = $2
+			where job_id = $1`,
+		id,
+		entities.DeleteFailed,
+	)
+	if err != nil {
+		return fmt.Errorf("updating delete job items: %w", err)
+	}
+
+	err = setStatus(ctx, tx, id, entities.JobFailed, &jobErr)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func setStatus(ctx context.Context, tx *sql.Tx, id int64, status entities.JobStatus, jobErr *string) error {
+	res, err := tx.ExecContext(
+		ctx,
+		`update delete_jobs set
+				status = $2,
+				error = $3,
+				updated_at = $4
+			where job_id = $1`,
+		id,
+		status,
+		jobErr,
+		timeArg(time.Now()),
+	)
+	if err != nil {
+		return fmt.Errorf("updating delete job status: %w", err)
+	}
+	if err = affected(res); err != nil {
+		return fmt.Errorf("updating delete job status: %w", err)
+	}
+
+	return nil
+}
+
+func (r *jobRepo) jobItems(ctx context.Context, id int64) (items []entities.DeleteJobItem, err error) {
+	rows, err := r.db.QueryContext(
+		ctx,
+		`select
+				route_id,
+				status
+			from delete_job_items
+			where job_id = $1
+			order by route_id`,
+		id,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("getting delete job items: %w", err)
+	}
+	defer rows.Close()
+
+	items = make([]entities.DeleteJobItem, 0)
+	for rows.Next() {
+		var item entities.DeleteJobItem
+		err = rows.Scan(&item.RouteID, &item.Status)
+		if err != nil {
+			return nil, fmt.Errorf("scanning delete job item: %w", err)
+		}
+		items = append(items, item)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("getting delete job items: %w", err)
+	}
+
+	return items, nil
+}
@@ -0,0 +1,703 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"strings"
+	"task/internal/entities"
+	"task/internal/repositories"
+	"task/pkg/decimal"
+	"time"
+	"unicode/utf8"
+)
+
+type routeRepo struct {
+	db *sql.DB
+}
+
+// NewRouteRepo returns RouteRepo with the same semantics as PostgreSQL one. Loads are compared,
+// summed up and search is ranked by the application, as SQLite has neither exact numbers nor
+// trigram similarity.
+func NewRouteRepo(db *sql.DB) repositories.RouteRepo {
+	return &routeRepo{
+		db: db,
+	}
+}
+
+const selectRoutes = `select
+		route_id,
+		route_name,
+		load,
+		cargo_type,
+		is_actual,
+		waypoints,
+		distance_m,
+		duration_s,
+		vehicle_id
+	from routes`
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanRoute(row scanner) (route entities.Route, err error) {
+	err = row.Scan(
+		&route.RouteID,
+		&route.RouteName,
+		(*decimalColumn)(&route.Load),
+		&route.CargoType,
+		&route.IsActual,
+		(*waypointsColumn)(&route.Waypoints),
+		&route.Distance,
+		(*secondsColumn)(&route.Duration),
+		&route.VehicleID,
+	)
+	if err != nil {
+		return entities.Route{}, err
+	}
+
+	return route, nil
+}
+
+func (r *routeRepo) Register(ctx context.Context, route entities.Route) (routeId int, err error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer rollback(tx, &err)
+
+	routeId, err = register(ctx, tx, route, time.Now())
+	if err != nil {
+		return 0, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return 0, fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return routeId, nil
+}
+
+// RegisterBatch registers all routes in one transaction, so either all of them are stored or none.
+func (r *routeRepo) RegisterBatch(ctx context.Context, routes []entities.Route) (routeIds []int, err error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer rollback(tx, &err)
+
+	now := time.Now()
+	routeIds = make([]int, 0, len(routes))
+	for i, route := range routes {
+		routeId, err := register(ctx, tx, route, now)
+		if err != nil {
+			return nil, fmt.Errorf("route #%d: %w", i, err)
+		}
+		routeIds = append(routeIds, routeId)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return routeIds, nil
+}
+
+// register inserts route. If route id is already taken, existing route is marked as not actual
+// and the new one gets next free id.
+func register(ctx context.Context, tx *sql.Tx, route entities.Route, now time.Time) (routeId int, err error) {
+	waypoints, err := waypointsArg(route.Waypoints)
+	if err != nil {
+		return 0, fmt.Errorf("register route: %w", err)
+	}
+	args := []any{
+		route.RouteID,
+		route.RouteName,
+		decimalArg(route.Load),
+		route.CargoType,
+		waypoints,
+		route.Distance,
+		secondsArg(route.Duration),
+	}
+
+	res, err := tx.ExecContext(
+		ctx,
+		`insert into routes(route_id, route_name, load, cargo_type, waypoints, distance_m, duration_s)
+			values($1, $2, $3, $4, $5, $6, $7)
+			on conflict(route_id) do nothing`,
+		args...,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("register route: %w", err)
+	}
+
+	routeId = route.RouteID
+	supersededRouteId := -1
+	if err = affected(res); err != nil {
+		_, err = tx.ExecContext(ctx, `update routes set is_actual = false where route_id = $1`, route.RouteID)
+		if err != nil {
+			return 0, fmt.Errorf("register route: %w", err)
+		}
+
+		err = tx.QueryRowContext(
+			ctx,
+			`insert into routes(route_id, route_name, load, cargo_type, waypoints, distance_m, duration_s)
+				select max(route_id) + 1, $2, $3, $4, $5, $6, $7
+				from routes
+				returning route_id`,
+			args...,
+		).Scan(&routeId)
+		if err != nil {
+			return 0, fmt.Errorf("register route: %w", err)
+		}
+		supersededRouteId = route.RouteID
+	}
+	route.RouteID = routeId
+
+	err = recordVersion(ctx, tx, route, supersededRouteId, now)
+	if err != nil {
+		return 0, err
+	}
+
+	return routeId, nil
+}
+
+func (r *routeRepo) GetById(ctx context.Context, id int) (route entities.Route, err error) {
+	route, err = scanRoute(r.db.QueryRowContext(ctx, selectRoutes+` where route_id = $1`, id))
+	if err != nil {
+		return entities.Route{}, fmt.Errorf("getting route by id: %w", noRows(err))
+	}
+
+	return route, nil
+}
+
+func (r *routeRepo) List(ctx context.Context, filter entities.RouteFilter) (routes []entities.Route, err error) {
+	routes = make([]entities.Route, 0, filter.Limit)
+	err = r.queryRoutes(ctx, filter, func(route entities.Route) error {
+		routes = append(routes, route)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("listing routes: %w", err)
+	}
+
+	return routes, nil
+}
+
+// Export calls fn for every route matching the filter, reading them from database cursor one by one.
+// Zero limit means no limit.
+func (r *routeRepo) Export(ctx context.Context, filter entities.RouteFilter, fn func(entities.Route) error) (err error) {
+	err = r.queryRoutes(ctx, filter, fn)
+	if err != nil {
+		return fmt.Errorf("exporting routes: %w", err)
+	}
+
+	return nil
+}
+
+// queryRoutes filters routes by load in the application, where loads are compared exactly,
+// so limit is applied there too.
+func (r *routeRepo) queryRoutes(ctx context.Context, filter entities.RouteFilter, fn func(entities.Route) error) (err error) {
+	var (
+		conds []string
+		args  []any
+	)
+	addCond := func(cond string, arg any) {
+		args = append(args, arg)
+		conds = append(conds, fmt.Sprintf(cond, len(args)))
+	}
+
+	if filter.RouteIDs != nil {
+		placeholders, ids := inArgs(len(args)+1, filter.RouteIDs)
+		conds = append(conds, "route_id in ("+placeholders+")")
+		args = append(args, ids...)
+	}
+	if filter.AfterID != nil {
+		addCond("route_id > $%d", *filter.AfterID)
+	}
+	if filter.CargoType != "" {
+		addCond("cargo_type = $%d", filter.CargoType)
+	}
+	if filter.IsActual != nil {
+		addCond("is_actual = $%d", *filter.IsActual)
+	}
+	if filter.NamePrefix != "" {
+		// like is case-insensitive in SQLite
+		args = append(args, utf8.RuneCountInString(filter.NamePrefix), filter.NamePrefix)
+		conds = append(conds, fmt.Sprintf("substr(route_name, 1, $%d) = $%d", len(args)-1, len(args)))
+	}
+	if filter.VehicleID != nil {
+		addCond("vehicle_id = $%d", *filter.VehicleID)
+	}
+	if filter.MinLoad != nil {
+		addCond("load_key >= $%d", loadKeyArg(*filter.MinLoad))
+	}
+	if filter.MaxLoad != nil {
+		addCond("load_key <= $%d", loadKeyArg(*filter.MaxLoad))
+	}
+
+	query := selectRoutes
+	if len(conds) > 0 {
+		query += " where " + strings.Join(conds, " and ")
+	}
+	query += " order by route_id"
+	if filter.Limit > 0 {
+		args = append(args, filter.Limit)
+		query += fmt.Sprintf(" limit $%d", len(args))
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		route, err := scanRoute(rows)
+		if err != nil {
+			return fmt.Errorf("scanning route: %w", err)
+		}
+
+		err = fn(route)
+		if err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+func (r *routeRepo) Update(ctx context.Context, route entities.Route) (err error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer rollback(tx, &err)
+
+	waypoints, err := waypointsArg(route.Waypoints)
+	if err != nil {
+		return fmt.Errorf("updating route: %w", err)
+	}
+
+	res, err := tx.ExecContext(
+		ctx,
+		`update routes set
+				route_name = $2,
+				load = $3,
+				cargo_type = $4,
+				waypoints = $5,
+				distance_m = $6,
+				duration_s = $7
+			where route_id = $1 and is_actual`,
+		route.RouteID,
+		route.RouteName,
+		decimalArg(route.Load),
+		route.CargoType,
+		waypoints,
+		route.Distance,
+		secondsArg(route.Duration),
+	)
+	if err != nil {
+		return fmt.Errorf("updating route: %w", err)
+	}
+	if err = affected(res); err != nil {
+		return fmt.Errorf("updating route: %w", err)
+	}
+
+	err = recordVersion(ctx, tx, route, route.RouteID, time.Now())
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// Assign checks the vehicle and binds it in one transaction. Transactions take the write lock
+// at start, so neither the route nor the vehicle can change in between.
+func (r *routeRepo) Assign(ctx context.Context, routeID int, vehicleID int) (err error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer rollback(tx, &err)
+
+	var load decimal.Decimal
+	err = tx.QueryRowContext(
+		ctx,
+		`select load from routes where route_id = $1 and is_actual`,
+		routeID,
+	).Scan((*decimalColumn)(&load))
+	if err != nil {
+		return fmt.Errorf("assigning vehicle: %w", noRows(err))
+	}
+
+	var (
+		capacity decimal.Decimal
+		status   entities.VehicleStatus
+	)
+	err = tx.QueryRowContext(
+		ctx,
+		`select capacity, status from vehicles where vehicle_id = $1`,
+		vehicleID,
+	).Scan((*decimalColumn)(&capacity), &status)
+	if err != nil {
+		return fmt.Errorf("getting vehicle: %w", noRows(err))
+	}
+	if status != entities.VehicleAvailable || capacity.Cmp(load) < 0 {
+		return fmt.Errorf("assigning vehicle: %w", repositories.ErrVehicleUnfit)
+	}
+
+	_, err = tx.ExecContext(
+		ctx,
+		`update routes set vehicle_id = $2 where route_id = $1`,
+		routeID,
+		vehicleID,
+	)
+	if err != nil {
+		return fmt.Errorf("assigning vehicle: %w", err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (r *routeRepo) Stats(ctx context.Context) (stats []entities.RouteStats, err error) {
+	rows, err := r.db.QueryContext(ctx, `select cargo_type, is_actual, load from routes`)
+	if err != nil {
+		return nil, fmt.Errorf("getting route stats: %w", err)
+	}
+	defer rows.Close()
+
+	var routes []entities.Route
+	for rows.Next() {
+		var route entities.Route
+		err = rows.Scan(&route.CargoType, &route.IsActual, (*decimalColumn)(&route.Load))
+		if err != nil {
+			return nil, fmt.Errorf("scanning route: %w", err)
+		}
+		routes = append(routes, route)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("getting route stats: %w", err)
+	}
+
+	stats, err = entities.AggregateStats(routes)
+	if err != nil {
+		return nil, fmt.Errorf("getting route stats: %w", err)
+	}
+
+	return stats, nil
+}
+
+// Search ranks names of actual routes with entities.SearchRank.
+func (r *routeRepo) Search(ctx context.Context, query string, limit int) (routes []entities.Route, err error) {
+	actual, err := r.actualRoutes(ctx, "")
+	if err != nil {
+		return nil, fmt.Errorf("searching routes: %w", err)
+	}
+
+	return entities.RankRoutes(query, actual, limit), nil
+}
+
+// Near returns actual routes passing within radius meters of the point, ordered by id.
+func (r *routeRepo) Near(ctx context.Context, point entities.Waypoint, radius float64) (routes []entities.Route, err error) {
+	actual, err := r.actualRoutes(ctx, ` and waypoints <> '[]'`)
+	if err != nil {
+		return nil, fmt.Errorf("finding routes near point: %w", err)
+	}
+
+	routes = make([]entities.Route, 0)
+	for _, route := range actual {
+		if route.PassesNear(point, radius) {
+			routes = append(routes, route)
+		}
+	}
+
+	return routes, nil
+}
+
+// Within returns actual routes passing through the box, ordered by id.
+func (r *routeRepo) Within(ctx context.Context, box entities.BBox) (routes []entities.Route, err error) {
+	actual, err := r.actualRoutes(ctx, ` and waypoints <> '[]'`)
+	if err != nil {
+		return nil, fmt.Errorf("finding routes within box: %w", err)
+	}
+
+	routes = make([]entities.Route, 0)
+	for _, route := range actual {
+		if route.Crosses(box) {
+			routes = append(routes, route)
+		}
+	}
+
+	return routes, nil
+}
+
+// actualRoutes returns actual routes ordered by id, cond is appended to where clause.
+func (r *routeRepo) actualRoutes(ctx context.Context, cond string) (routes []entities.Route, err error) {
+	rows, err := r.db.QueryContext(ctx, selectRoutes+` where is_actual`+cond+` order by route_id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		route, err := scanRoute(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanning route: %w", err)
+		}
+		routes = append(routes, route)
+	}
+
+	return routes, rows.Err()
+}
+
+func (r *routeRepo) History(ctx context.Context, id int) (versions []entities.RouteVersion, err error) {
+	rows, err := r.db.QueryContext(
+		ctx,
+		`with recursive seed as (
+			select version_id
+			from route_versions
+			where route_id = $1
+		), predecessors(version_id) as (
+			select version_id from seed
+			union
+			select v.version_id
+			from route_versions v
+				join predecessors p on v.superseded_by = p.version_id
+		), successors(version_id) as (
+			select version_id from seed
+			union
+			select v.superseded_by
+			from route_versions v
+				join successors s on v.version_id = s.version_id
+			where v.superseded_by is not null
+		)
+		select
+			version_id,
+			route_id,
+			route_name,
+			load,
+			cargo_type,
+			waypoints,
+			created_at,
+			superseded_by,
+			superseded_at
+		from route_versions
+		where version_id in (
+			select version_id from predecessors
+			union
+			select version_id from successors
+		)
+		order by created_at, version_id`,
+		id,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("getting route history: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var version entities.RouteVersion
+		err = rows.Scan(
+			&version.VersionID,
+			&version.Route.RouteID,
+			&version.Route.RouteName,
+			(*decimalColumn)(&version.Route.Load),
+			&version.Route.CargoType,
+			(*waypointsColumn)(&version.Route.Waypoints),
+			(*timeColumn)(&version.CreatedAt),
+			&version.SupersededBy,
+			nullTimeColumn{t: &version.SupersededAt},
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scanning route version: %w", err)
+		}
+		version.Route.IsActual = version.SupersededBy == nil
+		versions = append(versions, version)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("getting route history: %w", err)
+	}
+
+	if len(versions) == 0 {
+		return nil, fmt.Errorf("getting route history: %w", repositories.ErrNotFound)
+	}
+
+	return versions, nil
+}
+
+// recordVersion appends route snapshot to route_versions. If supersededRouteId is non-negative,
+// the last version in the chain of that route is linked to the new one, and the route owning
+// that version is marked as not actual unless it is the route being recorded. So every chain
+// ends with the only actual version.
+func recordVersion(ctx context.Context, tx *sql.Tx, route entities.Route, supersededRouteId int, now time.Time) (err error) {
+	var tailId *int64
+	if supersededRouteId >= 0 {
+		err = tx.QueryRowContext(
+			ctx,
+			`with recursive chain(version_id, superseded_by) as (
+				select version_id, superseded_by
+				from route_versions
+				where version_id = (
+					select max(version_id)
+					from route_versions
+					where route_id = $1
+				)
+				union all
+				select v.version_id, v.superseded_by
+				from route_versions v
+					join chain c on v.version_id = c.superseded_by
+			)
+			select max(version_id)
+			from chain
+			where superseded_by is null`,
+			supersededRouteId,
+		).Scan(&tailId)
+		if err != nil {
+			return fmt.Errorf("finding last route version: %w", err)
+		}
+	}
+
+	waypoints, err := waypointsArg(route.Waypoints)
+	if err != nil {
+		return fmt.Errorf("inserting route version: %w", err)
+	}
+
+	var versionId int64
+	err = tx.QueryRowContext(
+		ctx,
+		`insert into route_versions(route_id, route_name, load, cargo_type, waypoints, created_at)
+			values($1, $2, $3, $4, $5, $6)
+			returning version_id`,
+		route.RouteID,
+		route.RouteName,
+		decimalArg(route.Load),
+		route.CargoType,
+		waypoints,
+		timeArg(now),
+	).Scan(&versionId)
+	if err != nil {
+		return fmt.Errorf("inserting route version: %w", err)
+	}
+
+	if tailId == nil {
+		return nil
+	}
+
+	_, err = tx.ExecContext(
+		ctx,
+		`update route_versions set
+				superseded_by = $2,
+				superseded_at = $3
+			where version_id = $1`,
+		*tailId,
+		versionId,
+		timeArg(now),
+	)
+	if err != nil {
+		return fmt.Errorf("superseding route version: %w", err)
+	}
+
+	_, err = tx.ExecContext(
+		ctx,
+		`update routes set is_actual = false
+			where route_id = (select route_id from route_versions where version_id = $1)
+				and route_id <> $2`,
+		*tailId,
+		route.RouteID,
+	)
+	if err != nil {
+		return fmt.Errorf("superseding route: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteById deletes routes and returns ids of those which existed.
+func (r *routeRepo) DeleteById(ctx context.Context, ids []int) (deletedIds []int, err error) {
+	deletedIds = make([]int, 0, len(ids))
+	if len(ids) == 0 {
+		return deletedIds, nil
+	}
+
+	placeholders, args := inArgs(1, ids)
+	rows, err := r.db.QueryContext(
+		ctx,
+		`delete from routes where route_id in (`+placeholders+`) returning route_id`,
+		args...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("deleting route by id: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id int
+		err = rows.Scan(&id)
+		if err != nil {
+			return nil, fmt.Errorf("deleting route by id: %w", err)
+		}
+		deletedIds = append(deletedIds, id)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("deleting route by id: %w", err)
+	}
+
+	return deletedIds, nil
+}
+
+// Import reads all routes of the source and merges them in the order of lines, with the same
+// conflict semantics as Register. Import is atomic.
+func (r *routeRepo) Import(ctx context.Context, src repositories.ImportSource) (report entities.ImportReport, err error) {
+	var rows []entities.ImportedRoute
+	for src.Next() {
+		rows = append(rows, src.Route())
+	}
+	if err = src.Err(); err != nil {
+		return entities.ImportReport{}, fmt.Errorf("reading import source: %w", err)
+	}
+	slices.SortStableFunc(rows, func(a, b entities.ImportedRoute) int {
+		return a.Line - b.Line
+	})
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return entities.ImportReport{}, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer rollback(tx, &err)
+
+	now := time.Now()
+	for _, row := range rows {
+		var routeId int
+		routeId, err = register(ctx, tx, row.Route, now)
+		if err != nil {
+			return entities.ImportReport{}, fmt.Errorf("line %d: %w", row.Line, err)
+		}
+
+		report.Imported++
+		if routeId != row.Route.RouteID {
+			report.Reissued++
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return entities.ImportReport{}, fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return report, nil
+}
@@ -0,0 +1,102 @@
+package sqlite
+
+import (
+	"context"
+	"github.com/stretchr/testify/require"
+	"task/internal/entities"
+	"task/internal/repositories"
+	"task/internal/repositories/repotest"
+	"task/pkg/decimal"
+	"testing"
+)
+
+func TestRouteRepoContract(t *testing.T) {
+	repotest.TestRouteRepo(t, func(t *testing.T) repositories.RouteRepo {
+		db := newTestDB(t)
+
+		vehicleID, err := NewVehicleRepo(db).Create(context.Background(), entities.Vehicle{
+			Name:     "Truck",
+			Capacity: decimal.MustParse("1000"),
+			Status:   entities.VehicleAvailable,
+		})
+		require.Nil(t, err)
+		require.Equal(t, repotest.VehicleID, vehicleID)
+
+		return NewRouteRepo(db)
+	})
+}
+
+func TestListNamePrefixIsCaseSensitive(t *testing.T) {
+	repo := NewRouteRepo(newTestDB(t))
+	ctx := context.Background()
+
+	for i, name := range []string{"Москва - Тверь", "москва - Клин", "Moscow - Tver"} {
+		_, err := repo.Register(ctx, entities.Route{RouteID: i + 1, RouteName: name, Load: decimal.MustParse("1"), CargoType: "sand"})
+		require.Nil(t, err)
+	}
+
+	routes, err := repo.List(ctx, entities.RouteFilter{NamePrefix: "Москва"})
+	require.Nil(t, err)
+	require.Len(t, routes, 1)
+	require.Equal(t, 1, routes[0].RouteID)
+}
+
+func TestAssignUnknownVehicle(t *testing.T) {
+	repo := NewRouteRepo(newTestDB(t))
+	ctx := context.Background()
+
+	routeID, err := repo.Register(ctx, entities.Route{RouteID: 1, RouteName: "route", Load: decimal.MustParse("1"), CargoType: "sand"})
+	require.Nil(t, err)
+
+	err = repo.Assign(ctx, routeID, 100)
+	require.ErrorIs(t, err, repositories.ErrNotFound)
+}
+
+func TestAssignChecksVehicle(t *testing.T) {
+	db := newTestDB(t)
+	repo := NewRouteRepo(db)
+	vehicles := NewVehicleRepo(db)
+	ctx := context.Background()
+
+	routeID, err := repo.Register(ctx, entities.Route{RouteID: 1, RouteName: "route", Load: decimal.MustParse("1000.5"), CargoType: "sand"})
+	require.Nil(t, err)
+
+	testCases := []struct {
+		name    string
+		vehicle entities.Vehicle
+		err     error
+	}{
+		{
+			name:    "capacity is less than load",
+			vehicle: entities.Vehicle{Name: "Van", Capacity: decimal.MustParse("1000"), Status: entities.VehicleAvailable},
+			err:     repositories.ErrVehicleUnfit,
+		},
+		{
+			name:    "vehicle is not available",
+			vehicle: entities.Vehicle{Name: "Broken truck", Capacity: decimal.MustParse("20000"), Status: entities.VehicleMaintenance},
+			err:     repositories.ErrVehicleUnfit,
+		},
+		{
+			name:    "vehicle fits",
+			vehicle: entities.Vehicle{Name: "Truck", Capacity: decimal.MustParse("1000.5"), Status: entities.VehicleAvailable},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			vehicleID, err := vehicles.Create(ctx, tc.vehicle)
+			require.Nil(t, err)
+
+			err = repo.Assign(ctx, routeID, vehicleID)
+
+			found, getErr := repo.GetById(ctx, routeID)
+			require.Nil(t, getErr)
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+				require.NotEqual(t, &vehicleID, found.VehicleID)
+			} else {
+				require.Nil(t, err)
+				require.Equal(t, &vehicleID, found.VehicleID)
+			}
+		})
+	}
+}
@@ -0,0 +1,178 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"task/internal/entities"
+	"task/internal/repositories"
+	"time"
+)
+
+type scheduleRepo struct {
+	db *sql.DB
+}
+
+func NewScheduleRepo(db *sql.DB) repositories.ScheduleRepo {
+	return &scheduleRepo{
+		db: db,
+	}
+}
+
+const selectSchedules = `select
+		s.route_id,
+		s.departure_from,
+		s.departure_to,
+		s.arrival_from,
+		s.arrival_to,
+		s.recurrence,
+		s.timezone,
+		s.valid_from,
+		s.valid_until
+	from route_schedules s`
+
+func (r *scheduleRepo) Set(ctx context.Context, schedule entities.Schedule) (err error) {
+	_, err = r.db.ExecContext(
+		ctx,
+		`insert into route_schedules(route_id, departure_from, departure_to, arrival_from, arrival_to, recurrence, timezone, valid_from, valid_until)
+			values($1, $2, $3, $4, $5, $6, $7, $8, $9)
+			on conflict(route_id) do update set
+				departure_from = excluded.departure_from,
+				departure_to = excluded.departure_to,
+				arrival_from = excluded.arrival_from,
+				arrival_to = excluded.arrival_to,
+				recurrence = excluded.recurrence,
+				timezone = excluded.timezone,
+				valid_from = excluded.valid_from,
+				valid_until = excluded.valid_until`,
+		schedule.RouteID,
+		secondsArg(schedule.Departure.From),
+		secondsArg(schedule.Departure.To),
+		secondsArg(schedule.Arrival.From),
+		secondsArg(schedule.Arrival.To),
+		schedule.Recurrence.String(),
+		schedule.Timezone,
+		dateArg(schedule.ValidFrom),
+		dateArg(schedule.ValidUntil),
+	)
+	if err != nil {
+		return fmt.Errorf("setting route schedule: %w", err)
+	}
+
+	return nil
+}
+
+func (r *scheduleRepo) Get(ctx context.Context, routeID int) (schedule entities.Schedule, err error) {
+	schedule, err = scanSchedule(r.db.QueryRowContext(ctx, selectSchedules+` where s.route_id = $1`, routeID))
+	if err != nil {
+		return entities.Schedule{}, fmt.Errorf("getting route schedule: %w", noRows(err))
+	}
+
+	return schedule, nil
+}
+
+func (r *scheduleRepo) List(ctx context.Context, routeIDs []int) (schedules []entities.Schedule, err error) {
+	query := selectSchedules + `
+		join routes using(route_id)
+		where routes.is_actual`
+	var args []any
+	if routeIDs != nil {
+		var placeholders string
+		placeholders, args = inArgs(1, routeIDs)
+		query += ` and s.route_id in (` + placeholders + `)`
+	}
+	query += ` order by s.route_id`
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("listing route schedules: %w", err)
+	}
+	defer rows.Close()
+
+	schedules = make([]entities.Schedule, 0)
+	for rows.Next() {
+		schedule, err := scanSchedule(rows)
+		if err != nil {
+			return nil, fmt.Errorf("listing route schedules: %w", err)
+		}
+		schedules = append(schedules, schedule)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("listing route schedules: %w", err)
+	}
+
+	return schedules, nil
+}
+
+func (r *scheduleRepo) Delete(ctx context.Context, routeID int) (err error) {
+	res, err := r.db.ExecContext(ctx, `delete from route_schedules where route_id = $1`, routeID)
+	if err != nil {
+		return fmt.Errorf("deleting route schedule: %w", err)
+	}
+	if err = affected(res); err != nil {
+		return fmt.Errorf("deleting route schedule: %w", err)
+	}
+
+	return nil
+}
+
+func scanSchedule(row scanner) (schedule entities.Schedule, err error) {
+	var recurrence string
+	err = row.Scan(
+		&schedule.RouteID,
+		(*secondsColumn)(&schedule.Departure.From),
+		(*secondsColumn)(&schedule.Departure.To),
+		(*secondsColumn)(&schedule.Arrival.From),
+		(*secondsColumn)(&schedule.Arrival.To),
+		&recurrence,
+		&schedule.Timezone,
+		nullDateColumn{d: &schedule.ValidFrom},
+		nullDateColumn{d: &schedule.ValidUntil},
+	)
+	if err != nil {
+		return entities.Schedule{}, fmt.Errorf("scanning route schedule: %w", err)
+	}
+
+	schedule.Recurrence, err = entities.ParseRecurrence(recurrence)
+	if err != nil {
+		return entities.Schedule{}, fmt.Errorf("scanning route schedule: %w", err)
+	}
+
+	return schedule, nil
+}
+
+// dateArg stores date as YYYY-MM-DD string, nil as NULL.
+func dateArg(d *time.Time) *string {
+	if d == nil {
+		return nil
+	}
+
+	s := d.Format(dateLayout)
+	return &s
+}
+
+// nullDateColumn is nullable date stored as YYYY-MM-DD string, it is scanned as midnight UTC
+// like PostgreSQL date.
+type nullDateColumn struct {
+	d **time.Time
+}
+
+func (c nullDateColumn) Scan(src any) error {
+	if src == nil {
+		*c.d = nil
+		return nil
+	}
+
+	s, ok := src.(string)
+	if !ok {
+		return fmt.Errorf("cannot scan %T into date", src)
+	}
+
+	d, err := time.Parse(dateLayout, s)
+	if err != nil {
+		return fmt.Errorf("scanning date: %w", err)
+	}
+
+	*c.d = &d
+	return nil
+}
@@ -0,0 +1,80 @@
+package sqlite
+
+import (
+	"context"
+	"github.com/stretchr/testify/require"
+	"task/internal/entities"
+	"task/internal/repositories"
+	"task/pkg/decimal"
+	"testing"
+	"time"
+)
+
+func TestSchedules(t *testing.T) {
+	db := newTestDB(t)
+	repo := NewScheduleRepo(db)
+	routes := NewRouteRepo(db)
+	ctx := context.Background()
+
+	for _, id := range []int{1, 40, 40} {
+		_, err := routes.Register(ctx, entities.Route{RouteID: id, RouteName: "route", Load: decimal.MustParse("1"), CargoType: "sand"})
+		require.Nil(t, err)
+	}
+
+	validFrom := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	actual := entities.Schedule{
+		RouteID:    1,
+		Departure:  entities.TimeWindow{From: 8 * time.Hour, To: 8*time.Hour + 30*time.Minute},
+		Arrival:    entities.TimeWindow{From: 26 * time.Hour, To: 27 * time.Hour},
+		Recurrence: entities.MustParseRecurrence("1,15 * mon-fri"),
+		Timezone:   "Europe/Moscow",
+		ValidFrom:  &validFrom,
+	}
+	// route 40 is superseded by the second registration
+	notActual := entities.Schedule{
+		RouteID:    40,
+		Departure:  entities.TimeWindow{From: 0, To: time.Hour},
+		Arrival:    entities.TimeWindow{From: time.Hour, To: 2 * time.Hour},
+		Recurrence: entities.MustParseRecurrence("daily"),
+		Timezone:   "UTC",
+	}
+
+	err := repo.Set(ctx, actual)
+	require.Nil(t, err)
+	err = repo.Set(ctx, notActual)
+	require.Nil(t, err)
+
+	found, err := repo.Get(ctx, 1)
+	require.Nil(t, err)
+	require.Equal(t, actual, found)
+
+	schedules, err := repo.List(ctx, nil)
+	require.Nil(t, err)
+	require.Equal(t, []entities.Schedule{actual}, schedules)
+
+	schedules, err = repo.List(ctx, []int{40})
+	require.Nil(t, err)
+	require.Empty(t, schedules)
+
+	// setting schedule again replaces it
+	actual.Recurrence = entities.MustParseRecurrence("weekdays")
+	actual.ValidFrom = nil
+	err = repo.Set(ctx, actual)
+	require.Nil(t, err)
+
+	found, err = repo.Get(ctx, 1)
+	require.Nil(t, err)
+	require.Equal(t, actual, found)
+
+	err = repo.Set(ctx, entities.Schedule{RouteID: 1000, Recurrence: entities.MustParseRecurrence("daily"), Timezone: "UTC"})
+	require.NotNil(t, err)
+
+	err = repo.Delete(ctx, 1)
+	require.Nil(t, err)
+
+	_, err = repo.Get(ctx, 1)
+	require.ErrorIs(t, err, repositories.ErrNotFound)
+
+	err = repo.Delete(ctx, 1)
+	require.ErrorIs(t, err, repositories.ErrNotFound)
+}
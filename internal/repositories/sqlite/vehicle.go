@@ -0,0 +1,190 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"task/internal/entities"
+	"task/internal/repositories"
+)
+
+type vehicleRepo struct {
+	db *sql.DB
+}
+
+func NewVehicleRepo(db *sql.DB) repositories.VehicleRepo {
+	return &vehicleRepo{
+		db: db,
+	}
+}
+
+const selectVehicles = `select
+		v.vehicle_id,
+		v.name,
+		v.capacity,
+		v.status,
+		(
+			select json_group_array(code)
+			from (
+				select c.code
+				from vehicle_cargo_types c
+				where c.vehicle_id = v.vehicle_id
+				order by c.code
+			)
+		)
+	from vehicles v`
+
+func scanVehicle(row scanner) (vehicle entities.Vehicle, err error) {
+	err = row.Scan(
+		&vehicle.VehicleID,
+		&vehicle.Name,
+		(*decimalColumn)(&vehicle.Capacity),
+		&vehicle.Status,
+		jsonColumn[[]string]{v: &vehicle.CargoTypes},
+	)
+	if err != nil {
+		return entities.Vehicle{}, err
+	}
+
+	return vehicle, nil
+}
+
+func (r *vehicleRepo) Create(ctx context.Context, vehicle entities.Vehicle) (id int, err error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer rollback(tx, &err)
+
+	err = tx.QueryRowContext(
+		ctx,
+		`insert into vehicles(name, capacity, status)
+			values($1, $2, $3)
+			returning vehicle_id`,
+		vehicle.Name,
+		decimalArg(vehicle.Capacity),
+		vehicle.Status,
+	).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("inserting vehicle: %w", err)
+	}
+	vehicle.VehicleID = id
+
+	err = insertVehicleCargoTypes(ctx, tx, vehicle)
+	if err != nil {
+		return 0, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return 0, fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return id, nil
+}
+
+func (r *vehicleRepo) GetById(ctx context.Context, id int) (vehicle entities.Vehicle, err error) {
+	vehicle, err = scanVehicle(r.db.QueryRowContext(ctx, selectVehicles+` where v.vehicle_id = $1`, id))
+	if err != nil {
+		return entities.Vehicle{}, fmt.Errorf("getting vehicle by id: %w", noRows(err))
+	}
+
+	return vehicle, nil
+}
+
+func (r *vehicleRepo) List(ctx context.Context) (vehicles []entities.Vehicle, err error) {
+	rows, err := r.db.QueryContext(ctx, selectVehicles+` order by v.vehicle_id`)
+	if err != nil {
+		return nil, fmt.Errorf("listing vehicles: %w", err)
+	}
+	defer rows.Close()
+
+	vehicles = make([]entities.Vehicle, 0)
+	for rows.Next() {
+		vehicle, err := scanVehicle(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanning vehicle: %w", err)
+		}
+		vehicles = append(vehicles, vehicle)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("listing vehicles: %w", err)
+	}
+
+	return vehicles, nil
+}
+
+// Update replaces attributes and allowed cargo types of existing vehicle.
+func (r *vehicleRepo) Update(ctx context.Context, vehicle entities.Vehicle) (err error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer rollback(tx, &err)
+
+	res, err := tx.ExecContext(
+		ctx,
+		`update vehicles set
+				name = $2,
+				capacity = $3,
+				status = $4
+			where vehicle_id = $1`,
+		vehicle.VehicleID,
+		vehicle.Name,
+		decimalArg(vehicle.Capacity),
+		vehicle.Status,
+	)
+	if err != nil {
+		return fmt.Errorf("updating vehicle: %w", err)
+	}
+	if err = affected(res); err != nil {
+		return fmt.Errorf("updating vehicle: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `delete from vehicle_cargo_types where vehicle_id = $1`, vehicle.VehicleID)
+	if err != nil {
+		return fmt.Errorf("deleting vehicle cargo types: %w", err)
+	}
+
+	err = insertVehicleCargoTypes(ctx, tx, vehicle)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func insertVehicleCargoTypes(ctx context.Context, tx *sql.Tx, vehicle entities.Vehicle) (err error) {
+	for _, code := range vehicle.CargoTypes {
+		_, err = tx.ExecContext(
+			ctx,
+			`insert into vehicle_cargo_types(vehicle_id, code)
+				values($1, $2)`,
+			vehicle.VehicleID,
+			code,
+		)
+		if err != nil {
+			return fmt.Errorf("inserting vehicle cargo types: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Delete removes vehicle. Routes it was assigned to are left without vehicle.
+func (r *vehicleRepo) Delete(ctx context.Context, id int) (err error) {
+	res, err := r.db.ExecContext(ctx, `delete from vehicles where vehicle_id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("deleting vehicle: %w", err)
+	}
+	if err = affected(res); err != nil {
+		return fmt.Errorf("deleting vehicle: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,82 @@
+package sqlite
+
+import (
+	"context"
+	"github.com/stretchr/testify/require"
+	"task/internal/entities"
+	"task/internal/repositories"
+	"task/pkg/decimal"
+	"testing"
+)
+
+func TestVehicles(t *testing.T) {
+	db := newTestDB(t)
+	repo := NewVehicleRepo(db)
+	routes := NewRouteRepo(db)
+	ctx := context.Background()
+
+	cargo := NewCargoTypeRepo(db)
+	for _, code := range []string{"ore", "sand"} {
+		err := cargo.Create(ctx, entities.CargoType{Code: code, DisplayName: code, Unit: entities.UnitTonne})
+		require.Nil(t, err)
+	}
+
+	truck := entities.Vehicle{
+		Name:       "Truck",
+		Capacity:   decimal.MustParse("20000.5"),
+		CargoTypes: []string{"ore", "sand"},
+		Status:     entities.VehicleAvailable,
+	}
+
+	var err error
+	truck.VehicleID, err = repo.Create(ctx, truck)
+	require.Nil(t, err)
+
+	found, err := repo.GetById(ctx, truck.VehicleID)
+	require.Nil(t, err)
+	require.Equal(t, truck, found)
+
+	// unknown cargo type violates foreign key
+	_, err = repo.Create(ctx, entities.Vehicle{Name: "Tanker", Capacity: decimal.MustParse("1"), CargoTypes: []string{"water"}, Status: entities.VehicleAvailable})
+	require.NotNil(t, err)
+
+	vehicles, err := repo.List(ctx)
+	require.Nil(t, err)
+	require.Equal(t, []entities.Vehicle{truck}, vehicles)
+
+	truck.Status = entities.VehicleMaintenance
+	truck.CargoTypes = []string{"ore"}
+	err = repo.Update(ctx, truck)
+	require.Nil(t, err)
+
+	found, err = repo.GetById(ctx, truck.VehicleID)
+	require.Nil(t, err)
+	require.Equal(t, truck, found)
+
+	err = repo.Update(ctx, entities.Vehicle{VehicleID: truck.VehicleID + 100, Name: "Ghost", Status: entities.VehicleAvailable})
+	require.ErrorIs(t, err, repositories.ErrNotFound)
+
+	routeID, err := routes.Register(ctx, entities.Route{RouteID: 60, RouteName: "ore route", Load: decimal.MustParse("15000"), CargoType: "ore"})
+	require.Nil(t, err)
+
+	err = routes.Assign(ctx, routeID, truck.VehicleID)
+	require.ErrorIs(t, err, repositories.ErrVehicleUnfit)
+
+	truck.Status = entities.VehicleAvailable
+	err = repo.Update(ctx, truck)
+	require.Nil(t, err)
+
+	err = routes.Assign(ctx, routeID, truck.VehicleID)
+	require.Nil(t, err)
+
+	err = repo.Delete(ctx, truck.VehicleID)
+	require.Nil(t, err)
+
+	err = repo.Delete(ctx, truck.VehicleID)
+	require.ErrorIs(t, err, repositories.ErrNotFound)
+
+	// assignment is dropped together with the vehicle
+	route, err := routes.GetById(ctx, routeID)
+	require.Nil(t, err)
+	require.Nil(t, route.VehicleID)
+}
@@ -0,0 +1,52 @@
+package sqlite
+
+import (
+	"encoding/json"
+	"task/internal/entities"
+)
+
+// waypointRecord is stored in JSON array, the same way as in PostgreSQL.
+type waypointRecord struct {
+	Lat      float64 `json:"lat"`
+	Lon      float64 `json:"lon"`
+	StopName string  `json:"stop_name,omitempty"`
+	StopType string  `json:"stop_type,omitempty"`
+}
+
+func waypointsArg(waypoints []entities.Waypoint) (string, error) {
+	records := make([]waypointRecord, 0, len(waypoints))
+	for _, wp := range waypoints {
+		records = append(records, waypointRecord(wp))
+	}
+
+	data, err := json.Marshal(records)
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
+
+// waypointsColumn scans no waypoints as nil.
+type waypointsColumn []entities.Waypoint
+
+func (c *waypointsColumn) Scan(src any) error {
+	var records []waypointRecord
+	err := jsonColumn[[]waypointRecord]{v: &records}.Scan(src)
+	if err != nil {
+		return err
+	}
+
+	if len(records) == 0 {
+		*c = nil
+		return nil
+	}
+
+	waypoints := make([]entities.Waypoint, 0, len(records))
+	for _, rec := range records {
+		waypoints = append(waypoints, entities.Waypoint(rec))
+	}
+	*c = waypoints
+
+	return nil
+}
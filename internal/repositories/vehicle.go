@@ -0,0 +1,219 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"task/internal/entities"
+)
+
+//go:generate mockgen -source=vehicle.go -destination=../mocks/vehicle.go -package=mocks
+type VehicleRepo interface {
+	// Create inserts vehicle and returns id assigned to it.
+	Create(ctx context.Context, vehicle entities.Vehicle) (int, error)
+	GetById(ctx context.Context, id int) (entities.Vehicle, error)
+	List(ctx context.Context) ([]entities.Vehicle, error)
+	Update(ctx context.Context, vehicle entities.Vehicle) error
+	Delete(ctx context.Context, id int) error
+}
+
+type vehicleRepo struct {
+	db Querier
+}
+
+func NewVehicleRepo(db Querier) VehicleRepo {
+	return &vehicleRepo{
+		db: db,
+	}
+}
+
+const selectVehicles = `select
+		v.vehicle_id,
+		v.name,
+		v.capacity,
+		v.status,
+		coalesce(array_agg(c.code order by c.code) filter (where c.code is not null), '{}')
+	from vehicles v
+		left join vehicle_cargo_types c on c.vehicle_id = v.vehicle_id`
+
+func (r *vehicleRepo) Create(ctx context.Context, vehicle entities.Vehicle) (id int, err error) {
+	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return 0, fmt.Errorf("begin transaction: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			rollbackErr := tx.Rollback(ctx)
+			if rollbackErr != nil {
+				err = fmt.Errorf("rollback err: %w; handled err: %v", rollbackErr, err)
+			}
+		}
+	}()
+
+	err = tx.QueryRow(
+		ctx,
+		`insert into vehicles(name, capacity, status)
+			values($1, $2, $3)
+			returning vehicle_id`,
+		vehicle.Name,
+		decimalArg(vehicle.Capacity),
+		vehicle.Status,
+	).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("inserting vehicle: %w", err)
+	}
+	vehicle.VehicleID = id
+
+	err = insertVehicleCargoTypes(ctx, tx, vehicle)
+	if err != nil {
+		return 0, err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return id, nil
+}
+
+func (r *vehicleRepo) GetById(ctx context.Context, id int) (vehicle entities.Vehicle, err error) {
+	err = r.db.QueryRow(
+		ctx,
+		selectVehicles+`
+			where v.vehicle_id = $1
+			group by v.vehicle_id`,
+		id,
+	).Scan(
+		&vehicle.VehicleID,
+		&vehicle.Name,
+		(*decimalColumn)(&vehicle.Capacity),
+		&vehicle.Status,
+		&vehicle.CargoTypes,
+	)
+	if err != nil {
+		return entities.Vehicle{}, fmt.Errorf("getting vehicle by id: %w", err)
+	}
+
+	return vehicle, nil
+}
+
+func (r *vehicleRepo) List(ctx context.Context) (vehicles []entities.Vehicle, err error) {
+	rows, err := r.db.Query(
+		ctx,
+		selectVehicles+`
+			group by v.vehicle_id
+			order by v.vehicle_id`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("listing vehicles: %w", err)
+	}
+	defer rows.Close()
+
+	vehicles = make([]entities.Vehicle, 0)
+	for rows.Next() {
+		var vehicle entities.Vehicle
+		err = rows.Scan(
+			&vehicle.VehicleID,
+			&vehicle.Name,
+			(*decimalColumn)(&vehicle.Capacity),
+			&vehicle.Status,
+			&vehicle.CargoTypes,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scanning vehicle: %w", err)
+		}
+		vehicles = append(vehicles, vehicle)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("listing vehicles: %w", err)
+	}
+
+	return vehicles, nil
+}
+
+// Update replaces attributes and allowed cargo types of existing vehicle.
+func (r *vehicleRepo) Update(ctx context.Context, vehicle entities.Vehicle) (err error) {
+	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			rollbackErr := tx.Rollback(ctx)
+			if rollbackErr != nil {
+				err = fmt.Errorf("rollback err: %w; handled err: %v", rollbackErr, err)
+			}
+		}
+	}()
+
+	tag, err := tx.Exec(
+		ctx,
+		`update vehicles set
+				name = $2,
+				capacity = $3,
+				status = $4
+			where vehicle_id = $1`,
+		vehicle.VehicleID,
+		vehicle.Name,
+		decimalArg(vehicle.Capacity),
+		vehicle.Status,
+	)
+	if err != nil {
+		return fmt.Errorf("updating vehicle: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("updating vehicle: %w", pgx.ErrNoRows)
+	}
+
+	_, err = tx.Exec(ctx, `delete from vehicle_cargo_types where vehicle_id = $1`, vehicle.VehicleID)
+	if err != nil {
+		return fmt.Errorf("deleting vehicle cargo types: %w", err)
+	}
+
+	err = insertVehicleCargoTypes(ctx, tx, vehicle)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func insertVehicleCargoTypes(ctx context.Context, tx pgx.Tx, vehicle entities.Vehicle) (err error) {
+	if len(vehicle.CargoTypes) == 0 {
+		return nil
+	}
+
+	_, err = tx.Exec(
+		ctx,
+		`insert into vehicle_cargo_types(vehicle_id, code)
+			select $1, unnest($2::varchar[])`,
+		vehicle.VehicleID,
+		vehicle.CargoTypes,
+	)
+	if err != nil {
+		return fmt.Errorf("inserting vehicle cargo types: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes vehicle. Routes it was assigned to are left without vehicle.
+func (r *vehicleRepo) Delete(ctx context.Context, id int) (err error) {
+	tag, err := r.db.Exec(ctx, `delete from vehicles where vehicle_id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("deleting vehicle: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("deleting vehicle: %w", pgx.ErrNoRows)
+	}
+
+	return nil
+}
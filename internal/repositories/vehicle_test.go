@@ -0,0 +1,93 @@
+package repositories
+
+import (
+	"context"
+	"github.com/stretchr/testify/require"
+	"task/internal/entities"
+	"task/pkg/decimal"
+	"testing"
+)
+
+func TestVehicles(t *testing.T) {
+	repo := NewVehicleRepo(testDbInstance)
+	routes := NewRouteRepo(testDbInstance)
+	ctx := context.Background()
+
+	err := NewCargoTypeRepo(testDbInstance).Create(ctx, entities.CargoType{Code: "ore", DisplayName: "Ore", Unit: entities.UnitTonne})
+	require.Nil(t, err)
+
+	truck := entities.Vehicle{
+		Name:       "Truck",
+		Capacity:   decimal.MustParse("20000.5"),
+		CargoTypes: []string{"ore", "sand"},
+		Status:     entities.VehicleAvailable,
+	}
+
+	truck.VehicleID, err = repo.Create(ctx, truck)
+	require.Nil(t, err)
+
+	found, err := repo.GetById(ctx, truck.VehicleID)
+	require.Nil(t, err)
+	require.Equal(t, truck, found)
+
+	_, err = repo.Create(ctx, entities.Vehicle{Name: "Tanker", Capacity: decimal.MustParse("1"), CargoTypes: []string{"water"}, Status: entities.VehicleAvailable})
+	require.NotNil(t, err)
+
+	vehicles, err := repo.List(ctx)
+	require.Nil(t, err)
+	require.Equal(t, []entities.Vehicle{truck}, vehicles)
+
+	truck.Status = entities.VehicleMaintenance
+	truck.CargoTypes = []string{"ore"}
+	err = repo.Update(ctx, truck)
+	require.Nil(t, err)
+
+	found, err = repo.GetById(ctx, truck.VehicleID)
+	require.Nil(t, err)
+	require.Equal(t, truck, found)
+
+	err = repo.Update(ctx, entities.Vehicle{VehicleID: truck.VehicleID + 100, Name: "Ghost", Status: entities.VehicleAvailable})
+	require.ErrorIs(t, err, ErrNotFound)
+
+	routeID, err := routes.Register(ctx, entities.Route{RouteID: 60, RouteName: "ore route", Load: decimal.MustParse("15000"), CargoType: "ore"})
+	require.Nil(t, err)
+
+	err = routes.Assign(ctx, routeID, truck.VehicleID)
+	require.ErrorIs(t, err, ErrVehicleUnfit)
+
+	truck.Status = entities.VehicleAvailable
+	err = repo.Update(ctx, truck)
+	require.Nil(t, err)
+
+	err = routes.Assign(ctx, routeID, truck.VehicleID)
+	require.Nil(t, err)
+
+	// route 2 is not actual
+	err = routes.Assign(ctx, 2, truck.VehicleID)
+	require.ErrorIs(t, err, ErrNotFound)
+
+	heavyID, err := routes.Register(ctx, entities.Route{RouteID: 61, RouteName: "heavy ore route", Load: decimal.MustParse("20000.6"), CargoType: "ore"})
+	require.Nil(t, err)
+	err = routes.Assign(ctx, heavyID, truck.VehicleID)
+	require.ErrorIs(t, err, ErrVehicleUnfit)
+
+	route, err := routes.GetById(ctx, routeID)
+	require.Nil(t, err)
+	require.Equal(t, &truck.VehicleID, route.VehicleID)
+
+	assigned, err := routes.List(ctx, entities.RouteFilter{VehicleID: &truck.VehicleID})
+	require.Nil(t, err)
+	require.Len(t, assigned, 1)
+	require.Equal(t, routeID, assigned[0].RouteID)
+
+	err = repo.Delete(ctx, truck.VehicleID)
+	require.Nil(t, err)
+
+	err = repo.Delete(ctx, truck.VehicleID)
+	require.ErrorIs(t, err, ErrNotFound)
+
+	// assignment is dropped together with the vehicle
+	route, err = routes.GetById(ctx, routeID)
+	require.Nil(t, err)
+	require.Nil(t, route.VehicleID)
+}
@@ -0,0 +1,56 @@
+package repositories
+
+import (
+	"encoding/json"
+	"task/internal/entities"
+)
+
+// waypointsColumn is stored in jsonb column as array of waypointRecord.
+type waypointsColumn []entities.Waypoint
+
+type waypointRecord struct {
+	Lat      float64 `json:"lat"`
+	Lon      float64 `json:"lon"`
+	StopName string  `json:"stop_name,omitempty"`
+	StopType string  `json:"stop_type,omitempty"`
+}
+
+// waypointsArg converts waypoints to query argument. Nil slice would be sent as NULL,
+// so it is replaced with empty one.
+func waypointsArg(waypoints []entities.Waypoint) waypointsColumn {
+	if waypoints == nil {
+		return waypointsColumn{}
+	}
+
+	return waypoints
+}
+
+func (c waypointsColumn) MarshalJSON() ([]byte, error) {
+	records := make([]waypointRecord, 0, len(c))
+	for _, wp := range c {
+		records = append(records, waypointRecord(wp))
+	}
+
+	return json.Marshal(records)
+}
+
+func (c *waypointsColumn) UnmarshalJSON(data []byte) error {
+	var records []waypointRecord
+	err := json.Unmarshal(data, &records)
+	if err != nil {
+		return err
+	}
+
+	if len(records) == 0 {
+		*c = nil
+		return nil
+	}
+
+	waypoints := make([]entities.Waypoint, 0, len(records))
+	for _, rec := range records {
+		waypoints = append(waypoints, entities.Waypoint(rec))
+	}
+	*c = waypoints
+
+	return nil
+}
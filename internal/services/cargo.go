@@ -0,0 +1,201 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"task/internal/dto"
+	"task/internal/entities"
+	"task/internal/repositories"
+	"task/pkg/decimal"
+)
+
+type CargoService interface {
+	Create(ctx context.Context, data dto.CargoTypeRequestBody) (entities.CargoType, error)
+	GetByCode(ctx context.Context, code string) (entities.CargoType, error)
+	List(ctx context.Context) ([]entities.CargoType, error)
+	Update(ctx context.Context, code string, data dto.UpdateCargoTypeRequestBody) (entities.CargoType, error)
+	Delete(ctx context.Context, code string) error
+}
+
+type cargoService struct {
+	repo repositories.CargoTypeRepo
+}
+
+func NewCargoService(repo repositories.CargoTypeRepo) CargoService {
+	return &cargoService{
+		repo: repo,
+	}
+}
+
+func (s *cargoService) Create(ctx context.Context, data dto.CargoTypeRequestBody) (cargoType entities.CargoType, err error) {
+	cargoType, err = dto.ToCargoTypeModel(data)
+	if err != nil {
+		return entities.CargoType{}, validationError(fmt.Errorf("converting dto to cargo type: %w", err))
+	}
+
+	err = s.checkNames(ctx, cargoType)
+	if err != nil {
+		return entities.CargoType{}, err
+	}
+
+	err = s.repo.Create(ctx, cargoType)
+	if err != nil {
+		if repositories.IsUniqueViolation(err) {
+			return entities.CargoType{}, conflictError(fmt.Errorf("creating cargo type: %w", err))
+		}
+		return entities.CargoType{}, fmt.Errorf("creating cargo type: %w", err)
+	}
+
+	return cargoType, nil
+}
+
+func (s *cargoService) GetByCode(ctx context.Context, code string) (cargoType entities.CargoType, err error) {
+	cargoType, err = s.repo.GetByCode(ctx, dto.NormalizeCargoCode(code))
+	if err != nil {
+		return entities.CargoType{}, repoError(fmt.Errorf("getting cargo type: %w", err))
+	}
+
+	return cargoType, nil
+}
+
+func (s *cargoService) List(ctx context.Context) (cargoTypes []entities.CargoType, err error) {
+	cargoTypes, err = s.repo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing cargo types: %w", err)
+	}
+
+	return cargoTypes, nil
+}
+
+// Update changes supplied fields of cargo type. Code of cargo type can not be changed.
+func (s *cargoService) Update(ctx context.Context, code string, data dto.UpdateCargoTypeRequestBody) (cargoType entities.CargoType, err error) {
+	existing, err := s.repo.GetByCode(ctx, dto.NormalizeCargoCode(code))
+	if err != nil {
+		return entities.CargoType{}, repoError(fmt.Errorf("getting cargo type: %w", err))
+	}
+
+	cargoType, err = dto.ToCargoTypeModel(dto.MergeCargoTypeUpdate(existing, data))
+	if err != nil {
+		return entities.CargoType{}, validationError(fmt.Errorf("converting dto to cargo type: %w", err))
+	}
+
+	err = s.checkNames(ctx, cargoType)
+	if err != nil {
+		return entities.CargoType{}, err
+	}
+
+	err = s.repo.Update(ctx, cargoType)
+	if err != nil {
+		if repositories.IsUniqueViolation(err) {
+			return entities.CargoType{}, conflictError(fmt.Errorf("updating cargo type: %w", err))
+		}
+		return entities.CargoType{}, repoError(fmt.Errorf("updating cargo type: %w", err))
+	}
+
+	return cargoType, nil
+}
+
+// Delete removes cargo type from catalog unless it is used by actual routes.
+func (s *cargoService) Delete(ctx context.Context, code string) (err error) {
+	code = dto.NormalizeCargoCode(code)
+
+	inUse, err := s.repo.InUse(ctx, code)
+	if err != nil {
+		return fmt.Errorf("deleting cargo type: %w", err)
+	}
+	if inUse {
+		return conflictError(fmt.Errorf("cargo type %q is used by actual routes", code))
+	}
+
+	err = s.repo.Delete(ctx, code)
+	if err != nil {
+		return repoError(fmt.Errorf("deleting cargo type: %w", err))
+	}
+
+	return nil
+}
+
+// checkNames makes sure that code and aliases of cargo type do not clash with names of other types,
+// so every name resolves to a single code.
+func (s *cargoService) checkNames(ctx context.Context, cargoType entities.CargoType) (err error) {
+	cargoTypes, err := s.repo.List(ctx)
+	if err != nil {
+		return fmt.Errorf("listing cargo types: %w", err)
+	}
+
+	catalog := newCargoCatalog(cargoTypes)
+	for _, name := range append([]string{cargoType.Code}, cargoType.Aliases...) {
+		if code, ok := catalog.names[name]; ok && code != cargoType.Code {
+			return conflictError(fmt.Errorf("name %q is already used by cargo type %q", name, code))
+		}
+	}
+
+	return nil
+}
+
+// cargoCatalog resolves normalized codes and aliases to cargo types.
+type cargoCatalog struct {
+	names map[string]string
+	types map[string]entities.CargoType
+}
+
+func newCargoCatalog(cargoTypes []entities.CargoType) cargoCatalog {
+	catalog := cargoCatalog{
+		names: make(map[string]string, len(cargoTypes)),
+		types: make(map[string]entities.CargoType, len(cargoTypes)),
+	}
+	for _, cargoType := range cargoTypes {
+		catalog.types[cargoType.Code] = cargoType
+		for _, alias := range cargoType.Aliases {
+			catalog.names[alias] = cargoType.Code
+		}
+	}
+	// codes take precedence over aliases
+	for _, cargoType := range cargoTypes {
+		catalog.names[cargoType.Code] = cargoType.Code
+	}
+
+	return catalog
+}
+
+func (c cargoCatalog) resolve(name string) (cargoType entities.CargoType, ok bool) {
+	code, ok := c.names[dto.NormalizeCargoCode(name)]
+	if !ok {
+		return entities.CargoType{}, false
+	}
+
+	return c.types[code], true
+}
+
+func (s *routeService) loadCargoCatalog(ctx context.Context) (catalog cargoCatalog, err error) {
+	cargoTypes, err := s.cargo.List(ctx)
+	if err != nil {
+		return cargoCatalog{}, fmt.Errorf("loading cargo catalog: %w", err)
+	}
+
+	return newCargoCatalog(cargoTypes), nil
+}
+
+// ConvertLoad returns load of the route measured in unit, using cargo catalog for
+// conversions between volume and mass.
+func (s *routeService) ConvertLoad(ctx context.Context, route entities.Route, unit string) (load decimal.Decimal, err error) {
+	if unit == entities.CanonicalUnit {
+		return route.Load, nil
+	}
+
+	cargoType, err := s.cargo.GetByCode(ctx, route.CargoType)
+	if errors.Is(err, repositories.ErrNotFound) {
+		// cargo type without catalog entry still allows conversions between mass units
+		cargoType = entities.CargoType{Code: route.CargoType}
+	} else if err != nil {
+		return decimal.Decimal{}, fmt.Errorf("getting cargo type: %w", err)
+	}
+
+	load, err = cargoType.FromCanonical(route.Load, unit, s.loadPrecision)
+	if err != nil {
+		return decimal.Decimal{}, validationError(fmt.Errorf("converting load: %w", err))
+	}
+
+	return load, nil
+}
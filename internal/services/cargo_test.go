@@ -0,0 +1,351 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"task/internal/dto"
+	"task/internal/entities"
+	"task/internal/mocks"
+	"task/internal/repositories"
+	"task/pkg/decimal"
+	"testing"
+)
+
+func TestCreateCargoType(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cargo := mocks.NewMockCargoTypeRepo(ctrl)
+	svc := NewCargoService(cargo)
+
+	testCases := []struct {
+		name       string
+		data       dto.CargoTypeRequestBody
+		expected   entities.CargoType
+		beforeTest func(cargo mocks.MockCargoTypeRepo)
+		wantErr    bool
+		kind       error
+		err        error
+	}{
+		{
+			name: "success",
+			data: dto.CargoTypeRequestBody{
+				Code:        " Petrol ",
+				DisplayName: "Petrol",
+				Unit:        entities.UnitLitre,
+				Density:     750,
+				HazardClass: "3",
+				Aliases:     []string{"Gasoline"},
+			},
+			beforeTest: func(cargo mocks.MockCargoTypeRepo) {
+				cargo.EXPECT().List(gomock.Any()).Return(testCargoTypes, nil)
+				cargo.EXPECT().Create(gomock.Any(), entities.CargoType{
+					Code:        "petrol",
+					DisplayName: "Petrol",
+					Unit:        entities.UnitLitre,
+					Density:     750,
+					HazardClass: "3",
+					Aliases:     []string{"gasoline"},
+				}).Return(nil)
+			},
+			expected: entities.CargoType{
+				Code:        "petrol",
+				DisplayName: "Petrol",
+				Unit:        entities.UnitLitre,
+				Density:     750,
+				HazardClass: "3",
+				Aliases:     []string{"gasoline"},
+			},
+		},
+		{
+			name:    "unknown unit",
+			data:    dto.CargoTypeRequestBody{Code: "petrol", DisplayName: "Petrol", Unit: "gallon"},
+			wantErr: true,
+			kind:    ErrValidation,
+			err:     fmt.Errorf(`converting dto to cargo type: unknown unit "gallon"`),
+		},
+		{
+			name:    "unknown hazard class",
+			data:    dto.CargoTypeRequestBody{Code: "petrol", DisplayName: "Petrol", Unit: entities.UnitLitre, Density: 750, HazardClass: "10"},
+			wantErr: true,
+			kind:    ErrValidation,
+			err:     fmt.Errorf(`converting dto to cargo type: unknown hazard class "10"`),
+		},
+		{
+			name:    "duplicated alias",
+			data:    dto.CargoTypeRequestBody{Code: "petrol", DisplayName: "Petrol", Unit: entities.UnitLitre, Density: 750, Aliases: []string{"Petrol"}},
+			wantErr: true,
+			kind:    ErrValidation,
+			err:     fmt.Errorf(`converting dto to cargo type: alias "petrol" is duplicated`),
+		},
+		{
+			name: "alias is used by other cargo type",
+			data: dto.CargoTypeRequestBody{Code: "sandstone", DisplayName: "Sandstone", Unit: entities.UnitTonne, Aliases: []string{"quartz sand"}},
+			beforeTest: func(cargo mocks.MockCargoTypeRepo) {
+				cargo.EXPECT().List(gomock.Any()).Return(testCargoTypes, nil)
+			},
+			wantErr: true,
+			kind:    ErrConflict,
+			err:     fmt.Errorf(`name "quartz sand" is already used by cargo type "sand"`),
+		},
+		{
+			name: "code is taken",
+			data: dto.CargoTypeRequestBody{Code: "petrol", DisplayName: "Petrol", Unit: entities.UnitLitre, Density: 750},
+			beforeTest: func(cargo mocks.MockCargoTypeRepo) {
+				cargo.EXPECT().List(gomock.Any()).Return(testCargoTypes, nil)
+				cargo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(&pgconn.PgError{Severity: "ERROR", Code: "23505", Message: "duplicate key"})
+			},
+			wantErr: true,
+			kind:    ErrConflict,
+			err:     fmt.Errorf("creating cargo type: ERROR: duplicate key (SQLSTATE 23505)"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.beforeTest != nil {
+				tc.beforeTest(*cargo)
+			}
+
+			cargoType, err := svc.Create(context.Background(), tc.data)
+
+			if tc.wantErr {
+				require.Equal(t, tc.err.Error(), err.Error())
+				require.ErrorIs(t, err, tc.kind)
+			} else {
+				require.Nil(t, err)
+				require.Equal(t, tc.expected, cargoType)
+			}
+		})
+	}
+}
+
+func TestUpdateCargoType(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cargo := mocks.NewMockCargoTypeRepo(ctrl)
+	svc := NewCargoService(cargo)
+
+	hazardClass := "9"
+	aliases := []string{"Beach Sand"}
+
+	testCases := []struct {
+		name       string
+		code       string
+		data       dto.UpdateCargoTypeRequestBody
+		expected   entities.CargoType
+		beforeTest func(cargo mocks.MockCargoTypeRepo)
+		wantErr    bool
+		kind       error
+		err        error
+	}{
+		{
+			name: "success",
+			code: "SAND",
+			data: dto.UpdateCargoTypeRequestBody{HazardClass: &hazardClass, Aliases: &aliases},
+			beforeTest: func(cargo mocks.MockCargoTypeRepo) {
+				cargo.EXPECT().GetByCode(gomock.Any(), "sand").Return(testCargoTypes[1], nil)
+				cargo.EXPECT().List(gomock.Any()).Return(testCargoTypes, nil)
+				cargo.EXPECT().Update(gomock.Any(), entities.CargoType{
+					Code:        "sand",
+					DisplayName: "Sand",
+					Unit:        entities.UnitKilogram,
+					HazardClass: "9",
+					Aliases:     []string{"beach sand"},
+				}).Return(nil)
+			},
+			expected: entities.CargoType{
+				Code:        "sand",
+				DisplayName: "Sand",
+				Unit:        entities.UnitKilogram,
+				HazardClass: "9",
+				Aliases:     []string{"beach sand"},
+			},
+		},
+		{
+			name: "cargo type not found",
+			code: "water",
+			beforeTest: func(cargo mocks.MockCargoTypeRepo) {
+				cargo.EXPECT().GetByCode(gomock.Any(), "water").Return(entities.CargoType{}, fmt.Errorf("getting cargo type by code: %w", repositories.ErrNotFound))
+			},
+			wantErr: true,
+			kind:    ErrNotFound,
+			err:     fmt.Errorf("getting cargo type: getting cargo type by code: no rows in result set"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.beforeTest != nil {
+				tc.beforeTest(*cargo)
+			}
+
+			cargoType, err := svc.Update(context.Background(), tc.code, tc.data)
+
+			if tc.wantErr {
+				require.Equal(t, tc.err.Error(), err.Error())
+				require.ErrorIs(t, err, tc.kind)
+			} else {
+				require.Nil(t, err)
+				require.Equal(t, tc.expected, cargoType)
+			}
+		})
+	}
+}
+
+func TestDeleteCargoType(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cargo := mocks.NewMockCargoTypeRepo(ctrl)
+	svc := NewCargoService(cargo)
+
+	testCases := []struct {
+		name       string
+		code       string
+		beforeTest func(cargo mocks.MockCargoTypeRepo)
+		wantErr    bool
+		kind       error
+		err        error
+	}{
+		{
+			name: "success",
+			code: "gravel",
+			beforeTest: func(cargo mocks.MockCargoTypeRepo) {
+				cargo.EXPECT().InUse(gomock.Any(), "gravel").Return(false, nil)
+				cargo.EXPECT().Delete(gomock.Any(), "gravel").Return(nil)
+			},
+		},
+		{
+			name: "cargo type is in use",
+			code: "sand",
+			beforeTest: func(cargo mocks.MockCargoTypeRepo) {
+				cargo.EXPECT().InUse(gomock.Any(), "sand").Return(true, nil)
+			},
+			wantErr: true,
+			kind:    ErrConflict,
+			err:     fmt.Errorf(`cargo type "sand" is used by actual routes`),
+		},
+		{
+			name: "cargo type not found",
+			code: "water",
+			beforeTest: func(cargo mocks.MockCargoTypeRepo) {
+				cargo.EXPECT().InUse(gomock.Any(), "water").Return(false, nil)
+				cargo.EXPECT().Delete(gomock.Any(), "water").Return(fmt.Errorf("deleting cargo type: %w", repositories.ErrNotFound))
+			},
+			wantErr: true,
+			kind:    ErrNotFound,
+			err:     fmt.Errorf("deleting cargo type: deleting cargo type: no rows in result set"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.beforeTest != nil {
+				tc.beforeTest(*cargo)
+			}
+
+			err := svc.Delete(context.Background(), tc.code)
+
+			if tc.wantErr {
+				require.Equal(t, tc.err.Error(), err.Error())
+				require.ErrorIs(t, err, tc.kind)
+			} else {
+				require.Nil(t, err)
+			}
+		})
+	}
+}
+
+func TestConvertLoad(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cargo := mocks.NewMockCargoTypeRepo(ctrl)
+	svc := NewRouteService(mocks.NewMockRouteRepo(ctrl), mocks.NewMockJobRepo(ctrl), cargo, mocks.NewMockVehicleRepo(ctrl))
+
+	testCases := []struct {
+		name       string
+		route      entities.Route
+		unit       string
+		expected   decimal.Decimal
+		beforeTest func(cargo mocks.MockCargoTypeRepo)
+		wantErr    bool
+		kind       error
+		err        error
+	}{
+		{
+			name:     "canonical unit",
+			route:    entities.Route{Load: decimal.MustParse("1500"), CargoType: "sand"},
+			unit:     entities.UnitKilogram,
+			expected: decimal.MustParse("1500"),
+		},
+		{
+			name:  "tonnes",
+			route: entities.Route{Load: decimal.MustParse("1500"), CargoType: "sand"},
+			unit:  entities.UnitTonne,
+			beforeTest: func(cargo mocks.MockCargoTypeRepo) {
+				cargo.EXPECT().GetByCode(gomock.Any(), "sand").Return(testCargoTypes[1], nil)
+			},
+			expected: decimal.MustParse("1.5"),
+		},
+		{
+			name:  "litres",
+			route: entities.Route{Load: decimal.MustParse("840"), CargoType: "diesel"},
+			unit:  entities.UnitLitre,
+			beforeTest: func(cargo mocks.MockCargoTypeRepo) {
+				cargo.EXPECT().GetByCode(gomock.Any(), "diesel").Return(testCargoTypes[2], nil)
+			},
+			expected: decimal.MustParse("1000"),
+		},
+		{
+			name:  "cargo type missing in catalog",
+			route: entities.Route{Load: decimal.MustParse("1500"), CargoType: "water"},
+			unit:  entities.UnitTonne,
+			beforeTest: func(cargo mocks.MockCargoTypeRepo) {
+				cargo.EXPECT().GetByCode(gomock.Any(), "water").Return(entities.CargoType{}, fmt.Errorf("getting cargo type by code: %w", repositories.ErrNotFound))
+			},
+			expected: decimal.MustParse("1.5"),
+		},
+		{
+			name:  "volume of cargo without density",
+			route: entities.Route{Load: decimal.MustParse("1500"), CargoType: "bitumen"},
+			unit:  entities.UnitCubicMetre,
+			beforeTest: func(cargo mocks.MockCargoTypeRepo) {
+				cargo.EXPECT().GetByCode(gomock.Any(), "bitumen").Return(testCargoTypes[3], nil)
+			},
+			wantErr: true,
+			kind:    ErrValidation,
+			err:     fmt.Errorf(`converting load: cargo type "bitumen" has no density to convert m3 to mass`),
+		},
+		{
+			name:  "unknown unit",
+			route: entities.Route{Load: decimal.MustParse("1500"), CargoType: "sand"},
+			unit:  "lb",
+			beforeTest: func(cargo mocks.MockCargoTypeRepo) {
+				cargo.EXPECT().GetByCode(gomock.Any(), "sand").Return(testCargoTypes[1], nil)
+			},
+			wantErr: true,
+			kind:    ErrValidation,
+			err:     fmt.Errorf(`converting load: unknown unit "lb"`),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.beforeTest != nil {
+				tc.beforeTest(*cargo)
+			}
+
+			load, err := svc.ConvertLoad(context.Background(), tc.route, tc.unit)
+
+			if tc.wantErr {
+				require.Equal(t, tc.err.Error(), err.Error())
+				require.ErrorIs(t, err, tc.kind)
+			} else {
+				require.Nil(t, err)
+				require.Equal(t, tc.expected, load)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"task/internal/dto"
+	"task/internal/entities"
+	"task/pkg/decimal"
+	"time"
+)
+
+// DefaultSpeed is average speed in km/h used for cargo types without configured speed.
+const DefaultSpeed = 60.0
+
+// SpeedConfig holds average speeds in km/h used to estimate route duration.
+type SpeedConfig struct {
+	Default float64
+	ByCargo map[string]float64
+}
+
+func (c SpeedConfig) speed(cargoType string) float64 {
+	if speed, ok := c.ByCargo[cargoType]; ok && speed > 0 {
+		return speed
+	}
+	if c.Default > 0 {
+		return c.Default
+	}
+
+	return DefaultSpeed
+}
+
+// estimate returns travel time of given distance in meters rounded to seconds.
+func (c SpeedConfig) estimate(cargoType string, distance float64) time.Duration {
+	seconds := distance / 1000 / c.speed(cargoType) * 3600
+	return time.Duration(math.Round(seconds)) * time.Second
+}
+
+// toEntity validates route, resolves its cargo type, converts load to canonical unit
+// and computes distance and duration.
+func (s *routeService) toEntity(data dto.RegisterRouteRequestBody, catalog cargoCatalog) (route entities.Route, err error) {
+	route, err = dto.ToEntityModel(data)
+	if err != nil {
+		return entities.Route{}, err
+	}
+
+	return s.completeEntity(route, data.Unit, catalog)
+}
+
+// completeEntity resolves cargo type of validated route, converts its load measured in unit
+// to canonical unit and computes distance and duration.
+func (s *routeService) completeEntity(route entities.Route, unit string, catalog cargoCatalog) (entities.Route, error) {
+	cargoType, ok := catalog.resolve(route.CargoType)
+	if !ok {
+		return entities.Route{}, fmt.Errorf("unknown cargo type %q", route.CargoType)
+	}
+	route.CargoType = cargoType.Code
+
+	if unit == "" {
+		unit = cargoType.Unit
+	}
+	load, err := cargoType.ToCanonical(route.Load, unit)
+	if err != nil {
+		return entities.Route{}, err
+	}
+	route.Load = load.Round(s.loadPrecision)
+	if route.Load.IsZero() {
+		return entities.Route{}, fmt.Errorf("load should not be less than %s %s", decimal.New(1, s.loadPrecision), entities.CanonicalUnit)
+	}
+
+	route.Distance = route.Length()
+	route.Duration = s.speeds.estimate(route.CargoType, route.Distance)
+
+	return route, nil
+}
@@ -0,0 +1,62 @@
+package services
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestSpeedConfigEstimate(t *testing.T) {
+	speeds := SpeedConfig{
+		Default: 80,
+		ByCargo: map[string]float64{"sand": 40, "broken": 0},
+	}
+
+	testCases := []struct {
+		name      string
+		speeds    SpeedConfig
+		cargoType string
+		distance  float64
+		expected  time.Duration
+	}{
+		{
+			name:      "cargo speed",
+			speeds:    speeds,
+			cargoType: "sand",
+			distance:  100000,
+			expected:  time.Hour*2 + time.Minute*30,
+		},
+		{
+			name:      "default speed",
+			speeds:    speeds,
+			cargoType: "gravel",
+			distance:  100000,
+			expected:  time.Hour + time.Minute*15,
+		},
+		{
+			name:      "non-positive cargo speed falls back to default",
+			speeds:    speeds,
+			cargoType: "broken",
+			distance:  100000,
+			expected:  time.Hour + time.Minute*15,
+		},
+		{
+			name:      "empty config",
+			cargoType: "sand",
+			distance:  60000,
+			expected:  time.Hour,
+		},
+		{
+			name:      "rounded to seconds",
+			speeds:    speeds,
+			cargoType: "sand",
+			distance:  1,
+			expected:  0,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, tc.speeds.estimate(tc.cargoType, tc.distance))
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package services
+
+import (
+	"errors"
+	"task/internal/repositories"
+)
+
+// Error kinds returned by services. Check them with errors.Is.
+var (
+	ErrNotFound   = errors.New("not found")
+	ErrValidation = errors.New("validation failed")
+	ErrConflict   = errors.New("conflict")
+)
+
+// Error marks Err with one of the error kinds. Message of Err is kept as is.
+type Error struct {
+	Kind error
+	Err  error
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() []error {
+	return []error{e.Kind, e.Err}
+}
+
+func validationError(err error) error {
+	return &Error{Kind: ErrValidation, Err: err}
+}
+
+func conflictError(err error) error {
+	return &Error{Kind: ErrConflict, Err: err}
+}
+
+// repoError marks repository "not found" errors with ErrNotFound kind.
+func repoError(err error) error {
+	if errors.Is(err, repositories.ErrNotFound) {
+		return &Error{Kind: ErrNotFound, Err: err}
+	}
+
+	return err
+}
@@ -0,0 +1,79 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"task/internal/dto"
+	"task/internal/entities"
+	"task/internal/mocks"
+	"task/internal/repositories"
+	"testing"
+)
+
+func TestErrorKinds(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockRouteRepo(ctrl)
+	svc := NewRouteService(repo, mocks.NewMockJobRepo(ctrl), newTestCargoRepo(ctrl), mocks.NewMockVehicleRepo(ctrl))
+
+	newName := "renamed"
+
+	testCases := []struct {
+		name       string
+		call       func() error
+		beforeTest func(repo mocks.MockRouteRepo)
+		kind       error
+		msg        string
+	}{
+		{
+			name: "validation",
+			call: func() error {
+				_, err := svc.Register(context.Background(), dto.RegisterRouteRequestBody{RouteID: 1})
+				return err
+			},
+			kind: ErrValidation,
+			msg:  "converting dto to entity model: route name should not be empty",
+		},
+		{
+			name: "not found",
+			call: func() error {
+				_, err := svc.GetById(context.Background(), 1)
+				return err
+			},
+			beforeTest: func(repo mocks.MockRouteRepo) {
+				repo.EXPECT().
+					GetById(gomock.Any(), 1).
+					Return(entities.Route{}, fmt.Errorf("getting route by id: %w", repositories.ErrNotFound))
+			},
+			kind: ErrNotFound,
+			msg:  "getting route by id: getting route by id: no rows in result set",
+		},
+		{
+			name: "conflict",
+			call: func() error {
+				_, err := svc.Update(context.Background(), 1, dto.UpdateRouteRequestBody{RouteName: &newName})
+				return err
+			},
+			beforeTest: func(repo mocks.MockRouteRepo) {
+				repo.EXPECT().GetById(gomock.Any(), 1).Return(entities.Route{RouteID: 1, IsActual: false}, nil)
+			},
+			kind: ErrConflict,
+			msg:  "route is not actual",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.beforeTest != nil {
+				tc.beforeTest(*repo)
+			}
+
+			err := tc.call()
+
+			require.ErrorIs(t, err, tc.kind)
+			require.Equal(t, tc.msg, err.Error())
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"task/internal/dto"
+	"task/internal/entities"
+)
+
+// Import streams routes from CSV or JSONL input into repository. Invalid rows are skipped and
+// listed in the report with their line numbers; valid rows are imported atomically.
+func (s *routeService) Import(ctx context.Context, r io.Reader, format string) (report entities.ImportReport, err error) {
+	reader, err := dto.NewRouteReader(r, format)
+	if err != nil {
+		return entities.ImportReport{}, validationError(fmt.Errorf("reading import file: %w", err))
+	}
+
+	catalog, err := s.loadCargoCatalog(ctx)
+	if err != nil {
+		return entities.ImportReport{}, err
+	}
+
+	src := &validatingSource{
+		reader: reader,
+		toEntity: func(data dto.RegisterRouteRequestBody) (entities.Route, error) {
+			return s.toEntity(data, catalog)
+		},
+	}
+
+	repoReport, err := s.repo.Import(ctx, src)
+	if err != nil {
+		return entities.ImportReport{}, fmt.Errorf("importing routes: %w", err)
+	}
+
+	return entities.ImportReport{
+		Total:    src.total,
+		Imported: repoReport.Imported,
+		Reissued: repoReport.Reissued,
+		Rejected: src.rejected,
+	}, nil
+}
+
+// validatingSource reads routes from import file and skips those failing validation.
+type validatingSource struct {
+	reader   dto.RouteReader
+	toEntity func(dto.RegisterRouteRequestBody) (entities.Route, error)
+	current  entities.ImportedRoute
+	total    int
+	rejected []entities.RejectedRow
+	err      error
+}
+
+func (v *validatingSource) Next() bool {
+	for {
+		line, data, err := v.reader.Next()
+		if errors.Is(err, io.EOF) {
+			return false
+		}
+
+		var rowErr *dto.RowError
+		if errors.As(err, &rowErr) {
+			v.total++
+			v.reject(rowErr.Line, rowErr.Err)
+			continue
+		}
+		if err != nil {
+			v.err = fmt.Errorf("reading import file: %w", err)
+			return false
+		}
+
+		v.total++
+		route, err := v.toEntity(data)
+		if err != nil {
+			v.reject(line, err)
+			continue
+		}
+
+		v.current = entities.ImportedRoute{Line: line, Route: route}
+		return true
+	}
+}
+
+func (v *validatingSource) Route() entities.ImportedRoute {
+	return v.current
+}
+
+func (v *validatingSource) Err() error {
+	return v.err
+}
+
+func (v *validatingSource) reject(line int, err error) {
+	v.rejected = append(v.rejected, entities.RejectedRow{Line: line, Reason: err.Error()})
+}
@@ -0,0 +1,161 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"strings"
+	"task/internal/entities"
+	"task/internal/mocks"
+	"task/internal/repositories"
+	"task/pkg/decimal"
+	"testing"
+)
+
+func TestImport(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockRouteRepo(ctrl)
+	svc := NewRouteService(repo, mocks.NewMockJobRepo(ctrl), newTestCargoRepo(ctrl), mocks.NewMockVehicleRepo(ctrl))
+
+	// drain reads all routes from source like real repository does
+	drain := func(imported *[]entities.ImportedRoute) func(ctx context.Context, src repositories.ImportSource) (entities.ImportReport, error) {
+		return func(ctx context.Context, src repositories.ImportSource) (entities.ImportReport, error) {
+			for src.Next() {
+				*imported = append(*imported, src.Route())
+			}
+			if err := src.Err(); err != nil {
+				return entities.ImportReport{}, err
+			}
+			return entities.ImportReport{Imported: len(*imported), Reissued: 1}, nil
+		}
+	}
+
+	waypointsJSON := `[{"lat": 55.75, "lon": 37.61, "stop_name": "depot", "stop_type": "depot"}, {"lat": 59.93, "lon": 30.31}]`
+	waypointsCSV := strings.ReplaceAll(waypointsJSON, `"`, `""`)
+
+	testCases := []struct {
+		name             string
+		input            string
+		format           string
+		expectedImported []entities.ImportedRoute
+		expectedReport   entities.ImportReport
+		mockRepo         bool
+		wantErr          bool
+		err              error
+	}{
+		{
+			name:   "csv",
+			format: "csv",
+			input: "route_name,route_id,load,unit,cargo_type,waypoints\n" +
+				"first,1,10.5,,sand,\"" + waypointsCSV + "\"\n" +
+				"second,x,1,,sand,\"" + waypointsCSV + "\"\n" +
+				"third,3,-1,,sand,\"" + waypointsCSV + "\"\n" +
+				"\"fourth\nmultiline\",4,2000,kg,gravel,\"" + waypointsCSV + "\"\n" +
+				"fifth,5,2,t,gravel,\n",
+			mockRepo: true,
+			expectedImported: []entities.ImportedRoute{
+				{Line: 2, Route: entities.Route{RouteID: 1, RouteName: "first", Load: decimal.MustParse("10.5"), CargoType: "sand", Waypoints: testRouteWaypoints, Distance: testDistance, Duration: testDuration}},
+				{Line: 5, Route: entities.Route{RouteID: 4, RouteName: "fourth\nmultiline", Load: decimal.MustParse("2000"), CargoType: "gravel", Waypoints: testRouteWaypoints, Distance: testDistance, Duration: testDuration}},
+			},
+			expectedReport: entities.ImportReport{
+				Total:    5,
+				Imported: 2,
+				Reissued: 1,
+				Rejected: []entities.RejectedRow{
+					{Line: 3, Reason: `parsing route_id: strconv.Atoi: parsing "x": invalid syntax`},
+					{Line: 4, Reason: "load should be non-negative"},
+					{Line: 7, Reason: "route should have at least 2 waypoints"},
+				},
+			},
+		},
+		{
+			name:   "jsonl",
+			format: "jsonl",
+			input: `{"route_id": 1, "route_name": "first", "load": 1, "cargo_type": "sand", "waypoints": ` + waypointsJSON + `}` + "\n" +
+				"\n" +
+				`{"route_id": 2, "route_name": ""` + "\n" +
+				`{"route_id": 3, "route_name": "", "load": 1, "cargo_type": "sand"}` + "\n",
+			mockRepo: true,
+			expectedImported: []entities.ImportedRoute{
+				{Line: 1, Route: entities.Route{RouteID: 1, RouteName: "first", Load: decimal.MustParse("1"), CargoType: "sand", Waypoints: testRouteWaypoints, Distance: testDistance, Duration: testDuration}},
+			},
+			expectedReport: entities.ImportReport{
+				Total:    3,
+				Imported: 1,
+				Reissued: 1,
+				Rejected: []entities.RejectedRow{
+					{Line: 3, Reason: "unexpected end of JSON input"},
+					{Line: 4, Reason: "route name should not be empty"},
+				},
+			},
+		},
+		{
+			name:     "csv without waypoints column",
+			format:   "csv",
+			input:    "route_id,route_name,load,unit,cargo_type\n1,first,1,t,sand\n",
+			mockRepo: true,
+			expectedReport: entities.ImportReport{
+				Total:    1,
+				Reissued: 1,
+				Rejected: []entities.RejectedRow{
+					{Line: 2, Reason: "route should have at least 2 waypoints"},
+				},
+			},
+		},
+		{
+			name:   "values longer than columns",
+			format: "jsonl",
+			input: `{"route_id": 1, "route_name": "` + strings.Repeat("ы", 128) + `", "load": 1, "cargo_type": "sand", "waypoints": ` + waypointsJSON + `}` + "\n" +
+				`{"route_id": 2, "route_name": "` + strings.Repeat("x", 129) + `", "load": 1, "cargo_type": "sand", "waypoints": ` + waypointsJSON + `}` + "\n" +
+				`{"route_id": 3, "route_name": "third", "load": 1, "cargo_type": "` + strings.Repeat("x", 65) + `", "waypoints": ` + waypointsJSON + `}` + "\n",
+			mockRepo: true,
+			expectedImported: []entities.ImportedRoute{
+				{Line: 1, Route: entities.Route{RouteID: 1, RouteName: strings.Repeat("ы", 128), Load: decimal.MustParse("1"), CargoType: "sand", Waypoints: testRouteWaypoints, Distance: testDistance, Duration: testDuration}},
+			},
+			expectedReport: entities.ImportReport{
+				Total:    3,
+				Imported: 1,
+				Reissued: 1,
+				Rejected: []entities.RejectedRow{
+					{Line: 2, Reason: "route name should not be longer than 128 characters"},
+					{Line: 3, Reason: "cargo type should not be longer than 64 characters"},
+				},
+			},
+		},
+		{
+			name:    "csv without required column",
+			format:  "csv",
+			input:   "route_id,route_name,load,waypoints\n1,first,1,[]\n",
+			wantErr: true,
+			err:     fmt.Errorf(`reading import file: csv header: missing column "cargo_type"`),
+		},
+		{
+			name:    "unknown format",
+			format:  "xml",
+			wantErr: true,
+			err:     fmt.Errorf(`reading import file: unknown import format "xml"`),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var imported []entities.ImportedRoute
+			if tc.mockRepo {
+				repo.EXPECT().Import(gomock.Any(), gomock.Any()).DoAndReturn(drain(&imported))
+			}
+
+			report, err := svc.Import(context.Background(), strings.NewReader(tc.input), tc.format)
+
+			if tc.wantErr {
+				require.Equal(t, tc.err.Error(), err.Error())
+				require.ErrorIs(t, err, ErrValidation)
+			} else {
+				require.Nil(t, err)
+				require.Equal(t, tc.expectedImported, imported)
+				require.Equal(t, tc.expectedReport, report)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"task/internal/entities"
+	"task/internal/repositories"
+	"time"
+)
+
+// failureSaveTimeout limits saving failure of delete job, which happens after
+// context of the job may have already expired.
+const failureSaveTimeout = 5 * time.Second
+
+func (s *routeService) GetDeleteJob(ctx context.Context, id int64) (job entities.DeleteJob, err error) {
+	if id <= 0 {
+		return entities.DeleteJob{}, validationError(fmt.Errorf("job id should be positive"))
+	}
+
+	job, err = s.jobs.GetDeleteJob(ctx, id)
+	if err != nil {
+		return entities.DeleteJob{}, repoError(fmt.Errorf("getting delete job: %w", err))
+	}
+
+	return job, nil
+}
+
+// ResumeDeleteJobs restarts jobs which were not finished, e.g. because of server restart.
+func (s *routeService) ResumeDeleteJobs(ctx context.Context) error {
+	jobs, err := s.jobs.ListUnfinishedDeleteJobs(ctx)
+	if err != nil {
+		return fmt.Errorf("listing unfinished delete jobs: %w", err)
+	}
+
+	for _, job := range jobs {
+		s.goBackground(func() {
+			s.runDeleteJob(job.JobID, job.RouteIDs())
+		})
+	}
+
+	return nil
+}
+
+// runDeleteJob deletes routes retrying transient failures and stores the outcome in the job.
+func (s *routeService) runDeleteJob(jobId int64, ids []int) {
+	// new context because after getting http response on this request original request context is cancelled
+	ctx, cancel := context.WithTimeout(s.bgCtx, s.deleteTimeout)
+	defer cancel()
+
+	err := s.deleteWithRetries(ctx, jobId, ids)
+	if err == nil {
+		return
+	}
+	log.Printf("delete job %d: %v", jobId, err)
+
+	if s.bgCtx.Err() != nil {
+		// interrupted by shutdown, job stays unfinished and will be resumed
+		return
+	}
+
+	// job context is likely expired when deletion timed out, so failure is saved with a fresh one
+	failCtx, failCancel := context.WithTimeout(context.WithoutCancel(ctx), failureSaveTimeout)
+	defer failCancel()
+
+	err = s.jobs.FailDeleteJob(failCtx, jobId, err.Error())
+	if err != nil {
+		log.Printf("delete job %d: saving failure: %v", jobId, err)
+	}
+}
+
+func (s *routeService) deleteWithRetries(ctx context.Context, jobId int64, ids []int) (err error) {
+	delay := s.deleteRetryDelay
+	for attempt := 1; ; attempt++ {
+		err = s.jobs.StartDeleteJob(ctx, jobId)
+		if err != nil {
+			return fmt.Errorf("starting delete job: %w", err)
+		}
+
+		var deletedIds []int
+		deletedIds, err = s.repo.DeleteById(ctx, ids)
+		if err == nil {
+			err = s.jobs.FinishDeleteJob(ctx, jobId, deletedIds)
+			if err != nil {
+				return fmt.Errorf("finishing delete job: %w", err)
+			}
+			return nil
+		}
+
+		if !repositories.IsTransient(err) || attempt >= s.deleteMaxAttempts {
+			return fmt.Errorf("deleting routes: %w", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("deleting routes: %w", err)
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
@@ -0,0 +1,77 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"task/internal/entities"
+	"task/internal/mocks"
+	"testing"
+)
+
+func TestGetDeleteJob(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	jobs := mocks.NewMockJobRepo(ctrl)
+	svc := NewRouteService(mocks.NewMockRouteRepo(ctrl), jobs, mocks.NewMockCargoTypeRepo(ctrl), mocks.NewMockVehicleRepo(ctrl))
+
+	job := entities.DeleteJob{
+		JobID:  1,
+		Status: entities.JobSucceeded,
+		Items: []entities.DeleteJobItem{
+			{RouteID: 1, Status: entities.DeleteDone},
+			{RouteID: 2, Status: entities.DeleteNotFound},
+		},
+	}
+
+	testCases := []struct {
+		name       string
+		id         int64
+		expected   entities.DeleteJob
+		beforeTest func(jobs mocks.MockJobRepo)
+		wantErr    bool
+		err        error
+	}{
+		{
+			name: "success",
+			id:   1,
+			beforeTest: func(jobs mocks.MockJobRepo) {
+				jobs.EXPECT().GetDeleteJob(gomock.Any(), int64(1)).Return(job, nil)
+			},
+			expected: job,
+		},
+		{
+			name:    "id is not positive",
+			id:      0,
+			wantErr: true,
+			err:     fmt.Errorf("job id should be positive"),
+		},
+		{
+			name: "error in repository",
+			id:   1,
+			beforeTest: func(jobs mocks.MockJobRepo) {
+				jobs.EXPECT().GetDeleteJob(gomock.Any(), int64(1)).Return(entities.DeleteJob{}, fmt.Errorf("some repo error"))
+			},
+			wantErr: true,
+			err:     fmt.Errorf("getting delete job: some repo error"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.beforeTest != nil {
+				tc.beforeTest(*jobs)
+			}
+
+			job, err := svc.GetDeleteJob(context.Background(), tc.id)
+
+			if tc.wantErr {
+				require.Equal(t, tc.err.Error(), err.Error())
+			} else {
+				require.Nil(t, err)
+				require.Equal(t, tc.expected, job)
+			}
+		})
+	}
+}
@@ -3,30 +3,108 @@ package services
 import (
 	"context"
 	"fmt"
+	"io"
+	"sync"
 	"task/internal/dto"
 	"task/internal/entities"
 	"task/internal/repositories"
+	"task/pkg/decimal"
 	"time"
 )
 
 type RouteService interface {
 	Register(ctx context.Context, data dto.RegisterRouteRequestBody) (int, error)
+	RegisterBatch(ctx context.Context, data dto.RegisterBatchRequestBody) ([]entities.RegisterResult, error)
+	Import(ctx context.Context, r io.Reader, format string) (entities.ImportReport, error)
 	GetById(ctx context.Context, id int) (entities.Route, error)
-	DeleteByIds(ctx context.Context, ids dto.DeleteRoutesRequestBody) error
+	ConvertLoad(ctx context.Context, route entities.Route, unit string) (decimal.Decimal, error)
+	List(ctx context.Context, req dto.ListRoutesRequest) ([]entities.Route, string, error)
+	Export(ctx context.Context, req dto.ListRoutesRequest, fn func(entities.Route) error) error
+	Update(ctx context.Context, id int, data dto.UpdateRouteRequestBody) (entities.Route, error)
+	Assign(ctx context.Context, id int, data dto.AssignVehicleRequestBody) (entities.Route, error)
+	Stats(ctx context.Context) ([]entities.RouteStats, error)
+	History(ctx context.Context, id int) ([]entities.RouteVersion, error)
+	Near(ctx context.Context, req dto.NearRequest) ([]entities.Route, error)
+	Within(ctx context.Context, req dto.WithinRequest) ([]entities.Route, error)
+	Search(ctx context.Context, req dto.SearchRoutesRequest) ([]entities.Route, error)
+	DeleteByIds(ctx context.Context, ids dto.DeleteRoutesRequestBody) (int64, error)
+	GetDeleteJob(ctx context.Context, id int64) (entities.DeleteJob, error)
+	ResumeDeleteJobs(ctx context.Context) error
+	Shutdown(ctx context.Context) error
 }
 
+// DefaultLoadPrecision is number of fractional digits of load kept in CanonicalUnit, i.e. grams.
+const DefaultLoadPrecision = 3
+
 type routeService struct {
-	repo repositories.RouteRepo
+	repo     repositories.RouteRepo
+	jobs     repositories.JobRepo
+	cargo    repositories.CargoTypeRepo
+	vehicles repositories.VehicleRepo
+
+	speeds        SpeedConfig
+	loadPrecision int32
+
+	deleteTimeout     time.Duration
+	deleteMaxAttempts int
+	deleteRetryDelay  time.Duration
+
+	// background work outlives requests, so it is bound to service lifetime instead
+	bgCtx    context.Context
+	bgCancel context.CancelFunc
+	bgWg     sync.WaitGroup
+}
+
+// Option configures route service.
+type Option func(s *routeService)
+
+// WithSpeeds sets average speeds used to estimate route duration.
+func WithSpeeds(speeds SpeedConfig) Option {
+	return func(s *routeService) {
+		s.speeds = speeds
+	}
 }
 
-func NewRouteService(repo repositories.RouteRepo) RouteService {
-	return &routeService{repo: repo}
+// WithLoadPrecision sets number of fractional digits loads are rounded to. It should not
+// exceed decimal.MaxScale.
+func WithLoadPrecision(digits int32) Option {
+	return func(s *routeService) {
+		s.loadPrecision = digits
+	}
+}
+
+func NewRouteService(repo repositories.RouteRepo, jobs repositories.JobRepo, cargo repositories.CargoTypeRepo, vehicles repositories.VehicleRepo, opts ...Option) RouteService {
+	bgCtx, bgCancel := context.WithCancel(context.Background())
+
+	s := &routeService{
+		repo:              repo,
+		jobs:              jobs,
+		cargo:             cargo,
+		vehicles:          vehicles,
+		speeds:            SpeedConfig{Default: DefaultSpeed},
+		loadPrecision:     DefaultLoadPrecision,
+		deleteTimeout:     time.Second * 60,
+		deleteMaxAttempts: 3,
+		deleteRetryDelay:  time.Millisecond * 500,
+		bgCtx:             bgCtx,
+		bgCancel:          bgCancel,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
 }
 
 func (s *routeService) Register(ctx context.Context, data dto.RegisterRouteRequestBody) (routeId int, err error) {
-	route, err := dto.ToEntityModel(data)
+	catalog, err := s.loadCargoCatalog(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	route, err := s.toEntity(data, catalog)
 	if err != nil {
-		return 0, fmt.Errorf("converting dto to entity model: %w", err)
+		return 0, validationError(fmt.Errorf("converting dto to entity model: %w", err))
 	}
 
 	routeId, err = s.repo.Register(ctx, route)
@@ -37,37 +115,253 @@ func (s *routeService) Register(ctx context.Context, data dto.RegisterRouteReque
 	return routeId, nil
 }
 
+// RegisterBatch registers routes of the batch. In atomic mode any error fails the whole batch,
+// otherwise errors are reported per route in the results.
+func (s *routeService) RegisterBatch(ctx context.Context, data dto.RegisterBatchRequestBody) (results []entities.RegisterResult, err error) {
+	if len(data.Routes) == 0 {
+		return nil, validationError(fmt.Errorf("batch should not be empty"))
+	}
+	if len(data.Routes) > dto.MaxBatchSize {
+		return nil, validationError(fmt.Errorf("batch should not contain more than %d routes", dto.MaxBatchSize))
+	}
+
+	catalog, err := s.loadCargoCatalog(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	results = make([]entities.RegisterResult, len(data.Routes))
+	routes := make([]entities.Route, len(data.Routes))
+	for i, item := range data.Routes {
+		routes[i], err = s.toEntity(item, catalog)
+		if err != nil {
+			err = validationError(fmt.Errorf("route #%d: converting dto to entity model: %w", i, err))
+			if data.Atomic {
+				return nil, err
+			}
+			results[i].Err = err
+		}
+	}
+
+	if data.Atomic {
+		routeIds, err := s.repo.RegisterBatch(ctx, routes)
+		if err != nil {
+			return nil, fmt.Errorf("batch registration: %w", err)
+		}
+
+		for i, routeId := range routeIds {
+			results[i] = entities.RegisterResult{
+				RouteID:  routeId,
+				Reissued: routeId != routes[i].RouteID,
+			}
+		}
+
+		return results, nil
+	}
+
+	for i, route := range routes {
+		if results[i].Err != nil {
+			continue
+		}
+
+		routeId, err := s.repo.Register(ctx, route)
+		if err != nil {
+			results[i].Err = fmt.Errorf("route #%d: route registration: %w", i, err)
+			continue
+		}
+
+		results[i] = entities.RegisterResult{
+			RouteID:  routeId,
+			Reissued: routeId != route.RouteID,
+		}
+	}
+
+	return results, nil
+}
+
 func (s *routeService) GetById(ctx context.Context, id int) (route entities.Route, err error) {
 	if id < 0 {
-		return entities.Route{}, fmt.Errorf("route id should be non-negative")
+		return entities.Route{}, validationError(fmt.Errorf("route id should be non-negative"))
 	}
 
 	route, err = s.repo.GetById(ctx, id)
 	if err != nil {
-		return entities.Route{}, fmt.Errorf("getting route by id: %w", err)
+		return entities.Route{}, repoError(fmt.Errorf("getting route by id: %w", err))
 	}
 
 	return route, nil
 }
 
-func (s *routeService) DeleteByIds(ctx context.Context, ids dto.DeleteRoutesRequestBody) (err error) {
+// List returns page of routes matching the request and cursor for the next page.
+// Cursor is empty when there are no more routes.
+func (s *routeService) List(ctx context.Context, req dto.ListRoutesRequest) (routes []entities.Route, nextCursor string, err error) {
+	filter, err := dto.ToRouteFilter(req)
+	if err != nil {
+		return nil, "", validationError(fmt.Errorf("converting dto to filter: %w", err))
+	}
+
+	pageSize := filter.Limit
+	filter.Limit++ // one extra row tells whether next page exists
+
+	routes, err = s.repo.List(ctx, filter)
+	if err != nil {
+		return nil, "", fmt.Errorf("listing routes: %w", err)
+	}
+
+	if len(routes) > pageSize {
+		routes = routes[:pageSize]
+		nextCursor = dto.EncodeCursor(routes[pageSize-1].RouteID)
+	}
+
+	return routes, nextCursor, nil
+}
+
+// Export calls fn for every route matching the request without loading them all into memory.
+// Zero limit of the request means all routes.
+func (s *routeService) Export(ctx context.Context, req dto.ListRoutesRequest, fn func(entities.Route) error) (err error) {
+	filter, err := dto.ToRouteFilter(req)
+	if err != nil {
+		return validationError(fmt.Errorf("converting dto to filter: %w", err))
+	}
+	filter.Limit = req.Limit
+
+	err = s.repo.Export(ctx, filter, fn)
+	if err != nil {
+		return fmt.Errorf("exporting routes: %w", err)
+	}
+
+	return nil
+}
+
+// Update changes supplied fields of the route in place, keeping its id.
+func (s *routeService) Update(ctx context.Context, id int, data dto.UpdateRouteRequestBody) (route entities.Route, err error) {
+	if id < 0 {
+		return entities.Route{}, validationError(fmt.Errorf("route id should be non-negative"))
+	}
+
+	existing, err := s.repo.GetById(ctx, id)
+	if err != nil {
+		return entities.Route{}, repoError(fmt.Errorf("getting route by id: %w", err))
+	}
+
+	if !existing.IsActual {
+		return entities.Route{}, conflictError(fmt.Errorf("route is not actual"))
+	}
+
+	catalog, err := s.loadCargoCatalog(ctx)
+	if err != nil {
+		return entities.Route{}, err
+	}
+
+	merged := dto.MergeUpdate(existing, data)
+	route, err = dto.ToUpdatedEntityModel(merged, data)
+	if err == nil {
+		route, err = s.completeEntity(route, merged.Unit, catalog)
+	}
+	if err != nil {
+		return entities.Route{}, validationError(fmt.Errorf("converting dto to entity model: %w", err))
+	}
+	route.IsActual = existing.IsActual
+	route.VehicleID = existing.VehicleID
+
+	err = s.checkAssignedVehicle(ctx, route)
+	if err != nil {
+		return entities.Route{}, err
+	}
+
+	err = s.repo.Update(ctx, route)
+	if err != nil {
+		return entities.Route{}, repoError(fmt.Errorf("updating route: %w", err))
+	}
+
+	return route, nil
+}
+
+// Search returns actual routes with names matching the query, the most relevant first.
+// Query may contain typos.
+func (s *routeService) Search(ctx context.Context, req dto.SearchRoutesRequest) (routes []entities.Route, err error) {
+	query, limit, err := dto.ToSearchQuery(req)
+	if err != nil {
+		return nil, validationError(fmt.Errorf("converting dto to search query: %w", err))
+	}
+
+	routes, err = s.repo.Search(ctx, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("searching routes: %w", err)
+	}
+
+	return routes, nil
+}
+
+// Stats returns load statistics of routes grouped by cargo type and actuality.
+func (s *routeService) Stats(ctx context.Context) (stats []entities.RouteStats, err error) {
+	stats, err = s.repo.Stats(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("getting route stats: %w", err)
+	}
+
+	return stats, nil
+}
+
+// History returns all versions linked with the route, from the oldest to the newest.
+func (s *routeService) History(ctx context.Context, id int) (versions []entities.RouteVersion, err error) {
+	if id < 0 {
+		return nil, validationError(fmt.Errorf("route id should be non-negative"))
+	}
+
+	versions, err = s.repo.History(ctx, id)
+	if err != nil {
+		return nil, repoError(fmt.Errorf("getting route history: %w", err))
+	}
+
+	return versions, nil
+}
+
+// DeleteByIds persists delete job and runs it in background. Returned job id can be used
+// to track job status.
+func (s *routeService) DeleteByIds(ctx context.Context, ids dto.DeleteRoutesRequestBody) (jobId int64, err error) {
 	for _, val := range ids.RouteIDs {
 		if val < 0 {
-			return fmt.Errorf("deleting routes: ids should be non-negative")
+			return 0, validationError(fmt.Errorf("deleting routes: ids should be non-negative"))
 		}
 	}
 
-	go func() {
-		// new context because after getting http response on this request original request context is cancelled
-		delCtx, cancel := context.WithTimeout(context.Background(), time.Second*60)
-		defer cancel()
+	jobId, err = s.jobs.CreateDeleteJob(ctx, ids.RouteIDs)
+	if err != nil {
+		return 0, fmt.Errorf("creating delete job: %w", err)
+	}
 
-		err = s.repo.DeleteById(delCtx, ids.RouteIDs)
-		if err != nil {
-			// TODO: replace with log msg
-			fmt.Printf("deleting routes: %v\n", err)
-		}
+	s.goBackground(func() {
+		s.runDeleteJob(jobId, ids.RouteIDs)
+	})
+
+	return jobId, nil
+}
+
+// Shutdown waits for background work to finish. If ctx expires first, background work is
+// cancelled and ctx error is returned; interrupted delete jobs are resumed on next start.
+func (s *routeService) Shutdown(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		s.bgWg.Wait()
+		close(done)
 	}()
 
-	return nil
+	select {
+	case <-done:
+		s.bgCancel()
+		return nil
+	case <-ctx.Done():
+		s.bgCancel()
+		<-done
+		return fmt.Errorf("waiting for background work: %w", ctx.Err())
+	}
+}
+
+func (s *routeService) goBackground(f func()) {
+	s.bgWg.Add(1)
+	go func() {
+		defer s.bgWg.Done()
+		f()
+	}()
 }
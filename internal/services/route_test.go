@@ -3,45 +3,114 @@ package services
 import (
 	"context"
 	"fmt"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 	"task/internal/dto"
 	"task/internal/entities"
 	"task/internal/mocks"
+	"task/pkg/decimal"
 	"testing"
 	"time"
 )
 
-const eps = 1e-6
+var (
+	testWaypoints = []dto.WaypointBody{
+		{Lat: 55.75, Lon: 37.61, StopName: "depot", StopType: entities.StopDepot},
+		{Lat: 59.93, Lon: 30.31},
+	}
+	testRouteWaypoints = []entities.Waypoint{
+		{Lat: 55.75, Lon: 37.61, StopName: "depot", StopType: entities.StopDepot},
+		{Lat: 59.93, Lon: 30.31},
+	}
+	// great-circle distance between waypoints and its duration at default speed
+	testDistance = entities.Route{Waypoints: testRouteWaypoints}.Length()
+	testDuration = 38034 * time.Second
+
+	testCargoTypes = []entities.CargoType{
+		{Code: "gravel", DisplayName: "Gravel", Unit: entities.UnitTonne},
+		{Code: "sand", DisplayName: "Sand", Unit: entities.UnitKilogram, Aliases: []string{"quartz sand"}},
+		{Code: "diesel", DisplayName: "Diesel", Unit: entities.UnitLitre, Density: 840, HazardClass: "3"},
+		{Code: "bitumen", DisplayName: "Bitumen", Unit: entities.UnitCubicMetre},
+	}
+)
+
+// newTestCargoRepo returns cargo catalog mock holding testCargoTypes.
+func newTestCargoRepo(ctrl *gomock.Controller) *mocks.MockCargoTypeRepo {
+	cargo := mocks.NewMockCargoTypeRepo(ctrl)
+	cargo.EXPECT().List(gomock.Any()).Return(testCargoTypes, nil).AnyTimes()
+
+	return cargo
+}
 
 func TestDeleteByIds(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	repo := mocks.NewMockRouteRepo(ctrl)
-	svc := NewRouteService(repo)
+	jobs := mocks.NewMockJobRepo(ctrl)
+	svc := NewRouteService(repo, jobs, mocks.NewMockCargoTypeRepo(ctrl), mocks.NewMockVehicleRepo(ctrl))
+	svc.(*routeService).deleteRetryDelay = time.Millisecond
+
+	transientErr := &pgconn.PgError{Code: "40001"}
 
 	testCases := []struct {
-		beforeTest func(repo mocks.MockRouteRepo)
+		beforeTest func(repo mocks.MockRouteRepo, jobs mocks.MockJobRepo)
 		name       string
 		ids        dto.DeleteRoutesRequestBody
+		expectedId int64
 		wantErr    bool
 		err        error
 	}{
 		{
-			name:    "success",
-			wantErr: false,
-			ids:     dto.DeleteRoutesRequestBody{RouteIDs: []int{1, 2, 3}},
-			beforeTest: func(repo mocks.MockRouteRepo) {
-				repo.EXPECT().DeleteById(gomock.Any(), []int{1, 2, 3}).Return(nil)
+			name:       "success",
+			wantErr:    false,
+			ids:        dto.DeleteRoutesRequestBody{RouteIDs: []int{1, 2, 3}},
+			expectedId: 1,
+			beforeTest: func(repo mocks.MockRouteRepo, jobs mocks.MockJobRepo) {
+				jobs.EXPECT().CreateDeleteJob(gomock.Any(), []int{1, 2, 3}).Return(int64(1), nil)
+				jobs.EXPECT().StartDeleteJob(gomock.Any(), int64(1)).Return(nil)
+				repo.EXPECT().DeleteById(gomock.Any(), []int{1, 2, 3}).Return([]int{1, 3}, nil)
+				jobs.EXPECT().FinishDeleteJob(gomock.Any(), int64(1), []int{1, 3}).Return(nil)
 			},
 		},
 		{
-			name:    "empty ids",
-			wantErr: false,
-			ids:     dto.DeleteRoutesRequestBody{RouteIDs: []int{}},
-			beforeTest: func(repo mocks.MockRouteRepo) {
-				repo.EXPECT().DeleteById(gomock.Any(), []int{}).Return(nil)
+			name:       "empty ids",
+			wantErr:    false,
+			ids:        dto.DeleteRoutesRequestBody{RouteIDs: []int{}},
+			expectedId: 2,
+			beforeTest: func(repo mocks.MockRouteRepo, jobs mocks.MockJobRepo) {
+				jobs.EXPECT().CreateDeleteJob(gomock.Any(), []int{}).Return(int64(2), nil)
+				jobs.EXPECT().StartDeleteJob(gomock.Any(), int64(2)).Return(nil)
+				repo.EXPECT().DeleteById(gomock.Any(), []int{}).Return([]int{}, nil)
+				jobs.EXPECT().FinishDeleteJob(gomock.Any(), int64(2), []int{}).Return(nil)
+			},
+		},
+		{
+			name:       "transient error is retried",
+			wantErr:    false,
+			ids:        dto.DeleteRoutesRequestBody{RouteIDs: []int{1}},
+			expectedId: 3,
+			beforeTest: func(repo mocks.MockRouteRepo, jobs mocks.MockJobRepo) {
+				jobs.EXPECT().CreateDeleteJob(gomock.Any(), []int{1}).Return(int64(3), nil)
+				jobs.EXPECT().StartDeleteJob(gomock.Any(), int64(3)).Return(nil).Times(2)
+				gomock.InOrder(
+					repo.EXPECT().DeleteById(gomock.Any(), []int{1}).Return(nil, transientErr),
+					repo.EXPECT().DeleteById(gomock.Any(), []int{1}).Return([]int{1}, nil),
+				)
+				jobs.EXPECT().FinishDeleteJob(gomock.Any(), int64(3), []int{1}).Return(nil)
+			},
+		},
+		{
+			name:       "permanent error fails job",
+			wantErr:    false,
+			ids:        dto.DeleteRoutesRequestBody{RouteIDs: []int{1}},
+			expectedId: 4,
+			beforeTest: func(repo mocks.MockRouteRepo, jobs mocks.MockJobRepo) {
+				jobs.EXPECT().CreateDeleteJob(gomock.Any(), []int{1}).Return(int64(4), nil)
+				jobs.EXPECT().StartDeleteJob(gomock.Any(), int64(4)).Return(nil)
+				repo.EXPECT().DeleteById(gomock.Any(), []int{1}).Return(nil, fmt.Errorf("some repo error"))
+				jobs.EXPECT().FailDeleteJob(gomock.Any(), int64(4), "deleting routes: some repo error").Return(nil)
 			},
 		},
 		{
@@ -50,19 +119,29 @@ func TestDeleteByIds(t *testing.T) {
 			ids:     dto.DeleteRoutesRequestBody{RouteIDs: []int{1, -2, 3}},
 			err:     fmt.Errorf("deleting routes: ids should be non-negative"),
 		},
+		{
+			name:    "error while creating job",
+			wantErr: true,
+			ids:     dto.DeleteRoutesRequestBody{RouteIDs: []int{1}},
+			beforeTest: func(repo mocks.MockRouteRepo, jobs mocks.MockJobRepo) {
+				jobs.EXPECT().CreateDeleteJob(gomock.Any(), []int{1}).Return(int64(0), fmt.Errorf("some repo error"))
+			},
+			err: fmt.Errorf("creating delete job: some repo error"),
+		},
 	}
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			if tc.beforeTest != nil {
-				tc.beforeTest(*repo)
+				tc.beforeTest(*repo, *jobs)
 			}
 
-			err := svc.DeleteByIds(context.Background(), tc.ids)
+			jobId, err := svc.DeleteByIds(context.Background(), tc.ids)
 
 			if tc.wantErr {
 				require.Equal(t, tc.err.Error(), err.Error())
 			} else {
 				require.Nil(t, err)
+				require.Equal(t, tc.expectedId, jobId)
 			}
 
 			// goroutine needs time to complete ;)
@@ -76,7 +155,7 @@ func TestGetById(t *testing.T) {
 	defer ctrl.Finish()
 
 	repo := mocks.NewMockRouteRepo(ctrl)
-	svc := NewRouteService(repo)
+	svc := NewRouteService(repo, mocks.NewMockJobRepo(ctrl), mocks.NewMockCargoTypeRepo(ctrl), mocks.NewMockVehicleRepo(ctrl))
 
 	testCases := []struct {
 		name       string
@@ -92,14 +171,14 @@ func TestGetById(t *testing.T) {
 			expected: entities.Route{
 				RouteID:   1,
 				RouteName: "test",
-				Load:      1000.0,
+				Load:      decimal.MustParse("1000"),
 				CargoType: "sand",
 			},
 			beforeTest: func(repo mocks.MockRouteRepo) {
 				repo.EXPECT().GetById(gomock.Any(), 1).Return(entities.Route{
 					RouteID:   1,
 					RouteName: "test",
-					Load:      1000.0,
+					Load:      decimal.MustParse("1000"),
 					CargoType: "sand",
 					IsActual:  false,
 				}, nil)
@@ -140,7 +219,7 @@ func TestGetById(t *testing.T) {
 				require.Nil(t, err)
 				require.Equal(t, tc.expected.RouteID, route.RouteID)
 				require.Equal(t, tc.expected.RouteName, route.RouteName)
-				require.InEpsilon(t, tc.expected.Load, route.Load, eps)
+				require.Equal(t, tc.expected.Load, route.Load)
 				require.Equal(t, tc.expected.CargoType, route.CargoType)
 			}
 		})
@@ -152,7 +231,7 @@ func TestRegister(t *testing.T) {
 	defer ctrl.Finish()
 
 	repo := mocks.NewMockRouteRepo(ctrl)
-	svc := NewRouteService(repo)
+	svc := NewRouteService(repo, mocks.NewMockJobRepo(ctrl), newTestCargoRepo(ctrl), mocks.NewMockVehicleRepo(ctrl))
 
 	testCases := []struct {
 		name            string
@@ -167,8 +246,9 @@ func TestRegister(t *testing.T) {
 			data: dto.RegisterRouteRequestBody{
 				RouteID:   1,
 				RouteName: "test",
-				Load:      1000.0,
+				Load:      decimal.MustParse("1000"),
 				CargoType: "sand",
+				Waypoints: testWaypoints,
 			},
 			beforeTest: func(repo mocks.MockRouteRepo) {
 				repo.EXPECT().
@@ -177,8 +257,11 @@ func TestRegister(t *testing.T) {
 						entities.Route{
 							RouteID:   1,
 							RouteName: "test",
-							Load:      1000.0,
+							Load:      decimal.MustParse("1000"),
 							CargoType: "sand",
+							Waypoints: testRouteWaypoints,
+							Distance:  testDistance,
+							Duration:  testDuration,
 						}).
 					Return(1, nil)
 			},
@@ -189,19 +272,249 @@ func TestRegister(t *testing.T) {
 			data: dto.RegisterRouteRequestBody{
 				RouteID:   1,
 				RouteName: "test",
-				Load:      -1000.0,
+				Load:      decimal.MustParse("-1000"),
 				CargoType: "sand",
+				Waypoints: testWaypoints,
 			},
 			wantErr: true,
 			err:     fmt.Errorf("converting dto to entity model: load should be non-negative"),
 		},
+		{
+			name: "cargo type alias",
+			data: dto.RegisterRouteRequestBody{
+				RouteID:   1,
+				RouteName: "test",
+				Load:      decimal.MustParse("1000"),
+				CargoType: " Quartz Sand",
+				Waypoints: testWaypoints,
+			},
+			beforeTest: func(repo mocks.MockRouteRepo) {
+				repo.EXPECT().
+					Register(
+						gomock.Any(),
+						entities.Route{
+							RouteID:   1,
+							RouteName: "test",
+							Load:      decimal.MustParse("1000"),
+							CargoType: "sand",
+							Waypoints: testRouteWaypoints,
+							Distance:  testDistance,
+							Duration:  testDuration,
+						}).
+					Return(1, nil)
+			},
+			expectedRouteId: 1,
+		},
+		{
+			name: "unknown cargo type",
+			data: dto.RegisterRouteRequestBody{
+				RouteID:   1,
+				RouteName: "test",
+				Load:      decimal.MustParse("1000"),
+				CargoType: "water",
+				Waypoints: testWaypoints,
+			},
+			wantErr: true,
+			err:     fmt.Errorf(`converting dto to entity model: unknown cargo type "water"`),
+		},
+		{
+			name: "default unit of cargo type",
+			data: dto.RegisterRouteRequestBody{
+				RouteID:   1,
+				RouteName: "test",
+				Load:      decimal.MustParse("2.5"),
+				CargoType: "gravel",
+				Waypoints: testWaypoints,
+			},
+			beforeTest: func(repo mocks.MockRouteRepo) {
+				repo.EXPECT().
+					Register(
+						gomock.Any(),
+						entities.Route{
+							RouteID:   1,
+							RouteName: "test",
+							Load:      decimal.MustParse("2500"),
+							CargoType: "gravel",
+							Waypoints: testRouteWaypoints,
+							Distance:  testDistance,
+							Duration:  testDuration,
+						}).
+					Return(1, nil)
+			},
+			expectedRouteId: 1,
+		},
+		{
+			name: "load in tonnes",
+			data: dto.RegisterRouteRequestBody{
+				RouteID:   1,
+				RouteName: "test",
+				Load:      decimal.MustParse("1.5"),
+				Unit:      entities.UnitTonne,
+				CargoType: "sand",
+				Waypoints: testWaypoints,
+			},
+			beforeTest: func(repo mocks.MockRouteRepo) {
+				repo.EXPECT().
+					Register(
+						gomock.Any(),
+						entities.Route{
+							RouteID:   1,
+							RouteName: "test",
+							Load:      decimal.MustParse("1500"),
+							CargoType: "sand",
+							Waypoints: testRouteWaypoints,
+							Distance:  testDistance,
+							Duration:  testDuration,
+						}).
+					Return(1, nil)
+			},
+			expectedRouteId: 1,
+		},
+		{
+			name: "load in litres",
+			data: dto.RegisterRouteRequestBody{
+				RouteID:   1,
+				RouteName: "test",
+				Load:      decimal.MustParse("1000"),
+				CargoType: "diesel",
+				Waypoints: testWaypoints,
+			},
+			beforeTest: func(repo mocks.MockRouteRepo) {
+				repo.EXPECT().
+					Register(
+						gomock.Any(),
+						entities.Route{
+							RouteID:   1,
+							RouteName: "test",
+							Load:      decimal.MustParse("840"),
+							CargoType: "diesel",
+							Waypoints: testRouteWaypoints,
+							Distance:  testDistance,
+							Duration:  testDuration,
+						}).
+					Return(1, nil)
+			},
+			expectedRouteId: 1,
+		},
+		{
+			name: "load is rounded to precision",
+			data: dto.RegisterRouteRequestBody{
+				RouteID:   1,
+				RouteName: "test",
+				Load:      decimal.MustParse("12.10045"),
+				CargoType: "sand",
+				Waypoints: testWaypoints,
+			},
+			beforeTest: func(repo mocks.MockRouteRepo) {
+				repo.EXPECT().
+					Register(
+						gomock.Any(),
+						entities.Route{
+							RouteID:   1,
+							RouteName: "test",
+							Load:      decimal.MustParse("12.1"),
+							CargoType: "sand",
+							Waypoints: testRouteWaypoints,
+							Distance:  testDistance,
+							Duration:  testDuration,
+						}).
+					Return(1, nil)
+			},
+			expectedRouteId: 1,
+		},
+		{
+			name: "load is less than precision",
+			data: dto.RegisterRouteRequestBody{
+				RouteID:   1,
+				RouteName: "test",
+				Load:      decimal.MustParse("0.0004"),
+				CargoType: "sand",
+				Waypoints: testWaypoints,
+			},
+			wantErr: true,
+			err:     fmt.Errorf("converting dto to entity model: load should not be less than 0.001 kg"),
+		},
+		{
+			name: "unknown unit",
+			data: dto.RegisterRouteRequestBody{
+				RouteID:   1,
+				RouteName: "test",
+				Load:      decimal.MustParse("1000"),
+				Unit:      "lb",
+				CargoType: "sand",
+				Waypoints: testWaypoints,
+			},
+			wantErr: true,
+			err:     fmt.Errorf(`converting dto to entity model: unknown unit "lb"`),
+		},
+		{
+			name: "volume of cargo without density",
+			data: dto.RegisterRouteRequestBody{
+				RouteID:   1,
+				RouteName: "test",
+				Load:      decimal.MustParse("10"),
+				CargoType: "bitumen",
+				Waypoints: testWaypoints,
+			},
+			wantErr: true,
+			err:     fmt.Errorf(`converting dto to entity model: cargo type "bitumen" has no density to convert m3 to mass`),
+		},
+		{
+			name: "single waypoint",
+			data: dto.RegisterRouteRequestBody{
+				RouteID:   1,
+				RouteName: "test",
+				Load:      decimal.MustParse("1000"),
+				CargoType: "sand",
+				Waypoints: testWaypoints[:1],
+			},
+			wantErr: true,
+			err:     fmt.Errorf("converting dto to entity model: route should have at least 2 waypoints"),
+		},
+		{
+			name: "latitude out of range",
+			data: dto.RegisterRouteRequestBody{
+				RouteID:   1,
+				RouteName: "test",
+				Load:      decimal.MustParse("1000"),
+				CargoType: "sand",
+				Waypoints: []dto.WaypointBody{{Lat: 10, Lon: 10}, {Lat: 91, Lon: 10}},
+			},
+			wantErr: true,
+			err:     fmt.Errorf("converting dto to entity model: waypoint #1: latitude should be in range [-90, 90]"),
+		},
+		{
+			name: "longitude out of range",
+			data: dto.RegisterRouteRequestBody{
+				RouteID:   1,
+				RouteName: "test",
+				Load:      decimal.MustParse("1000"),
+				CargoType: "sand",
+				Waypoints: []dto.WaypointBody{{Lat: 10, Lon: -180.5}, {Lat: 10, Lon: 10}},
+			},
+			wantErr: true,
+			err:     fmt.Errorf("converting dto to entity model: waypoint #0: longitude should be in range [-180, 180]"),
+		},
+		{
+			name: "unknown stop type",
+			data: dto.RegisterRouteRequestBody{
+				RouteID:   1,
+				RouteName: "test",
+				Load:      decimal.MustParse("1000"),
+				CargoType: "sand",
+				Waypoints: []dto.WaypointBody{{Lat: 10, Lon: 10, StopType: "harbour"}, {Lat: 10, Lon: 11}},
+			},
+			wantErr: true,
+			err:     fmt.Errorf(`converting dto to entity model: waypoint #0: unknown stop type "harbour"`),
+		},
 		{
 			name: "error in repository",
 			data: dto.RegisterRouteRequestBody{
 				RouteID:   1,
 				RouteName: "test",
-				Load:      1000.0,
+				Load:      decimal.MustParse("1000"),
 				CargoType: "sand",
+				Waypoints: testWaypoints,
 			},
 			beforeTest: func(repo mocks.MockRouteRepo) {
 				repo.EXPECT().
@@ -210,8 +523,11 @@ func TestRegister(t *testing.T) {
 						entities.Route{
 							RouteID:   1,
 							RouteName: "test",
-							Load:      1000.0,
+							Load:      decimal.MustParse("1000"),
 							CargoType: "sand",
+							Waypoints: testRouteWaypoints,
+							Distance:  testDistance,
+							Duration:  testDuration,
 						}).
 					Return(0, fmt.Errorf("some repo error"))
 			},
@@ -236,3 +552,772 @@ func TestRegister(t *testing.T) {
 		})
 	}
 }
+
+func TestList(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockRouteRepo(ctrl)
+	svc := NewRouteService(repo, mocks.NewMockJobRepo(ctrl), mocks.NewMockCargoTypeRepo(ctrl), mocks.NewMockVehicleRepo(ctrl))
+
+	afterID := 2
+	minLoad, maxLoad := decimal.MustParse("10"), decimal.MustParse("1")
+
+	testCases := []struct {
+		name               string
+		req                dto.ListRoutesRequest
+		expectedIDs        []int
+		expectedNextCursor string
+		beforeTest         func(repo mocks.MockRouteRepo)
+		wantErr            bool
+		err                error
+	}{
+		{
+			name: "success (has next page)",
+			req:  dto.ListRoutesRequest{Limit: 2, Cursor: dto.EncodeCursor(afterID), CargoType: "sand"},
+			beforeTest: func(repo mocks.MockRouteRepo) {
+				repo.EXPECT().
+					List(gomock.Any(), entities.RouteFilter{AfterID: &afterID, Limit: 3, CargoType: "sand"}).
+					Return([]entities.Route{{RouteID: 3}, {RouteID: 4}, {RouteID: 5}}, nil)
+			},
+			expectedIDs:        []int{3, 4},
+			expectedNextCursor: dto.EncodeCursor(4),
+		},
+		{
+			name: "success (last page, default limit)",
+			req:  dto.ListRoutesRequest{},
+			beforeTest: func(repo mocks.MockRouteRepo) {
+				repo.EXPECT().
+					List(gomock.Any(), entities.RouteFilter{Limit: dto.DefaultListLimit + 1}).
+					Return([]entities.Route{{RouteID: 1}}, nil)
+			},
+			expectedIDs: []int{1},
+		},
+		{
+			name:    "invalid cursor",
+			req:     dto.ListRoutesRequest{Cursor: "???"},
+			wantErr: true,
+			err:     fmt.Errorf("converting dto to filter: invalid cursor"),
+		},
+		{
+			name:    "min load greater than max load",
+			req:     dto.ListRoutesRequest{MinLoad: &minLoad, MaxLoad: &maxLoad},
+			wantErr: true,
+			err:     fmt.Errorf("converting dto to filter: min load should not be greater than max load"),
+		},
+		{
+			name: "error in repository",
+			req:  dto.ListRoutesRequest{Limit: 1},
+			beforeTest: func(repo mocks.MockRouteRepo) {
+				repo.EXPECT().
+					List(gomock.Any(), entities.RouteFilter{Limit: 2}).
+					Return(nil, fmt.Errorf("some repo error"))
+			},
+			wantErr: true,
+			err:     fmt.Errorf("listing routes: some repo error"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.beforeTest != nil {
+				tc.beforeTest(*repo)
+			}
+
+			routes, nextCursor, err := svc.List(context.Background(), tc.req)
+
+			if tc.wantErr {
+				require.Equal(t, tc.err.Error(), err.Error())
+			} else {
+				require.Nil(t, err)
+				ids := make([]int, 0, len(routes))
+				for _, route := range routes {
+					ids = append(ids, route.RouteID)
+				}
+				require.Equal(t, tc.expectedIDs, ids)
+				require.Equal(t, tc.expectedNextCursor, nextCursor)
+			}
+		})
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockRouteRepo(ctrl)
+	vehicles := mocks.NewMockVehicleRepo(ctrl)
+	svc := NewRouteService(repo, mocks.NewMockJobRepo(ctrl), newTestCargoRepo(ctrl), vehicles)
+
+	newName := "renamed"
+	oneWaypoint := testWaypoints[:1]
+	negativeLoad := decimal.MustParse("-1")
+	heavyLoad := decimal.MustParse("25000")
+	vehicleID := 7
+
+	existing := entities.Route{
+		RouteID:   1,
+		RouteName: "test",
+		Load:      decimal.MustParse("1000"),
+		CargoType: "sand",
+		Waypoints: testRouteWaypoints,
+		Distance:  testDistance,
+		Duration:  testDuration,
+		IsActual:  true,
+	}
+
+	testCases := []struct {
+		name       string
+		id         int
+		data       dto.UpdateRouteRequestBody
+		expected   entities.Route
+		beforeTest func(repo mocks.MockRouteRepo)
+		wantErr    bool
+		err        error
+	}{
+		{
+			name: "success",
+			id:   1,
+			data: dto.UpdateRouteRequestBody{RouteName: &newName},
+			beforeTest: func(repo mocks.MockRouteRepo) {
+				repo.EXPECT().GetById(gomock.Any(), 1).Return(existing, nil)
+				repo.EXPECT().
+					Update(gomock.Any(), entities.Route{
+						RouteID:   1,
+						RouteName: "renamed",
+						Load:      decimal.MustParse("1000"),
+						CargoType: "sand",
+						Waypoints: testRouteWaypoints,
+						Distance:  testDistance,
+						Duration:  testDuration,
+						IsActual:  true,
+					}).
+					Return(nil)
+			},
+			expected: entities.Route{
+				RouteID:   1,
+				RouteName: "renamed",
+				Load:      decimal.MustParse("1000"),
+				CargoType: "sand",
+				Waypoints: testRouteWaypoints,
+				Distance:  testDistance,
+				Duration:  testDuration,
+				IsActual:  true,
+			},
+		},
+		{
+			name: "route registered without waypoints",
+			id:   1,
+			data: dto.UpdateRouteRequestBody{RouteName: &newName},
+			beforeTest: func(repo mocks.MockRouteRepo) {
+				legacy := existing
+				legacy.Waypoints = []entities.Waypoint{}
+				legacy.Distance = 0
+				legacy.Duration = 0
+				repo.EXPECT().GetById(gomock.Any(), 1).Return(legacy, nil)
+				repo.EXPECT().
+					Update(gomock.Any(), entities.Route{
+						RouteID:   1,
+						RouteName: "renamed",
+						Load:      decimal.MustParse("1000"),
+						CargoType: "sand",
+						Waypoints: []entities.Waypoint{},
+						IsActual:  true,
+					}).
+					Return(nil)
+			},
+			expected: entities.Route{
+				RouteID:   1,
+				RouteName: "renamed",
+				Load:      decimal.MustParse("1000"),
+				CargoType: "sand",
+				Waypoints: []entities.Waypoint{},
+				IsActual:  true,
+			},
+		},
+		{
+			name: "too few waypoints supplied",
+			id:   1,
+			data: dto.UpdateRouteRequestBody{Waypoints: &oneWaypoint},
+			beforeTest: func(repo mocks.MockRouteRepo) {
+				repo.EXPECT().GetById(gomock.Any(), 1).Return(existing, nil)
+			},
+			wantErr: true,
+			err:     fmt.Errorf("converting dto to entity model: route should have at least 2 waypoints"),
+		},
+		{
+			name:    "id is negative",
+			id:      -1,
+			wantErr: true,
+			err:     fmt.Errorf("route id should be non-negative"),
+		},
+		{
+			name: "invalid merged route",
+			id:   1,
+			data: dto.UpdateRouteRequestBody{Load: &negativeLoad},
+			beforeTest: func(repo mocks.MockRouteRepo) {
+				repo.EXPECT().GetById(gomock.Any(), 1).Return(existing, nil)
+			},
+			wantErr: true,
+			err:     fmt.Errorf("converting dto to entity model: load should be non-negative"),
+		},
+		{
+			name: "route is not actual",
+			id:   1,
+			data: dto.UpdateRouteRequestBody{RouteName: &newName},
+			beforeTest: func(repo mocks.MockRouteRepo) {
+				notActual := existing
+				notActual.IsActual = false
+				repo.EXPECT().GetById(gomock.Any(), 1).Return(notActual, nil)
+			},
+			wantErr: true,
+			err:     fmt.Errorf("route is not actual"),
+		},
+		{
+			name: "load exceeds capacity of assigned vehicle",
+			id:   1,
+			data: dto.UpdateRouteRequestBody{Load: &heavyLoad},
+			beforeTest: func(repo mocks.MockRouteRepo) {
+				assigned := existing
+				assigned.VehicleID = &vehicleID
+				repo.EXPECT().GetById(gomock.Any(), 1).Return(assigned, nil)
+				vehicles.EXPECT().GetById(gomock.Any(), 7).Return(testVehicle, nil)
+			},
+			wantErr: true,
+			err:     fmt.Errorf("route is assigned to vehicle 7: capacity of vehicle 7 (20000 kg) is less than load of route 1 (25000 kg)"),
+		},
+		{
+			name: "error in repository",
+			id:   1,
+			data: dto.UpdateRouteRequestBody{RouteName: &newName},
+			beforeTest: func(repo mocks.MockRouteRepo) {
+				repo.EXPECT().GetById(gomock.Any(), 1).Return(existing, nil)
+				repo.EXPECT().Update(gomock.Any(), gomock.Any()).Return(fmt.Errorf("some repo error"))
+			},
+			wantErr: true,
+			err:     fmt.Errorf("updating route: some repo error"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.beforeTest != nil {
+				tc.beforeTest(*repo)
+			}
+
+			route, err := svc.Update(context.Background(), tc.id, tc.data)
+
+			if tc.wantErr {
+				require.Equal(t, tc.err.Error(), err.Error())
+			} else {
+				require.Nil(t, err)
+				require.Equal(t, tc.expected, route)
+			}
+		})
+	}
+}
+
+func TestHistory(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockRouteRepo(ctrl)
+	svc := NewRouteService(repo, mocks.NewMockJobRepo(ctrl), mocks.NewMockCargoTypeRepo(ctrl), mocks.NewMockVehicleRepo(ctrl))
+
+	supersededBy := int64(2)
+	versions := []entities.RouteVersion{
+		{VersionID: 1, Route: entities.Route{RouteID: 1}, SupersededBy: &supersededBy},
+		{VersionID: 2, Route: entities.Route{RouteID: 7, IsActual: true}},
+	}
+
+	testCases := []struct {
+		name       string
+		id         int
+		expected   []entities.RouteVersion
+		beforeTest func(repo mocks.MockRouteRepo)
+		wantErr    bool
+		err        error
+	}{
+		{
+			name: "success",
+			id:   1,
+			beforeTest: func(repo mocks.MockRouteRepo) {
+				repo.EXPECT().History(gomock.Any(), 1).Return(versions, nil)
+			},
+			expected: versions,
+		},
+		{
+			name:    "id is negative",
+			id:      -1,
+			wantErr: true,
+			err:     fmt.Errorf("route id should be non-negative"),
+		},
+		{
+			name: "error in repository",
+			id:   1,
+			beforeTest: func(repo mocks.MockRouteRepo) {
+				repo.EXPECT().History(gomock.Any(), 1).Return(nil, fmt.Errorf("some repo error"))
+			},
+			wantErr: true,
+			err:     fmt.Errorf("getting route history: some repo error"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.beforeTest != nil {
+				tc.beforeTest(*repo)
+			}
+
+			history, err := svc.History(context.Background(), tc.id)
+
+			if tc.wantErr {
+				require.Equal(t, tc.err.Error(), err.Error())
+			} else {
+				require.Nil(t, err)
+				require.Equal(t, tc.expected, history)
+			}
+		})
+	}
+}
+
+func TestSearch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockRouteRepo(ctrl)
+	svc := NewRouteService(repo, mocks.NewMockJobRepo(ctrl), mocks.NewMockCargoTypeRepo(ctrl), mocks.NewMockVehicleRepo(ctrl))
+
+	routes := []entities.Route{{RouteID: 3, RouteName: "Moscow - Tver", IsActual: true}}
+
+	testCases := []struct {
+		name       string
+		req        dto.SearchRoutesRequest
+		expected   []entities.Route
+		beforeTest func(repo mocks.MockRouteRepo)
+		wantErr    bool
+		kind       error
+		err        error
+	}{
+		{
+			name: "default limit",
+			req:  dto.SearchRoutesRequest{Query: "  mosow "},
+			beforeTest: func(repo mocks.MockRouteRepo) {
+				repo.EXPECT().Search(gomock.Any(), "mosow", dto.DefaultListLimit).Return(routes, nil)
+			},
+			expected: routes,
+		},
+		{
+			name: "explicit limit",
+			req:  dto.SearchRoutesRequest{Query: "tver", Limit: 5},
+			beforeTest: func(repo mocks.MockRouteRepo) {
+				repo.EXPECT().Search(gomock.Any(), "tver", 5).Return(routes, nil)
+			},
+			expected: routes,
+		},
+		{
+			name:    "empty query",
+			req:     dto.SearchRoutesRequest{Query: " "},
+			wantErr: true,
+			kind:    ErrValidation,
+			err:     fmt.Errorf("converting dto to search query: search query should not be empty"),
+		},
+		{
+			name:    "limit is too large",
+			req:     dto.SearchRoutesRequest{Query: "tver", Limit: 101},
+			wantErr: true,
+			kind:    ErrValidation,
+			err:     fmt.Errorf("converting dto to search query: limit should not be greater than 100"),
+		},
+		{
+			name: "error in repository",
+			req:  dto.SearchRoutesRequest{Query: "tver"},
+			beforeTest: func(repo mocks.MockRouteRepo) {
+				repo.EXPECT().Search(gomock.Any(), "tver", dto.DefaultListLimit).Return(nil, fmt.Errorf("some repo error"))
+			},
+			wantErr: true,
+			err:     fmt.Errorf("searching routes: some repo error"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.beforeTest != nil {
+				tc.beforeTest(*repo)
+			}
+
+			res, err := svc.Search(context.Background(), tc.req)
+
+			if tc.wantErr {
+				require.Equal(t, tc.err.Error(), err.Error())
+				if tc.kind != nil {
+					require.ErrorIs(t, err, tc.kind)
+				}
+			} else {
+				require.Nil(t, err)
+				require.Equal(t, tc.expected, res)
+			}
+		})
+	}
+}
+
+func TestStats(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockRouteRepo(ctrl)
+	svc := NewRouteService(repo, mocks.NewMockJobRepo(ctrl), mocks.NewMockCargoTypeRepo(ctrl), mocks.NewMockVehicleRepo(ctrl))
+
+	stats := []entities.RouteStats{
+		{
+			CargoType: "gravel",
+			IsActual:  true,
+			Count:     3,
+			TotalLoad: decimal.MustParse("3500.5"),
+			AvgLoad:   decimal.MustParse("1166.8"),
+			MinLoad:   decimal.MustParse("500"),
+			MaxLoad:   decimal.MustParse("2000.5"),
+		},
+	}
+
+	testCases := []struct {
+		name       string
+		expected   []entities.RouteStats
+		beforeTest func(repo mocks.MockRouteRepo)
+		wantErr    bool
+		err        error
+	}{
+		{
+			name: "success",
+			beforeTest: func(repo mocks.MockRouteRepo) {
+				repo.EXPECT().Stats(gomock.Any()).Return(stats, nil)
+			},
+			expected: stats,
+		},
+		{
+			name: "error in repository",
+			beforeTest: func(repo mocks.MockRouteRepo) {
+				repo.EXPECT().Stats(gomock.Any()).Return(nil, fmt.Errorf("some repo error"))
+			},
+			wantErr: true,
+			err:     fmt.Errorf("getting route stats: some repo error"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.beforeTest(*repo)
+
+			res, err := svc.Stats(context.Background())
+
+			if tc.wantErr {
+				require.Equal(t, tc.err.Error(), err.Error())
+			} else {
+				require.Nil(t, err)
+				require.Equal(t, tc.expected, res)
+			}
+		})
+	}
+}
+
+func TestDeleteJobTimeout(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockRouteRepo(ctrl)
+	jobs := mocks.NewMockJobRepo(ctrl)
+	svc := NewRouteService(repo, jobs, mocks.NewMockCargoTypeRepo(ctrl), mocks.NewMockVehicleRepo(ctrl))
+	svc.(*routeService).deleteTimeout = 10 * time.Millisecond
+
+	jobs.EXPECT().CreateDeleteJob(gomock.Any(), []int{1}).Return(int64(1), nil)
+	jobs.EXPECT().StartDeleteJob(gomock.Any(), int64(1)).Return(nil)
+	repo.EXPECT().
+		DeleteById(gomock.Any(), []int{1}).
+		DoAndReturn(func(ctx context.Context, ids []int) ([]int, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		})
+	var saveErr error
+	jobs.EXPECT().
+		FailDeleteJob(gomock.Any(), int64(1), "deleting routes: context deadline exceeded").
+		DoAndReturn(func(ctx context.Context, id int64, reason string) error {
+			// failure would not be saved with expired context
+			saveErr = ctx.Err()
+			return saveErr
+		})
+
+	_, err := svc.DeleteByIds(context.Background(), dto.DeleteRoutesRequestBody{RouteIDs: []int{1}})
+	require.Nil(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	require.Nil(t, svc.Shutdown(ctx))
+	require.Nil(t, saveErr)
+}
+
+func TestShutdown(t *testing.T) {
+	testCases := []struct {
+		name       string
+		timeout    time.Duration
+		beforeTest func(repo mocks.MockRouteRepo, jobs mocks.MockJobRepo)
+		wantErr    bool
+		err        error
+	}{
+		{
+			name:    "waits for running delete",
+			timeout: time.Second,
+			beforeTest: func(repo mocks.MockRouteRepo, jobs mocks.MockJobRepo) {
+				jobs.EXPECT().CreateDeleteJob(gomock.Any(), []int{1}).Return(int64(1), nil)
+				jobs.EXPECT().StartDeleteJob(gomock.Any(), int64(1)).Return(nil)
+				repo.EXPECT().
+					DeleteById(gomock.Any(), []int{1}).
+					DoAndReturn(func(ctx context.Context, ids []int) ([]int, error) {
+						time.Sleep(50 * time.Millisecond)
+						return ids, nil
+					})
+				jobs.EXPECT().FinishDeleteJob(gomock.Any(), int64(1), []int{1}).Return(nil)
+			},
+		},
+		{
+			name:    "cancels delete after timeout",
+			timeout: 10 * time.Millisecond,
+			beforeTest: func(repo mocks.MockRouteRepo, jobs mocks.MockJobRepo) {
+				jobs.EXPECT().CreateDeleteJob(gomock.Any(), []int{1}).Return(int64(1), nil)
+				jobs.EXPECT().StartDeleteJob(gomock.Any(), int64(1)).Return(nil)
+				repo.EXPECT().
+					DeleteById(gomock.Any(), []int{1}).
+					DoAndReturn(func(ctx context.Context, ids []int) ([]int, error) {
+						<-ctx.Done()
+						return nil, ctx.Err()
+					})
+			},
+			wantErr: true,
+			err:     fmt.Errorf("waiting for background work: context deadline exceeded"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := mocks.NewMockRouteRepo(ctrl)
+			jobs := mocks.NewMockJobRepo(ctrl)
+			svc := NewRouteService(repo, jobs, mocks.NewMockCargoTypeRepo(ctrl), mocks.NewMockVehicleRepo(ctrl))
+
+			tc.beforeTest(*repo, *jobs)
+
+			_, err := svc.DeleteByIds(context.Background(), dto.DeleteRoutesRequestBody{RouteIDs: []int{1}})
+			require.Nil(t, err)
+
+			ctx, cancel := context.WithTimeout(context.Background(), tc.timeout)
+			defer cancel()
+
+			err = svc.Shutdown(ctx)
+
+			if tc.wantErr {
+				require.Equal(t, tc.err.Error(), err.Error())
+			} else {
+				require.Nil(t, err)
+			}
+		})
+	}
+}
+
+func TestRegisterBatch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockRouteRepo(ctrl)
+	svc := NewRouteService(repo, mocks.NewMockJobRepo(ctrl), newTestCargoRepo(ctrl), mocks.NewMockVehicleRepo(ctrl))
+
+	valid := dto.RegisterRouteRequestBody{
+		RouteID:   1,
+		RouteName: "test",
+		Load:      decimal.MustParse("1000"),
+		CargoType: "sand",
+		Waypoints: testWaypoints,
+	}
+	validRoute := entities.Route{
+		RouteID:   1,
+		RouteName: "test",
+		Load:      decimal.MustParse("1000"),
+		CargoType: "sand",
+		Waypoints: testRouteWaypoints,
+		Distance:  testDistance,
+		Duration:  testDuration,
+	}
+	invalid := dto.RegisterRouteRequestBody{
+		RouteID:   2,
+		RouteName: "test",
+		Load:      decimal.MustParse("-1000"),
+		CargoType: "sand",
+		Waypoints: testWaypoints,
+	}
+
+	testCases := []struct {
+		name       string
+		data       dto.RegisterBatchRequestBody
+		expected   []entities.RegisterResult
+		beforeTest func(repo mocks.MockRouteRepo)
+		wantErr    bool
+		err        error
+	}{
+		{
+			name: "atomic success",
+			data: dto.RegisterBatchRequestBody{
+				Atomic: true,
+				Routes: []dto.RegisterRouteRequestBody{valid, valid},
+			},
+			beforeTest: func(repo mocks.MockRouteRepo) {
+				repo.EXPECT().
+					RegisterBatch(gomock.Any(), []entities.Route{validRoute, validRoute}).
+					Return([]int{1, 5}, nil)
+			},
+			expected: []entities.RegisterResult{
+				{RouteID: 1},
+				{RouteID: 5, Reissued: true},
+			},
+		},
+		{
+			name: "atomic with invalid route",
+			data: dto.RegisterBatchRequestBody{
+				Atomic: true,
+				Routes: []dto.RegisterRouteRequestBody{valid, invalid},
+			},
+			wantErr: true,
+			err:     fmt.Errorf("route #1: converting dto to entity model: load should be non-negative"),
+		},
+		{
+			name: "atomic error in repository",
+			data: dto.RegisterBatchRequestBody{
+				Atomic: true,
+				Routes: []dto.RegisterRouteRequestBody{valid},
+			},
+			beforeTest: func(repo mocks.MockRouteRepo) {
+				repo.EXPECT().
+					RegisterBatch(gomock.Any(), []entities.Route{validRoute}).
+					Return(nil, fmt.Errorf("some repo error"))
+			},
+			wantErr: true,
+			err:     fmt.Errorf("batch registration: some repo error"),
+		},
+		{
+			name: "per item",
+			data: dto.RegisterBatchRequestBody{
+				Routes: []dto.RegisterRouteRequestBody{valid, invalid, valid},
+			},
+			beforeTest: func(repo mocks.MockRouteRepo) {
+				gomock.InOrder(
+					repo.EXPECT().Register(gomock.Any(), validRoute).Return(1, nil),
+					repo.EXPECT().Register(gomock.Any(), validRoute).Return(0, fmt.Errorf("some repo error")),
+				)
+			},
+			expected: []entities.RegisterResult{
+				{RouteID: 1},
+				{Err: fmt.Errorf("route #1: converting dto to entity model: load should be non-negative")},
+				{Err: fmt.Errorf("route #2: route registration: some repo error")},
+			},
+		},
+		{
+			name:    "empty batch",
+			data:    dto.RegisterBatchRequestBody{},
+			wantErr: true,
+			err:     fmt.Errorf("batch should not be empty"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.beforeTest != nil {
+				tc.beforeTest(*repo)
+			}
+
+			results, err := svc.RegisterBatch(context.Background(), tc.data)
+
+			if tc.wantErr {
+				require.Equal(t, tc.err.Error(), err.Error())
+			} else {
+				require.Nil(t, err)
+				require.Len(t, results, len(tc.expected))
+				for i, res := range results {
+					require.Equal(t, tc.expected[i].RouteID, res.RouteID)
+					require.Equal(t, tc.expected[i].Reissued, res.Reissued)
+					if tc.expected[i].Err != nil {
+						require.Equal(t, tc.expected[i].Err.Error(), res.Err.Error())
+					} else {
+						require.Nil(t, res.Err)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestExport(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockRouteRepo(ctrl)
+	svc := NewRouteService(repo, mocks.NewMockJobRepo(ctrl), mocks.NewMockCargoTypeRepo(ctrl), mocks.NewMockVehicleRepo(ctrl))
+
+	isActual := true
+	routes := []entities.Route{{RouteID: 1}, {RouteID: 2}}
+
+	testCases := []struct {
+		name        string
+		req         dto.ListRoutesRequest
+		expectedIDs []int
+		beforeTest  func(repo mocks.MockRouteRepo)
+		wantErr     bool
+		err         error
+	}{
+		{
+			name: "success (without limit)",
+			req:  dto.ListRoutesRequest{IsActual: &isActual},
+			beforeTest: func(repo mocks.MockRouteRepo) {
+				repo.EXPECT().
+					Export(gomock.Any(), entities.RouteFilter{IsActual: &isActual}, gomock.Any()).
+					DoAndReturn(func(ctx context.Context, filter entities.RouteFilter, fn func(entities.Route) error) error {
+						for _, route := range routes {
+							if err := fn(route); err != nil {
+								return err
+							}
+						}
+						return nil
+					})
+			},
+			expectedIDs: []int{1, 2},
+		},
+		{
+			name:    "invalid filter",
+			req:     dto.ListRoutesRequest{Cursor: "???"},
+			wantErr: true,
+			err:     fmt.Errorf("converting dto to filter: invalid cursor"),
+		},
+		{
+			name: "error in repository",
+			req:  dto.ListRoutesRequest{},
+			beforeTest: func(repo mocks.MockRouteRepo) {
+				repo.EXPECT().
+					Export(gomock.Any(), entities.RouteFilter{}, gomock.Any()).
+					Return(fmt.Errorf("some repo error"))
+			},
+			wantErr: true,
+			err:     fmt.Errorf("exporting routes: some repo error"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.beforeTest != nil {
+				tc.beforeTest(*repo)
+			}
+
+			var ids []int
+			err := svc.Export(context.Background(), tc.req, func(route entities.Route) error {
+				ids = append(ids, route.RouteID)
+				return nil
+			})
+
+			if tc.wantErr {
+				require.Equal(t, tc.err.Error(), err.Error())
+			} else {
+				require.Nil(t, err)
+				require.Equal(t, tc.expectedIDs, ids)
+			}
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"task/internal/dto"
+	"task/internal/entities"
+	"task/internal/repositories"
+)
+
+type ScheduleService interface {
+	Set(ctx context.Context, routeID int, data dto.ScheduleRequestBody) (entities.Schedule, error)
+	Get(ctx context.Context, routeID int) (entities.Schedule, error)
+	Delete(ctx context.Context, routeID int) error
+	Trips(ctx context.Context, req dto.TripsRequest) ([]entities.Trip, string, error)
+}
+
+type scheduleService struct {
+	repo   repositories.ScheduleRepo
+	routes repositories.RouteRepo
+}
+
+func NewScheduleService(repo repositories.ScheduleRepo, routes repositories.RouteRepo) ScheduleService {
+	return &scheduleService{
+		repo:   repo,
+		routes: routes,
+	}
+}
+
+// Set creates or replaces schedule of actual route.
+func (s *scheduleService) Set(ctx context.Context, routeID int, data dto.ScheduleRequestBody) (schedule entities.Schedule, err error) {
+	if routeID < 0 {
+		return entities.Schedule{}, validationError(fmt.Errorf("route id should be non-negative"))
+	}
+
+	schedule, err = dto.ToScheduleModel(routeID, data)
+	if err != nil {
+		return entities.Schedule{}, validationError(fmt.Errorf("converting dto to schedule: %w", err))
+	}
+
+	route, err := s.routes.GetById(ctx, routeID)
+	if err != nil {
+		return entities.Schedule{}, repoError(fmt.Errorf("getting route by id: %w", err))
+	}
+	if !route.IsActual {
+		return entities.Schedule{}, conflictError(fmt.Errorf("route is not actual"))
+	}
+
+	err = s.repo.Set(ctx, schedule)
+	if err != nil {
+		return entities.Schedule{}, fmt.Errorf("setting schedule: %w", err)
+	}
+
+	return schedule, nil
+}
+
+func (s *scheduleService) Get(ctx context.Context, routeID int) (schedule entities.Schedule, err error) {
+	if routeID < 0 {
+		return entities.Schedule{}, validationError(fmt.Errorf("route id should be non-negative"))
+	}
+
+	schedule, err = s.repo.Get(ctx, routeID)
+	if err != nil {
+		return entities.Schedule{}, repoError(fmt.Errorf("getting schedule: %w", err))
+	}
+
+	return schedule, nil
+}
+
+func (s *scheduleService) Delete(ctx context.Context, routeID int) (err error) {
+	if routeID < 0 {
+		return validationError(fmt.Errorf("route id should be non-negative"))
+	}
+
+	err = s.repo.Delete(ctx, routeID)
+	if err != nil {
+		return repoError(fmt.Errorf("deleting schedule: %w", err))
+	}
+
+	return nil
+}
+
+// Trips expands schedules of actual routes into trips departing in the requested date range,
+// ordered by departure. Only one page of trips is returned, nextCursor is empty on the last page.
+func (s *scheduleService) Trips(ctx context.Context, req dto.TripsRequest) (trips []entities.Trip, nextCursor string, err error) {
+	first, last, err := dto.ToTripRange(req)
+	if err != nil {
+		return nil, "", validationError(fmt.Errorf("converting dto to trip range: %w", err))
+	}
+	limit, after, err := dto.ToTripPage(req)
+	if err != nil {
+		return nil, "", validationError(fmt.Errorf("converting dto to trip page: %w", err))
+	}
+
+	var routeIDs []int
+	if req.RouteID != nil {
+		routeIDs = []int{*req.RouteID}
+	}
+
+	schedules, err := s.repo.List(ctx, routeIDs)
+	if err != nil {
+		return nil, "", fmt.Errorf("listing schedules: %w", err)
+	}
+
+	// one extra trip tells whether next page exists; trips beyond it are dropped as schedules
+	// are expanded, so memory does not grow with the number of routes
+	pageSize := limit + 1
+	trips = make([]entities.Trip, 0)
+	for _, schedule := range schedules {
+		routeTrips, err := schedule.Trips(first, last)
+		if err != nil {
+			return nil, "", fmt.Errorf("expanding schedule: %w", err)
+		}
+		for _, trip := range routeTrips {
+			if after == nil || compareTrips(trip, *after) > 0 {
+				trips = append(trips, trip)
+			}
+		}
+		if len(trips) > 2*pageSize {
+			slices.SortFunc(trips, compareTrips)
+			trips = trips[:pageSize]
+		}
+	}
+
+	slices.SortFunc(trips, compareTrips)
+	if len(trips) > limit {
+		trips = trips[:limit]
+		nextCursor = dto.EncodeTripCursor(trips[limit-1])
+	}
+
+	return trips, nextCursor, nil
+}
+
+// compareTrips orders trips by departure, trips of one route never depart at the same time.
+func compareTrips(a, b entities.Trip) int {
+	if c := a.Departure.From.Compare(b.Departure.From); c != 0 {
+		return c
+	}
+	return a.RouteID - b.RouteID
+}
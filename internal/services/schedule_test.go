@@ -0,0 +1,308 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"task/internal/dto"
+	"task/internal/entities"
+	"task/internal/mocks"
+	"testing"
+	"time"
+)
+
+var testScheduleBody = dto.ScheduleRequestBody{
+	Departure:  dto.TimeWindowBody{From: "08:00", To: "08:30"},
+	Arrival:    dto.TimeWindowBody{From: "25:00", To: "26:15"},
+	Recurrence: "weekdays",
+}
+
+var testSchedule = entities.Schedule{
+	RouteID:    1,
+	Departure:  entities.TimeWindow{From: 8 * time.Hour, To: 8*time.Hour + 30*time.Minute},
+	Arrival:    entities.TimeWindow{From: 25 * time.Hour, To: 26*time.Hour + 15*time.Minute},
+	Recurrence: entities.MustParseRecurrence("weekdays"),
+	Timezone:   "UTC",
+}
+
+func TestSetSchedule(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	schedules := mocks.NewMockScheduleRepo(ctrl)
+	routes := mocks.NewMockRouteRepo(ctrl)
+	svc := NewScheduleService(schedules, routes)
+
+	withBody := func(f func(data *dto.ScheduleRequestBody)) dto.ScheduleRequestBody {
+		data := testScheduleBody
+		f(&data)
+		return data
+	}
+
+	testCases := []struct {
+		name       string
+		routeID    int
+		data       dto.ScheduleRequestBody
+		expected   entities.Schedule
+		beforeTest func(schedules mocks.MockScheduleRepo, routes mocks.MockRouteRepo)
+		wantErr    bool
+		kind       error
+		err        error
+	}{
+		{
+			name:    "success",
+			routeID: 1,
+			data:    testScheduleBody,
+			beforeTest: func(schedules mocks.MockScheduleRepo, routes mocks.MockRouteRepo) {
+				routes.EXPECT().GetById(gomock.Any(), 1).Return(entities.Route{RouteID: 1, IsActual: true}, nil)
+				schedules.EXPECT().Set(gomock.Any(), testSchedule).Return(nil)
+			},
+			expected: testSchedule,
+		},
+		{
+			name:    "departure window ends before it starts",
+			routeID: 1,
+			data: withBody(func(data *dto.ScheduleRequestBody) {
+				data.Departure.To = "07:59"
+			}),
+			wantErr: true,
+			kind:    ErrValidation,
+			err:     fmt.Errorf("converting dto to schedule: departure window should not end before it starts"),
+		},
+		{
+			name:    "invalid time",
+			routeID: 1,
+			data: withBody(func(data *dto.ScheduleRequestBody) {
+				data.Arrival.From = "9:5"
+			}),
+			wantErr: true,
+			kind:    ErrValidation,
+			err:     fmt.Errorf(`converting dto to schedule: arrival window start: invalid time "9:5", expected HH:MM`),
+		},
+		{
+			name:    "arrival before departure",
+			routeID: 1,
+			data: withBody(func(data *dto.ScheduleRequestBody) {
+				data.Arrival = dto.TimeWindowBody{From: "07:00", To: "09:00"}
+			}),
+			wantErr: true,
+			kind:    ErrValidation,
+			err:     fmt.Errorf("converting dto to schedule: arrival window should not start before departure window"),
+		},
+		{
+			name:    "invalid recurrence",
+			routeID: 1,
+			data: withBody(func(data *dto.ScheduleRequestBody) {
+				data.Recurrence = "hourly"
+			}),
+			wantErr: true,
+			kind:    ErrValidation,
+			err:     fmt.Errorf(`converting dto to schedule: recurrence "hourly" should be "daily", "weekdays" or "day-of-month month day-of-week"`),
+		},
+		{
+			name:    "unknown time zone",
+			routeID: 1,
+			data: withBody(func(data *dto.ScheduleRequestBody) {
+				data.Timezone = "Mars/Olympus"
+			}),
+			wantErr: true,
+			kind:    ErrValidation,
+			err:     fmt.Errorf(`converting dto to schedule: unknown time zone "Mars/Olympus"`),
+		},
+		{
+			name:    "valid period is reversed",
+			routeID: 1,
+			data: withBody(func(data *dto.ScheduleRequestBody) {
+				data.ValidFrom = "2026-10-17"
+				data.ValidUntil = "2026-10-16"
+			}),
+			wantErr: true,
+			kind:    ErrValidation,
+			err:     fmt.Errorf("converting dto to schedule: valid from should not be after valid until"),
+		},
+		{
+			name:    "route not found",
+			routeID: 5,
+			data:    testScheduleBody,
+			beforeTest: func(schedules mocks.MockScheduleRepo, routes mocks.MockRouteRepo) {
+				routes.EXPECT().GetById(gomock.Any(), 5).Return(entities.Route{}, pgx.ErrNoRows)
+			},
+			wantErr: true,
+			kind:    ErrNotFound,
+			err:     fmt.Errorf("getting route by id: %w", pgx.ErrNoRows),
+		},
+		{
+			name:    "route is not actual",
+			routeID: 2,
+			data:    testScheduleBody,
+			beforeTest: func(schedules mocks.MockScheduleRepo, routes mocks.MockRouteRepo) {
+				routes.EXPECT().GetById(gomock.Any(), 2).Return(entities.Route{RouteID: 2}, nil)
+			},
+			wantErr: true,
+			kind:    ErrConflict,
+			err:     fmt.Errorf("route is not actual"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.beforeTest != nil {
+				tc.beforeTest(*schedules, *routes)
+			}
+
+			schedule, err := svc.Set(context.Background(), tc.routeID, tc.data)
+
+			if tc.wantErr {
+				require.Equal(t, tc.err.Error(), err.Error())
+				require.ErrorIs(t, err, tc.kind)
+			} else {
+				require.Nil(t, err)
+				require.Equal(t, tc.expected, schedule)
+			}
+		})
+	}
+}
+
+func TestTrips(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	schedules := mocks.NewMockScheduleRepo(ctrl)
+	svc := NewScheduleService(schedules, mocks.NewMockRouteRepo(ctrl))
+
+	early := testSchedule
+	early.RouteID = 3
+	early.Recurrence = entities.MustParseRecurrence("daily")
+	early.Departure = entities.TimeWindow{From: 6 * time.Hour, To: 7 * time.Hour}
+	early.Timezone = "Europe/Moscow"
+
+	day := func(d int) time.Time {
+		return time.Date(2026, 10, d, 0, 0, 0, 0, time.UTC)
+	}
+	at := func(d int, hour int, min int, tz string) time.Time {
+		loc, err := time.LoadLocation(tz)
+		require.Nil(t, err)
+		return time.Date(2026, 10, d, hour, min, 0, 0, loc)
+	}
+
+	routeID := 3
+	trips := []entities.Trip{
+		{
+			RouteID:   3,
+			Date:      day(16),
+			Departure: entities.Interval{From: at(16, 6, 0, "Europe/Moscow"), To: at(16, 7, 0, "Europe/Moscow")},
+			Arrival:   entities.Interval{From: at(17, 1, 0, "Europe/Moscow"), To: at(17, 2, 15, "Europe/Moscow")},
+		},
+		{
+			RouteID:   1,
+			Date:      day(16),
+			Departure: entities.Interval{From: at(16, 8, 0, "UTC"), To: at(16, 8, 30, "UTC")},
+			Arrival:   entities.Interval{From: at(17, 1, 0, "UTC"), To: at(17, 2, 15, "UTC")},
+		},
+		{
+			RouteID:   3,
+			Date:      day(17),
+			Departure: entities.Interval{From: at(17, 6, 0, "Europe/Moscow"), To: at(17, 7, 0, "Europe/Moscow")},
+			Arrival:   entities.Interval{From: at(18, 1, 0, "Europe/Moscow"), To: at(18, 2, 15, "Europe/Moscow")},
+		},
+	}
+
+	testCases := []struct {
+		name       string
+		req        dto.TripsRequest
+		expected   []entities.Trip
+		nextCursor string
+		beforeTest func(schedules mocks.MockScheduleRepo)
+		wantErr    bool
+		kind       error
+		err        error
+	}{
+		{
+			name: "ordered by departure",
+			req:  dto.TripsRequest{From: "2026-10-16", To: "2026-10-17"},
+			beforeTest: func(schedules mocks.MockScheduleRepo) {
+				schedules.EXPECT().List(gomock.Any(), nil).Return([]entities.Schedule{testSchedule, early}, nil)
+			},
+			expected: trips,
+		},
+		{
+			name: "first page",
+			req:  dto.TripsRequest{From: "2026-10-16", To: "2026-10-17", Limit: 2},
+			beforeTest: func(schedules mocks.MockScheduleRepo) {
+				schedules.EXPECT().List(gomock.Any(), nil).Return([]entities.Schedule{testSchedule, early}, nil)
+			},
+			expected:   trips[:2],
+			nextCursor: dto.EncodeTripCursor(trips[1]),
+		},
+		{
+			name: "last page",
+			req:  dto.TripsRequest{From: "2026-10-16", To: "2026-10-17", Limit: 2, Cursor: dto.EncodeTripCursor(trips[1])},
+			beforeTest: func(schedules mocks.MockScheduleRepo) {
+				schedules.EXPECT().List(gomock.Any(), nil).Return([]entities.Schedule{testSchedule, early}, nil)
+			},
+			expected: trips[2:],
+		},
+		{
+			name: "one route",
+			req:  dto.TripsRequest{From: "2026-10-17", To: "2026-10-18", RouteID: &routeID},
+			beforeTest: func(schedules mocks.MockScheduleRepo) {
+				schedules.EXPECT().List(gomock.Any(), []int{3}).Return(nil, nil)
+			},
+			expected: []entities.Trip{},
+		},
+		{
+			name:    "missing dates",
+			req:     dto.TripsRequest{From: "2026-10-17"},
+			wantErr: true,
+			kind:    ErrValidation,
+			err:     fmt.Errorf("converting dto to trip range: from and to dates should be set"),
+		},
+		{
+			name:    "reversed range",
+			req:     dto.TripsRequest{From: "2026-10-17", To: "2026-10-16"},
+			wantErr: true,
+			kind:    ErrValidation,
+			err:     fmt.Errorf("converting dto to trip range: to date should not be before from date"),
+		},
+		{
+			name:    "range is too long",
+			req:     dto.TripsRequest{From: "2026-01-01", To: "2027-01-02"},
+			wantErr: true,
+			kind:    ErrValidation,
+			err:     fmt.Errorf("converting dto to trip range: date range should not be longer than 366 days"),
+		},
+		{
+			name:    "limit is too large",
+			req:     dto.TripsRequest{From: "2026-10-16", To: "2026-10-17", Limit: 101},
+			wantErr: true,
+			kind:    ErrValidation,
+			err:     fmt.Errorf("converting dto to trip page: limit should not be greater than 100"),
+		},
+		{
+			name:    "invalid cursor",
+			req:     dto.TripsRequest{From: "2026-10-16", To: "2026-10-17", Cursor: "???"},
+			wantErr: true,
+			kind:    ErrValidation,
+			err:     fmt.Errorf("converting dto to trip page: invalid cursor"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.beforeTest != nil {
+				tc.beforeTest(*schedules)
+			}
+
+			trips, nextCursor, err := svc.Trips(context.Background(), tc.req)
+
+			if tc.wantErr {
+				require.Equal(t, tc.err.Error(), err.Error())
+				require.ErrorIs(t, err, tc.kind)
+			} else {
+				require.Nil(t, err)
+				require.Equal(t, tc.expected, trips)
+				require.Equal(t, tc.nextCursor, nextCursor)
+			}
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"task/internal/dto"
+	"task/internal/entities"
+)
+
+// Near returns actual routes passing within requested radius of the point.
+func (s *routeService) Near(ctx context.Context, req dto.NearRequest) (routes []entities.Route, err error) {
+	point, radius, err := dto.ToNearQuery(req)
+	if err != nil {
+		return nil, validationError(fmt.Errorf("converting dto to near query: %w", err))
+	}
+
+	routes, err = s.repo.Near(ctx, point, radius)
+	if err != nil {
+		return nil, fmt.Errorf("finding routes near point: %w", err)
+	}
+
+	return routes, nil
+}
+
+// Within returns actual routes passing through requested bounding box.
+func (s *routeService) Within(ctx context.Context, req dto.WithinRequest) (routes []entities.Route, err error) {
+	box, err := dto.ToBBox(req)
+	if err != nil {
+		return nil, validationError(fmt.Errorf("converting dto to bbox: %w", err))
+	}
+
+	routes, err = s.repo.Within(ctx, box)
+	if err != nil {
+		return nil, fmt.Errorf("finding routes within bbox: %w", err)
+	}
+
+	return routes, nil
+}
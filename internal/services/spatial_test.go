@@ -0,0 +1,150 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"task/internal/dto"
+	"task/internal/entities"
+	"task/internal/mocks"
+	"testing"
+)
+
+func TestNear(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockRouteRepo(ctrl)
+	svc := NewRouteService(repo, mocks.NewMockJobRepo(ctrl), mocks.NewMockCargoTypeRepo(ctrl), mocks.NewMockVehicleRepo(ctrl))
+
+	routes := []entities.Route{{RouteID: 1, Waypoints: testRouteWaypoints}}
+
+	testCases := []struct {
+		name       string
+		req        dto.NearRequest
+		expected   []entities.Route
+		beforeTest func(repo mocks.MockRouteRepo)
+		wantErr    bool
+		err        error
+	}{
+		{
+			name: "success",
+			req:  dto.NearRequest{Lat: 55.75, Lon: 37.61, RadiusM: 1000},
+			beforeTest: func(repo mocks.MockRouteRepo) {
+				repo.EXPECT().Near(gomock.Any(), entities.Waypoint{Lat: 55.75, Lon: 37.61}, 1000.0).Return(routes, nil)
+			},
+			expected: routes,
+		},
+		{
+			name:    "latitude out of range",
+			req:     dto.NearRequest{Lat: -91, Lon: 37.61, RadiusM: 1000},
+			wantErr: true,
+			err:     fmt.Errorf("converting dto to near query: latitude should be in range [-90, 90]"),
+		},
+		{
+			name:    "zero radius",
+			req:     dto.NearRequest{Lat: 55.75, Lon: 37.61},
+			wantErr: true,
+			err:     fmt.Errorf("converting dto to near query: radius should be positive"),
+		},
+		{
+			name:    "radius is too big",
+			req:     dto.NearRequest{Lat: 55.75, Lon: 37.61, RadiusM: dto.MaxNearRadius + 1},
+			wantErr: true,
+			err:     fmt.Errorf("converting dto to near query: radius should not be greater than 500000 meters"),
+		},
+		{
+			name: "error in repository",
+			req:  dto.NearRequest{Lat: 55.75, Lon: 37.61, RadiusM: 1000},
+			beforeTest: func(repo mocks.MockRouteRepo) {
+				repo.EXPECT().Near(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, fmt.Errorf("some repo error"))
+			},
+			wantErr: true,
+			err:     fmt.Errorf("finding routes near point: some repo error"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.beforeTest != nil {
+				tc.beforeTest(*repo)
+			}
+
+			routes, err := svc.Near(context.Background(), tc.req)
+
+			if tc.wantErr {
+				require.Equal(t, tc.err.Error(), err.Error())
+			} else {
+				require.Nil(t, err)
+				require.Equal(t, tc.expected, routes)
+			}
+		})
+	}
+}
+
+func TestWithin(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockRouteRepo(ctrl)
+	svc := NewRouteService(repo, mocks.NewMockJobRepo(ctrl), mocks.NewMockCargoTypeRepo(ctrl), mocks.NewMockVehicleRepo(ctrl))
+
+	routes := []entities.Route{{RouteID: 1, Waypoints: testRouteWaypoints}}
+
+	testCases := []struct {
+		name       string
+		req        dto.WithinRequest
+		expected   []entities.Route
+		beforeTest func(repo mocks.MockRouteRepo)
+		wantErr    bool
+		err        error
+	}{
+		{
+			name: "success",
+			req:  dto.WithinRequest{MinLon: 30, MinLat: 55, MaxLon: 38, MaxLat: 60},
+			beforeTest: func(repo mocks.MockRouteRepo) {
+				repo.EXPECT().
+					Within(gomock.Any(), entities.BBox{MinLat: 55, MinLon: 30, MaxLat: 60, MaxLon: 38}).
+					Return(routes, nil)
+			},
+			expected: routes,
+		},
+		{
+			name:    "longitude out of range",
+			req:     dto.WithinRequest{MinLon: 30, MinLat: 55, MaxLon: 181, MaxLat: 60},
+			wantErr: true,
+			err:     fmt.Errorf("converting dto to bbox: bbox max corner: longitude should be in range [-180, 180]"),
+		},
+		{
+			name:    "min corner greater than max corner",
+			req:     dto.WithinRequest{MinLon: 38, MinLat: 55, MaxLon: 30, MaxLat: 60},
+			wantErr: true,
+			err:     fmt.Errorf("converting dto to bbox: bbox min corner should not be greater than max corner"),
+		},
+		{
+			name: "error in repository",
+			req:  dto.WithinRequest{MinLon: 30, MinLat: 55, MaxLon: 38, MaxLat: 60},
+			beforeTest: func(repo mocks.MockRouteRepo) {
+				repo.EXPECT().Within(gomock.Any(), gomock.Any()).Return(nil, fmt.Errorf("some repo error"))
+			},
+			wantErr: true,
+			err:     fmt.Errorf("finding routes within bbox: some repo error"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.beforeTest != nil {
+				tc.beforeTest(*repo)
+			}
+
+			routes, err := svc.Within(context.Background(), tc.req)
+
+			if tc.wantErr {
+				require.Equal(t, tc.err.Error(), err.Error())
+			} else {
+				require.Nil(t, err)
+				require.Equal(t, tc.expected, routes)
+			}
+		})
+	}
+}
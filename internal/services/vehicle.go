@@ -0,0 +1,218 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"task/internal/dto"
+	"task/internal/entities"
+	"task/internal/repositories"
+)
+
+type VehicleService interface {
+	Create(ctx context.Context, data dto.VehicleRequestBody) (entities.Vehicle, error)
+	GetById(ctx context.Context, id int) (entities.Vehicle, error)
+	List(ctx context.Context) ([]entities.Vehicle, error)
+	Update(ctx context.Context, id int, data dto.UpdateVehicleRequestBody) (entities.Vehicle, error)
+	Delete(ctx context.Context, id int) error
+}
+
+type vehicleService struct {
+	repo   repositories.VehicleRepo
+	routes repositories.RouteRepo
+	cargo  repositories.CargoTypeRepo
+}
+
+func NewVehicleService(repo repositories.VehicleRepo, routes repositories.RouteRepo, cargo repositories.CargoTypeRepo) VehicleService {
+	return &vehicleService{
+		repo:   repo,
+		routes: routes,
+		cargo:  cargo,
+	}
+}
+
+func (s *vehicleService) Create(ctx context.Context, data dto.VehicleRequestBody) (vehicle entities.Vehicle, err error) {
+	vehicle, err = s.toEntity(ctx, data)
+	if err != nil {
+		return entities.Vehicle{}, err
+	}
+
+	vehicle.VehicleID, err = s.repo.Create(ctx, vehicle)
+	if err != nil {
+		return entities.Vehicle{}, fmt.Errorf("creating vehicle: %w", err)
+	}
+
+	return vehicle, nil
+}
+
+func (s *vehicleService) GetById(ctx context.Context, id int) (vehicle entities.Vehicle, err error) {
+	vehicle, err = s.repo.GetById(ctx, id)
+	if err != nil {
+		return entities.Vehicle{}, repoError(fmt.Errorf("getting vehicle: %w", err))
+	}
+
+	return vehicle, nil
+}
+
+func (s *vehicleService) List(ctx context.Context) (vehicles []entities.Vehicle, err error) {
+	vehicles, err = s.repo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing vehicles: %w", err)
+	}
+
+	return vehicles, nil
+}
+
+// Update changes supplied fields of vehicle. Vehicle should still fit all actual routes assigned to it.
+func (s *vehicleService) Update(ctx context.Context, id int, data dto.UpdateVehicleRequestBody) (vehicle entities.Vehicle, err error) {
+	existing, err := s.repo.GetById(ctx, id)
+	if err != nil {
+		return entities.Vehicle{}, repoError(fmt.Errorf("getting vehicle: %w", err))
+	}
+
+	vehicle, err = s.toEntity(ctx, dto.MergeVehicleUpdate(existing, data))
+	if err != nil {
+		return entities.Vehicle{}, err
+	}
+	vehicle.VehicleID = existing.VehicleID
+
+	routes, err := s.assignedRoutes(ctx, id, 0)
+	if err != nil {
+		return entities.Vehicle{}, err
+	}
+	for _, route := range routes {
+		err = vehicle.Fits(route)
+		if err != nil {
+			return entities.Vehicle{}, conflictError(fmt.Errorf("vehicle is assigned to route %d: %w", route.RouteID, err))
+		}
+	}
+
+	err = s.repo.Update(ctx, vehicle)
+	if err != nil {
+		return entities.Vehicle{}, repoError(fmt.Errorf("updating vehicle: %w", err))
+	}
+
+	return vehicle, nil
+}
+
+// Delete removes vehicle unless it is assigned to actual routes.
+func (s *vehicleService) Delete(ctx context.Context, id int) (err error) {
+	routes, err := s.assignedRoutes(ctx, id, 1)
+	if err != nil {
+		return err
+	}
+	if len(routes) > 0 {
+		return conflictError(fmt.Errorf("vehicle %d is assigned to actual routes", id))
+	}
+
+	err = s.repo.Delete(ctx, id)
+	if err != nil {
+		return repoError(fmt.Errorf("deleting vehicle: %w", err))
+	}
+
+	return nil
+}
+
+// toEntity validates vehicle and resolves its cargo types to catalog codes.
+func (s *vehicleService) toEntity(ctx context.Context, data dto.VehicleRequestBody) (vehicle entities.Vehicle, err error) {
+	vehicle, err = dto.ToVehicleModel(data)
+	if err != nil {
+		return entities.Vehicle{}, validationError(fmt.Errorf("converting dto to vehicle: %w", err))
+	}
+
+	cargoTypes, err := s.cargo.List(ctx)
+	if err != nil {
+		return entities.Vehicle{}, fmt.Errorf("loading cargo catalog: %w", err)
+	}
+	catalog := newCargoCatalog(cargoTypes)
+
+	seen := make(map[string]bool, len(vehicle.CargoTypes))
+	codes := make([]string, 0, len(vehicle.CargoTypes))
+	for _, name := range vehicle.CargoTypes {
+		cargoType, ok := catalog.resolve(name)
+		if !ok {
+			return entities.Vehicle{}, validationError(fmt.Errorf("converting dto to vehicle: unknown cargo type %q", name))
+		}
+		if !seen[cargoType.Code] {
+			seen[cargoType.Code] = true
+			codes = append(codes, cargoType.Code)
+		}
+	}
+	vehicle.CargoTypes = codes
+
+	return vehicle, nil
+}
+
+// assignedRoutes returns actual routes assigned to vehicle, at most limit of them if limit is positive.
+func (s *vehicleService) assignedRoutes(ctx context.Context, id int, limit int) (routes []entities.Route, err error) {
+	isActual := true
+	routes, err = s.routes.List(ctx, entities.RouteFilter{VehicleID: &id, IsActual: &isActual, Limit: limit})
+	if err != nil {
+		return nil, fmt.Errorf("listing routes of vehicle: %w", err)
+	}
+
+	return routes, nil
+}
+
+// Assign binds available vehicle to actual route. Vehicle should have enough capacity
+// for load of the route and be allowed to carry its cargo type.
+func (s *routeService) Assign(ctx context.Context, id int, data dto.AssignVehicleRequestBody) (route entities.Route, err error) {
+	if id < 0 {
+		return entities.Route{}, validationError(fmt.Errorf("route id should be non-negative"))
+	}
+	if data.VehicleID <= 0 {
+		return entities.Route{}, validationError(fmt.Errorf("vehicle id should be positive"))
+	}
+
+	route, err = s.repo.GetById(ctx, id)
+	if err != nil {
+		return entities.Route{}, repoError(fmt.Errorf("getting route by id: %w", err))
+	}
+	if !route.IsActual {
+		return entities.Route{}, conflictError(fmt.Errorf("route is not actual"))
+	}
+
+	vehicle, err := s.vehicles.GetById(ctx, data.VehicleID)
+	if err != nil {
+		return entities.Route{}, repoError(fmt.Errorf("getting vehicle: %w", err))
+	}
+	if vehicle.Status != entities.VehicleAvailable {
+		return entities.Route{}, conflictError(fmt.Errorf("vehicle %d is not available, its status is %q", vehicle.VehicleID, vehicle.Status))
+	}
+
+	err = vehicle.Fits(route)
+	if err != nil {
+		return entities.Route{}, conflictError(err)
+	}
+
+	// repository checks capacity again, since route or vehicle may have changed since they were read
+	err = s.repo.Assign(ctx, route.RouteID, vehicle.VehicleID)
+	if errors.Is(err, repositories.ErrVehicleUnfit) {
+		return entities.Route{}, conflictError(err)
+	}
+	if err != nil {
+		return entities.Route{}, repoError(fmt.Errorf("assigning vehicle: %w", err))
+	}
+	route.VehicleID = &vehicle.VehicleID
+
+	return route, nil
+}
+
+// checkAssignedVehicle makes sure that changed route still fits vehicle assigned to it.
+func (s *routeService) checkAssignedVehicle(ctx context.Context, route entities.Route) (err error) {
+	if route.VehicleID == nil {
+		return nil
+	}
+
+	vehicle, err := s.vehicles.GetById(ctx, *route.VehicleID)
+	if err != nil {
+		return fmt.Errorf("getting assigned vehicle: %w", err)
+	}
+
+	err = vehicle.Fits(route)
+	if err != nil {
+		return conflictError(fmt.Errorf("route is assigned to vehicle %d: %w", vehicle.VehicleID, err))
+	}
+
+	return nil
+}
@@ -0,0 +1,404 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"task/internal/dto"
+	"task/internal/entities"
+	"task/internal/mocks"
+	"task/internal/repositories"
+	"task/pkg/decimal"
+	"testing"
+)
+
+var testVehicle = entities.Vehicle{
+	VehicleID:  7,
+	Name:       "Truck",
+	Capacity:   decimal.MustParse("20000"),
+	CargoTypes: []string{"gravel", "sand"},
+	Status:     entities.VehicleAvailable,
+}
+
+func TestCreateVehicle(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	vehicles := mocks.NewMockVehicleRepo(ctrl)
+	svc := NewVehicleService(vehicles, mocks.NewMockRouteRepo(ctrl), newTestCargoRepo(ctrl))
+
+	testCases := []struct {
+		name       string
+		data       dto.VehicleRequestBody
+		expected   entities.Vehicle
+		beforeTest func(vehicles mocks.MockVehicleRepo)
+		wantErr    bool
+		kind       error
+		err        error
+	}{
+		{
+			name: "success",
+			data: dto.VehicleRequestBody{
+				Name:       " Truck ",
+				Capacity:   decimal.MustParse("20000"),
+				CargoTypes: []string{"Gravel", "quartz sand", "sand"},
+			},
+			beforeTest: func(vehicles mocks.MockVehicleRepo) {
+				vehicles.EXPECT().Create(gomock.Any(), entities.Vehicle{
+					Name:       "Truck",
+					Capacity:   decimal.MustParse("20000"),
+					CargoTypes: []string{"gravel", "sand"},
+					Status:     entities.VehicleAvailable,
+				}).Return(7, nil)
+			},
+			expected: testVehicle,
+		},
+		{
+			name:    "capacity is not positive",
+			data:    dto.VehicleRequestBody{Name: "Truck", CargoTypes: []string{"sand"}},
+			wantErr: true,
+			kind:    ErrValidation,
+			err:     fmt.Errorf("converting dto to vehicle: capacity should be positive"),
+		},
+		{
+			name:    "no cargo types",
+			data:    dto.VehicleRequestBody{Name: "Truck", Capacity: decimal.MustParse("1")},
+			wantErr: true,
+			kind:    ErrValidation,
+			err:     fmt.Errorf("converting dto to vehicle: cargo types should not be empty"),
+		},
+		{
+			name:    "unknown status",
+			data:    dto.VehicleRequestBody{Name: "Truck", Capacity: decimal.MustParse("1"), CargoTypes: []string{"sand"}, Status: "lost"},
+			wantErr: true,
+			kind:    ErrValidation,
+			err:     fmt.Errorf(`converting dto to vehicle: unknown vehicle status "lost"`),
+		},
+		{
+			name:    "unknown cargo type",
+			data:    dto.VehicleRequestBody{Name: "Truck", Capacity: decimal.MustParse("1"), CargoTypes: []string{"water"}},
+			wantErr: true,
+			kind:    ErrValidation,
+			err:     fmt.Errorf(`converting dto to vehicle: unknown cargo type "water"`),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.beforeTest != nil {
+				tc.beforeTest(*vehicles)
+			}
+
+			vehicle, err := svc.Create(context.Background(), tc.data)
+
+			if tc.wantErr {
+				require.Equal(t, tc.err.Error(), err.Error())
+				require.ErrorIs(t, err, tc.kind)
+			} else {
+				require.Nil(t, err)
+				require.Equal(t, tc.expected, vehicle)
+			}
+		})
+	}
+}
+
+func TestUpdateVehicle(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	vehicles := mocks.NewMockVehicleRepo(ctrl)
+	routes := mocks.NewMockRouteRepo(ctrl)
+	svc := NewVehicleService(vehicles, routes, newTestCargoRepo(ctrl))
+
+	isActual := true
+	vehicleID := 7
+	assignedFilter := entities.RouteFilter{VehicleID: &vehicleID, IsActual: &isActual}
+	assigned := []entities.Route{{RouteID: 1, Load: decimal.MustParse("15000"), CargoType: "sand", IsActual: true, VehicleID: &vehicleID}}
+
+	capacity := decimal.MustParse("25000")
+	smallCapacity := decimal.MustParse("10000")
+	gravelOnly := []string{"gravel"}
+
+	testCases := []struct {
+		name       string
+		id         int
+		data       dto.UpdateVehicleRequestBody
+		expected   entities.Vehicle
+		beforeTest func(vehicles mocks.MockVehicleRepo)
+		wantErr    bool
+		kind       error
+		err        error
+	}{
+		{
+			name: "success",
+			id:   7,
+			data: dto.UpdateVehicleRequestBody{Capacity: &capacity},
+			beforeTest: func(vehicles mocks.MockVehicleRepo) {
+				vehicles.EXPECT().GetById(gomock.Any(), 7).Return(testVehicle, nil)
+				routes.EXPECT().List(gomock.Any(), assignedFilter).Return(assigned, nil)
+				vehicles.EXPECT().Update(gomock.Any(), entities.Vehicle{
+					VehicleID:  7,
+					Name:       "Truck",
+					Capacity:   capacity,
+					CargoTypes: []string{"gravel", "sand"},
+					Status:     entities.VehicleAvailable,
+				}).Return(nil)
+			},
+			expected: entities.Vehicle{
+				VehicleID:  7,
+				Name:       "Truck",
+				Capacity:   capacity,
+				CargoTypes: []string{"gravel", "sand"},
+				Status:     entities.VehicleAvailable,
+			},
+		},
+		{
+			name: "capacity is less than load of assigned route",
+			id:   7,
+			data: dto.UpdateVehicleRequestBody{Capacity: &smallCapacity},
+			beforeTest: func(vehicles mocks.MockVehicleRepo) {
+				vehicles.EXPECT().GetById(gomock.Any(), 7).Return(testVehicle, nil)
+				routes.EXPECT().List(gomock.Any(), assignedFilter).Return(assigned, nil)
+			},
+			wantErr: true,
+			kind:    ErrConflict,
+			err:     fmt.Errorf("vehicle is assigned to route 1: capacity of vehicle 7 (10000 kg) is less than load of route 1 (15000 kg)"),
+		},
+		{
+			name: "cargo type of assigned route is not allowed",
+			id:   7,
+			data: dto.UpdateVehicleRequestBody{CargoTypes: &gravelOnly},
+			beforeTest: func(vehicles mocks.MockVehicleRepo) {
+				vehicles.EXPECT().GetById(gomock.Any(), 7).Return(testVehicle, nil)
+				routes.EXPECT().List(gomock.Any(), assignedFilter).Return(assigned, nil)
+			},
+			wantErr: true,
+			kind:    ErrConflict,
+			err:     fmt.Errorf(`vehicle is assigned to route 1: vehicle 7 is not allowed to carry cargo type "sand"`),
+		},
+		{
+			name: "vehicle not found",
+			id:   8,
+			data: dto.UpdateVehicleRequestBody{Capacity: &capacity},
+			beforeTest: func(vehicles mocks.MockVehicleRepo) {
+				vehicles.EXPECT().GetById(gomock.Any(), 8).Return(entities.Vehicle{}, fmt.Errorf("getting vehicle by id: %w", repositories.ErrNotFound))
+			},
+			wantErr: true,
+			kind:    ErrNotFound,
+			err:     fmt.Errorf("getting vehicle: getting vehicle by id: no rows in result set"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.beforeTest != nil {
+				tc.beforeTest(*vehicles)
+			}
+
+			vehicle, err := svc.Update(context.Background(), tc.id, tc.data)
+
+			if tc.wantErr {
+				require.Equal(t, tc.err.Error(), err.Error())
+				require.ErrorIs(t, err, tc.kind)
+			} else {
+				require.Nil(t, err)
+				require.Equal(t, tc.expected, vehicle)
+			}
+		})
+	}
+}
+
+func TestDeleteVehicle(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	vehicles := mocks.NewMockVehicleRepo(ctrl)
+	routes := mocks.NewMockRouteRepo(ctrl)
+	svc := NewVehicleService(vehicles, routes, mocks.NewMockCargoTypeRepo(ctrl))
+
+	isActual := true
+	vehicleID := 7
+	assignedFilter := entities.RouteFilter{VehicleID: &vehicleID, IsActual: &isActual, Limit: 1}
+
+	testCases := []struct {
+		name       string
+		id         int
+		beforeTest func(vehicles mocks.MockVehicleRepo)
+		wantErr    bool
+		kind       error
+		err        error
+	}{
+		{
+			name: "success",
+			id:   7,
+			beforeTest: func(vehicles mocks.MockVehicleRepo) {
+				routes.EXPECT().List(gomock.Any(), assignedFilter).Return([]entities.Route{}, nil)
+				vehicles.EXPECT().Delete(gomock.Any(), 7).Return(nil)
+			},
+		},
+		{
+			name: "vehicle is assigned to actual routes",
+			id:   7,
+			beforeTest: func(vehicles mocks.MockVehicleRepo) {
+				routes.EXPECT().List(gomock.Any(), assignedFilter).Return([]entities.Route{{RouteID: 1}}, nil)
+			},
+			wantErr: true,
+			kind:    ErrConflict,
+			err:     fmt.Errorf("vehicle 7 is assigned to actual routes"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.beforeTest != nil {
+				tc.beforeTest(*vehicles)
+			}
+
+			err := svc.Delete(context.Background(), tc.id)
+
+			if tc.wantErr {
+				require.Equal(t, tc.err.Error(), err.Error())
+				require.ErrorIs(t, err, tc.kind)
+			} else {
+				require.Nil(t, err)
+			}
+		})
+	}
+}
+
+func TestAssign(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockRouteRepo(ctrl)
+	vehicles := mocks.NewMockVehicleRepo(ctrl)
+	svc := NewRouteService(repo, mocks.NewMockJobRepo(ctrl), mocks.NewMockCargoTypeRepo(ctrl), vehicles)
+
+	vehicleID := 7
+	route := entities.Route{RouteID: 1, RouteName: "test", Load: decimal.MustParse("12100"), CargoType: "sand", IsActual: true}
+
+	testCases := []struct {
+		name       string
+		id         int
+		data       dto.AssignVehicleRequestBody
+		expected   entities.Route
+		beforeTest func(repo mocks.MockRouteRepo)
+		wantErr    bool
+		kind       error
+		err        error
+	}{
+		{
+			name: "success",
+			id:   1,
+			data: dto.AssignVehicleRequestBody{VehicleID: 7},
+			beforeTest: func(repo mocks.MockRouteRepo) {
+				repo.EXPECT().GetById(gomock.Any(), 1).Return(route, nil)
+				vehicles.EXPECT().GetById(gomock.Any(), 7).Return(testVehicle, nil)
+				repo.EXPECT().Assign(gomock.Any(), 1, 7).Return(nil)
+			},
+			expected: entities.Route{RouteID: 1, RouteName: "test", Load: decimal.MustParse("12100"), CargoType: "sand", IsActual: true, VehicleID: &vehicleID},
+		},
+		{
+			name:    "vehicle id is not positive",
+			id:      1,
+			wantErr: true,
+			kind:    ErrValidation,
+			err:     fmt.Errorf("vehicle id should be positive"),
+		},
+		{
+			name: "load exceeds capacity",
+			id:   1,
+			data: dto.AssignVehicleRequestBody{VehicleID: 7},
+			beforeTest: func(repo mocks.MockRouteRepo) {
+				heavy := route
+				heavy.Load = decimal.MustParse("20000.5")
+				repo.EXPECT().GetById(gomock.Any(), 1).Return(heavy, nil)
+				vehicles.EXPECT().GetById(gomock.Any(), 7).Return(testVehicle, nil)
+			},
+			wantErr: true,
+			kind:    ErrConflict,
+			err:     fmt.Errorf("capacity of vehicle 7 (20000 kg) is less than load of route 1 (20000.5 kg)"),
+		},
+		{
+			name: "cargo type is not allowed",
+			id:   1,
+			data: dto.AssignVehicleRequestBody{VehicleID: 7},
+			beforeTest: func(repo mocks.MockRouteRepo) {
+				diesel := route
+				diesel.CargoType = "diesel"
+				repo.EXPECT().GetById(gomock.Any(), 1).Return(diesel, nil)
+				vehicles.EXPECT().GetById(gomock.Any(), 7).Return(testVehicle, nil)
+			},
+			wantErr: true,
+			kind:    ErrConflict,
+			err:     fmt.Errorf(`vehicle 7 is not allowed to carry cargo type "diesel"`),
+		},
+		{
+			name: "vehicle is not available",
+			id:   1,
+			data: dto.AssignVehicleRequestBody{VehicleID: 7},
+			beforeTest: func(repo mocks.MockRouteRepo) {
+				inMaintenance := testVehicle
+				inMaintenance.Status = entities.VehicleMaintenance
+				repo.EXPECT().GetById(gomock.Any(), 1).Return(route, nil)
+				vehicles.EXPECT().GetById(gomock.Any(), 7).Return(inMaintenance, nil)
+			},
+			wantErr: true,
+			kind:    ErrConflict,
+			err:     fmt.Errorf(`vehicle 7 is not available, its status is "maintenance"`),
+		},
+		{
+			name: "vehicle changed after check",
+			id:   1,
+			data: dto.AssignVehicleRequestBody{VehicleID: 7},
+			beforeTest: func(repo mocks.MockRouteRepo) {
+				repo.EXPECT().GetById(gomock.Any(), 1).Return(route, nil)
+				vehicles.EXPECT().GetById(gomock.Any(), 7).Return(testVehicle, nil)
+				repo.EXPECT().Assign(gomock.Any(), 1, 7).Return(fmt.Errorf("assigning vehicle: %w", repositories.ErrVehicleUnfit))
+			},
+			wantErr: true,
+			kind:    ErrConflict,
+			err:     fmt.Errorf("assigning vehicle: vehicle is not available or can not carry load of the route"),
+		},
+		{
+			name: "route is not actual",
+			id:   1,
+			data: dto.AssignVehicleRequestBody{VehicleID: 7},
+			beforeTest: func(repo mocks.MockRouteRepo) {
+				notActual := route
+				notActual.IsActual = false
+				repo.EXPECT().GetById(gomock.Any(), 1).Return(notActual, nil)
+			},
+			wantErr: true,
+			kind:    ErrConflict,
+			err:     fmt.Errorf("route is not actual"),
+		},
+		{
+			name: "vehicle not found",
+			id:   1,
+			data: dto.AssignVehicleRequestBody{VehicleID: 8},
+			beforeTest: func(repo mocks.MockRouteRepo) {
+				repo.EXPECT().GetById(gomock.Any(), 1).Return(route, nil)
+				vehicles.EXPECT().GetById(gomock.Any(), 8).Return(entities.Vehicle{}, fmt.Errorf("getting vehicle by id: %w", repositories.ErrNotFound))
+			},
+			wantErr: true,
+			kind:    ErrNotFound,
+			err:     fmt.Errorf("getting vehicle: getting vehicle by id: no rows in result set"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.beforeTest != nil {
+				tc.beforeTest(*repo)
+			}
+
+			route, err := svc.Assign(context.Background(), tc.id, tc.data)
+
+			if tc.wantErr {
+				require.Equal(t, tc.err.Error(), err.Error())
+				require.ErrorIs(t, err, tc.kind)
+			} else {
+				require.Nil(t, err)
+				require.Equal(t, tc.expected, route)
+			}
+		})
+	}
+}
@@ -0,0 +1 @@
+drop table route_versions;
\ No newline at end of file
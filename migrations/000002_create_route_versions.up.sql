@@ -0,0 +1,18 @@
+create table if not exists route_versions(
+    version_id bigserial primary key,
+    route_id int not null,
+    route_name varchar(128) not null,
+    load float not null,
+    cargo_type varchar(64) not null,
+    created_at timestamptz not null default now(),
+    superseded_by bigint references route_versions(version_id),
+    superseded_at timestamptz
+);
+
+create index if not exists route_versions_route_id_idx on route_versions(route_id);
+create index if not exists route_versions_superseded_by_idx on route_versions(superseded_by);
+
+insert into route_versions(route_id, route_name, load, cargo_type)
+select route_id, route_name, load, cargo_type
+from routes
+order by route_id;
@@ -0,0 +1,2 @@
+drop table delete_job_items;
+drop table delete_jobs;
\ No newline at end of file
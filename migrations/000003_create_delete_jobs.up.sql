@@ -0,0 +1,15 @@
+create table if not exists delete_jobs(
+    job_id bigserial primary key,
+    status varchar(16) not null default 'pending',
+    attempts int not null default 0,
+    error text,
+    created_at timestamptz not null default now(),
+    updated_at timestamptz not null default now()
+);
+
+create table if not exists delete_job_items(
+    job_id bigint not null references delete_jobs(job_id) on delete cascade,
+    route_id int not null,
+    status varchar(16) not null default 'pending',
+    primary key (job_id, route_id)
+);
@@ -0,0 +1,5 @@
+alter table route_versions
+    drop column waypoints;
+
+alter table routes
+    drop column waypoints;
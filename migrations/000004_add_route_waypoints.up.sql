@@ -0,0 +1,5 @@
+alter table routes
+    add column if not exists waypoints jsonb not null default '[]';
+
+alter table route_versions
+    add column if not exists waypoints jsonb not null default '[]';
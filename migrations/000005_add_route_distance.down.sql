@@ -0,0 +1,3 @@
+alter table routes
+    drop column duration_s,
+    drop column distance_m;
@@ -0,0 +1,3 @@
+alter table routes
+    add column if not exists distance_m double precision not null default 0,
+    add column if not exists duration_s bigint not null default 0;
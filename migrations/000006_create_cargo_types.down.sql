@@ -0,0 +1,2 @@
+drop table cargo_type_aliases;
+drop table cargo_types;
@@ -0,0 +1,12 @@
+update route_versions v
+set load = v.load / 1000
+from cargo_types c
+where c.code = lower(btrim(v.cargo_type)) and c.unit = 't';
+
+update routes r
+set load = r.load / 1000
+from cargo_types c
+where c.code = r.cargo_type and c.unit = 't';
+
+alter table cargo_types
+    drop column density;
@@ -0,0 +1,34 @@
+-- loads were stored in default unit of cargo type, now they are stored in kilograms.
+-- Loads of cargo measured in volume can not be converted, since density of cargo types is not known
+-- yet, so migration stops rather than leaving them in cubic metres or litres.
+do $$
+declare
+    volume_cargo text;
+begin
+    select string_agg(c.code, ', ' order by c.code)
+    into volume_cargo
+    from cargo_types c
+    where c.unit in ('m3', 'l') and (
+        exists (select 1 from routes r where r.cargo_type = c.code)
+        or exists (select 1 from route_versions v where lower(btrim(v.cargo_type)) = c.code)
+    );
+
+    if volume_cargo is not null then
+        raise exception 'loads of cargo types % are measured in volume and can not be converted to kilograms', volume_cargo
+            using hint = 'convert loads of these routes and their versions to tonnes, set unit of the cargo types to ''t'', reset schema_migrations to clean version 6 and run migration again';
+    end if;
+end $$;
+
+alter table cargo_types
+    add column if not exists density double precision not null default 0;
+
+update routes r
+set load = r.load * 1000
+from cargo_types c
+where c.code = r.cargo_type and c.unit = 't';
+
+-- cargo types of history were not normalized by 000006
+update route_versions v
+set load = v.load * 1000
+from cargo_types c
+where c.code = lower(btrim(v.cargo_type)) and c.unit = 't';
@@ -0,0 +1,5 @@
+alter table route_versions
+    alter column load type float using load::float;
+
+alter table routes
+    alter column load type float using load::float;
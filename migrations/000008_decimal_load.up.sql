@@ -0,0 +1,9 @@
+-- loads were written from float32 values, so only 7 significant digits of them are meaningful.
+-- They are rounded to these digits, but not finer than grams, default precision of loads.
+alter table routes
+    alter column load type numeric
+    using case when load = 0 then 0 else round(load::numeric, least(3, 6 - floor(log(abs(load))))::int) end;
+
+alter table route_versions
+    alter column load type numeric
+    using case when load = 0 then 0 else round(load::numeric, least(3, 6 - floor(log(abs(load))))::int) end;
@@ -0,0 +1,5 @@
+alter table routes
+    drop column vehicle_id;
+
+drop table vehicle_cargo_types;
+drop table vehicles;
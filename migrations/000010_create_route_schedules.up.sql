@@ -0,0 +1,12 @@
+-- windows are stored as seconds from midnight of the trip day in the schedule time zone
+create table if not exists route_schedules(
+    route_id int primary key references routes(route_id) on delete cascade,
+    departure_from int not null,
+    departure_to int not null,
+    arrival_from int not null,
+    arrival_to int not null,
+    recurrence varchar(128) not null,
+    timezone varchar(64) not null default 'UTC',
+    valid_from date,
+    valid_until date
+);
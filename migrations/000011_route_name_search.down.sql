@@ -0,0 +1,7 @@
+drop index if exists routes_route_name_trgm_idx;
+drop index if exists routes_route_name_tsv_idx;
+
+alter table routes
+    drop column if exists route_name_tsv;
+
+-- pg_trgm is left installed, other objects of the database may use it
@@ -0,0 +1,9 @@
+create extension if not exists pg_trgm;
+
+-- 'simple' configuration keeps words as they are, route names are not in one language
+alter table routes
+    add column if not exists route_name_tsv tsvector
+        generated always as (to_tsvector('simple', route_name)) stored;
+
+create index if not exists routes_route_name_tsv_idx on routes using gin(route_name_tsv);
+create index if not exists routes_route_name_trgm_idx on routes using gin(route_name gin_trgm_ops);
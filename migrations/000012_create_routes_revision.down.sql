@@ -0,0 +1 @@
+drop sequence if exists routes_revision;
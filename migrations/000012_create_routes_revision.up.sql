@@ -0,0 +1,4 @@
+-- revision of route geometry: the application takes the next value after committing routes or
+-- changes of their waypoints, so servers find out their spatial index is stale whichever of them
+-- made the change; a sequence never blocks writers and is not rolled back
+create sequence if not exists routes_revision;
@@ -0,0 +1,25 @@
+// Package migrations embeds SQL migrations, so the service binary applies them itself.
+// Migrations of PostgreSQL schema are in the root of the package, SQLite ones are in sqlite directory.
+package migrations
+
+import (
+	"embed"
+	"github.com/golang-migrate/migrate/v4/source"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+)
+
+//go:embed *.sql
+var postgresFS embed.FS
+
+//go:embed sqlite/*.sql
+var sqliteFS embed.FS
+
+// Postgres returns migrate source of PostgreSQL migrations.
+func Postgres() (source.Driver, error) {
+	return iofs.New(postgresFS, ".")
+}
+
+// SQLite returns migrate source of SQLite migrations.
+func SQLite() (source.Driver, error) {
+	return iofs.New(sqliteFS, "sqlite")
+}
@@ -0,0 +1,8 @@
+-- SQLite has no exact numeric type, so loads are stored as decimal strings
+create table if not exists routes(
+    route_id integer primary key,
+    route_name varchar(128) not null check(length(route_name) <= 128),
+    load text not null,
+    cargo_type varchar(64) not null check(length(cargo_type) <= 64),
+    is_actual boolean not null default true
+);
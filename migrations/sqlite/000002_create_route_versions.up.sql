@@ -0,0 +1,19 @@
+-- timestamps are stored as RFC 3339 strings in UTC, so they are ordered as text
+create table if not exists route_versions(
+    version_id integer primary key autoincrement,
+    route_id int not null,
+    route_name varchar(128) not null,
+    load text not null,
+    cargo_type varchar(64) not null,
+    created_at text not null default (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    superseded_by integer references route_versions(version_id),
+    superseded_at text
+);
+
+create index if not exists route_versions_route_id_idx on route_versions(route_id);
+create index if not exists route_versions_superseded_by_idx on route_versions(superseded_by);
+
+insert into route_versions(route_id, route_name, load, cargo_type)
+select route_id, route_name, load, cargo_type
+from routes
+order by route_id;
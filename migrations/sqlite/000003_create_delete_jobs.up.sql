@@ -0,0 +1,15 @@
+create table if not exists delete_jobs(
+    job_id integer primary key autoincrement,
+    status varchar(16) not null default 'pending',
+    attempts int not null default 0,
+    error text,
+    created_at text not null default (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    updated_at text not null default (strftime('%Y-%m-%dT%H:%M:%fZ', 'now'))
+);
+
+create table if not exists delete_job_items(
+    job_id integer not null references delete_jobs(job_id) on delete cascade,
+    route_id int not null,
+    status varchar(16) not null default 'pending',
+    primary key (job_id, route_id)
+);
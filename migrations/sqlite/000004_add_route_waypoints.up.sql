@@ -0,0 +1,5 @@
+alter table routes
+    add column waypoints text not null default '[]';
+
+alter table route_versions
+    add column waypoints text not null default '[]';
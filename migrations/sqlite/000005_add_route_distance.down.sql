@@ -0,0 +1,5 @@
+alter table routes
+    drop column duration_s;
+
+alter table routes
+    drop column distance_m;
@@ -0,0 +1,5 @@
+alter table routes
+    add column distance_m double precision not null default 0;
+
+alter table routes
+    add column duration_s bigint not null default 0;
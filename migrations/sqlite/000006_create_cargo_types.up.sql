@@ -0,0 +1,25 @@
+create table if not exists cargo_types(
+    code varchar(64) primary key,
+    display_name varchar(128) not null,
+    unit varchar(8) not null,
+    hazard_class varchar(8) not null default ''
+);
+
+create table if not exists cargo_type_aliases(
+    alias varchar(64) primary key,
+    code varchar(64) not null references cargo_types(code) on delete cascade
+);
+
+create index if not exists cargo_type_aliases_code_idx on cargo_type_aliases(code);
+
+-- cargo types were free-form before, so existing values are normalized and become catalog entries
+update routes
+set cargo_type = lower(trim(cargo_type))
+where cargo_type <> lower(trim(cargo_type));
+
+insert into cargo_types(code, display_name, unit)
+select distinct cargo_type, cargo_type, 't'
+from routes
+-- where clause resolves parsing ambiguity of upsert with select
+where true
+on conflict do nothing;
@@ -0,0 +1,2 @@
+alter table cargo_types
+    drop column density;
@@ -0,0 +1,3 @@
+-- loads of SQLite databases have always been stored in kilograms, so only density is added
+alter table cargo_types
+    add column density double precision not null default 0;
@@ -0,0 +1,2 @@
+-- loads of SQLite databases have always been stored as decimal strings, see 000001
+select 1;
@@ -0,0 +1,23 @@
+-- SQLite can not drop column referencing other table, so routes are copied without it
+drop index if exists routes_vehicle_id_idx;
+
+create table routes_without_vehicle(
+    route_id integer primary key,
+    route_name varchar(128) not null check(length(route_name) <= 128),
+    load text not null,
+    cargo_type varchar(64) not null check(length(cargo_type) <= 64),
+    is_actual boolean not null default true,
+    waypoints text not null default '[]',
+    distance_m double precision not null default 0,
+    duration_s bigint not null default 0
+);
+
+insert into routes_without_vehicle(route_id, route_name, load, cargo_type, is_actual, waypoints, distance_m, duration_s)
+select route_id, route_name, load, cargo_type, is_actual, waypoints, distance_m, duration_s
+from routes;
+
+drop table routes;
+alter table routes_without_vehicle rename to routes;
+
+drop table vehicle_cargo_types;
+drop table vehicles;
@@ -0,0 +1,19 @@
+create table if not exists vehicles(
+    vehicle_id integer primary key autoincrement,
+    name varchar(128) not null,
+    capacity text not null,
+    status varchar(16) not null default 'available'
+);
+
+create table if not exists vehicle_cargo_types(
+    vehicle_id integer not null references vehicles(vehicle_id) on delete cascade,
+    code varchar(64) not null references cargo_types(code) on delete cascade,
+    primary key (vehicle_id, code)
+);
+
+create index if not exists vehicle_cargo_types_code_idx on vehicle_cargo_types(code);
+
+alter table routes
+    add column vehicle_id integer references vehicles(vehicle_id) on delete set null;
+
+create index if not exists routes_vehicle_id_idx on routes(vehicle_id);
@@ -0,0 +1 @@
+drop table route_schedules;
@@ -0,0 +1,13 @@
+-- windows are stored as seconds from midnight of the trip day in the schedule time zone,
+-- dates of validity period as YYYY-MM-DD strings
+create table if not exists route_schedules(
+    route_id integer primary key references routes(route_id) on delete cascade,
+    departure_from int not null,
+    departure_to int not null,
+    arrival_from int not null,
+    arrival_to int not null,
+    recurrence varchar(128) not null,
+    timezone varchar(64) not null default 'UTC',
+    valid_from text,
+    valid_until text
+);
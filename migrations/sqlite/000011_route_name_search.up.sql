@@ -0,0 +1,2 @@
+-- SQLite has no trigram similarity, route names are ranked by the application
+select 1;
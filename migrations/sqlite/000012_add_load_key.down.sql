@@ -0,0 +1,4 @@
+drop index if exists routes_load_key_idx;
+
+alter table routes
+    drop column load_key;
@@ -0,0 +1,10 @@
+-- load_key orders as the decimal load does: 19 integer and 18 fractional digits, zero padded,
+-- so load filters and limit run in SQL; loads of routes are positive
+alter table routes
+    add column load_key text
+        generated always as (
+            substr('0000000000000000000' || substr(load, 1, instr(load || '.', '.') - 1), -19)
+                || substr(substr(load, instr(load || '.', '.') + 1) || '000000000000000000', 1, 18)
+        ) virtual;
+
+create index if not exists routes_load_key_idx on routes(load_key);
@@ -0,0 +1,189 @@
+// Package client is a Go client of the route API served under /api/route.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	defaultMaxRetries = 3
+	defaultMinBackoff = 100 * time.Millisecond
+	defaultMaxBackoff = 2 * time.Second
+)
+
+// Client calls route API of one server. It is safe for concurrent use.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	maxRetries int
+	minBackoff time.Duration
+	maxBackoff time.Duration
+}
+
+// Option configures client.
+type Option func(c *Client)
+
+// WithHTTPClient sets HTTP client used to send requests, http.DefaultClient by default.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithRetries sets number of retries of GET requests failed with network errors
+// or temporary server errors. Delay before retry starts with minBackoff and doubles up to maxBackoff.
+func WithRetries(maxRetries int, minBackoff, maxBackoff time.Duration) Option {
+	return func(c *Client) {
+		c.maxRetries = maxRetries
+		c.minBackoff = minBackoff
+		c.maxBackoff = maxBackoff
+	}
+}
+
+// New returns client of server at baseURL, e.g. "http://localhost:8080".
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:    strings.TrimRight(baseURL, "/") + "/api/route",
+		httpClient: http.DefaultClient,
+		maxRetries: defaultMaxRetries,
+		minBackoff: defaultMinBackoff,
+		maxBackoff: defaultMaxBackoff,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// request describes API call. Body is either JSON value in body, or raw stream in reader,
+// which can not be replayed, so such requests are never retried.
+type request struct {
+	method string
+	path   string
+	query  url.Values
+	body   any
+	reader io.Reader
+}
+
+// do sends request and decodes data of success response into dst, if dst is not nil.
+func (c *Client) do(ctx context.Context, req request, dst any) error {
+	resp, err := c.send(ctx, req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var success struct {
+		Data json.RawMessage `json:"data"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&success)
+	if err != nil {
+		return fmt.Errorf("decoding response: %w", err)
+	}
+	if dst == nil || len(success.Data) == 0 {
+		return nil
+	}
+
+	err = json.Unmarshal(success.Data, dst)
+	if err != nil {
+		return fmt.Errorf("decoding response: %w", err)
+	}
+
+	return nil
+}
+
+// send returns response with successful status, error responses are returned as *Error.
+// GET requests are retried on network errors and temporary server errors.
+func (c *Client) send(ctx context.Context, req request) (*http.Response, error) {
+	target := c.baseURL + req.path
+	if len(req.query) > 0 {
+		target += "?" + req.query.Encode()
+	}
+
+	var body []byte
+	if req.body != nil {
+		var err error
+		body, err = json.Marshal(req.body)
+		if err != nil {
+			return nil, fmt.Errorf("encoding request: %w", err)
+		}
+	}
+
+	retries := 0
+	if req.reader == nil && retryable(req.method) {
+		retries = c.maxRetries
+	}
+
+	backoff := c.minBackoff
+	for attempt := 0; ; attempt++ {
+		resp, err := c.sendOnce(ctx, req, target, body)
+		if err == nil || attempt >= retries || !temporary(err) || ctx.Err() != nil {
+			return resp, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, err
+		case <-time.After(backoff):
+		}
+
+		backoff = min(backoff*2, c.maxBackoff)
+	}
+}
+
+func (c *Client) sendOnce(ctx context.Context, req request, target string, body []byte) (*http.Response, error) {
+	reader := req.reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, req.method, target, reader)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+	if body != nil {
+		httpReq.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("sending request: %w", err)
+	}
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		defer resp.Body.Close()
+		return nil, responseError(resp)
+	}
+
+	return resp, nil
+}
+
+// retryable reports if request may be sent again when its response was lost.
+// Only reads are: repeated PATCH saves one more route version and repeated DELETE starts one more job.
+func retryable(method string) bool {
+	return method == http.MethodGet
+}
+
+// temporary reports if request failed with network error or with server error which may go away on retry.
+func temporary(err error) bool {
+	apiErr, ok := err.(*Error)
+	if !ok {
+		return true
+	}
+
+	switch apiErr.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
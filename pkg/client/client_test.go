@@ -0,0 +1,156 @@
+package client
+
+import (
+	"context"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"task/internal/app"
+	"task/internal/delivery"
+	"task/internal/entities"
+	"task/internal/mocks"
+	"task/internal/repositories"
+	"task/internal/services"
+	"task/pkg/decimal"
+	"task/pkg/dto"
+	"testing"
+	"time"
+)
+
+func newTestServer(t *testing.T) *httptest.Server {
+	ctrl := gomock.NewController(t)
+
+	sand := entities.CargoType{Code: "sand", DisplayName: "Sand", Unit: entities.UnitKilogram}
+	cargo := mocks.NewMockCargoTypeRepo(ctrl)
+	cargo.EXPECT().List(gomock.Any()).Return([]entities.CargoType{sand}, nil).AnyTimes()
+	cargo.EXPECT().GetByCode(gomock.Any(), "sand").Return(sand, nil).AnyTimes()
+
+	a := &app.App{
+		Svc: services.NewRouteService(
+			repositories.NewMemoryRouteRepo(),
+			mocks.NewMockJobRepo(ctrl),
+			cargo,
+			mocks.NewMockVehicleRepo(ctrl),
+		),
+	}
+
+	router := chi.NewRouter()
+	router.Route("/api/route", func(r chi.Router) {
+		r.Get("/", delivery.ListHandler(a))
+		r.Post("/register", delivery.RegisterHandler(a))
+		r.Get("/{id}", delivery.GetHandler(a))
+		r.Patch("/{id}", delivery.UpdateHandler(a))
+	})
+
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestClient(t *testing.T) {
+	ctx := context.Background()
+	c := New(newTestServer(t).URL)
+
+	route := dto.RegisterRouteRequestBody{
+		RouteID:   1,
+		RouteName: "Moscow - Tver",
+		Load:      decimal.MustParse("1.5"),
+		Unit:      entities.UnitTonne,
+		CargoType: "sand",
+		Waypoints: []dto.WaypointBody{{Lat: 55.7558, Lon: 37.6173}, {Lat: 56.8587, Lon: 35.9176}},
+	}
+
+	id, err := c.Register(ctx, route)
+	require.NoError(t, err)
+	require.Equal(t, 1, id)
+
+	id, err = c.Register(ctx, route)
+	require.NoError(t, err)
+	require.Equal(t, 2, id)
+
+	// registering taken id replaces the route
+	_, err = c.Get(ctx, 1, "")
+	require.ErrorIs(t, err, ErrGone)
+
+	got, err := c.Get(ctx, 2, entities.UnitTonne)
+	require.NoError(t, err)
+	require.Equal(t, 2, got.RouteID)
+	require.Equal(t, "Moscow - Tver", got.RouteName)
+	require.Equal(t, decimal.MustParse("1.5"), got.Load)
+	require.Equal(t, entities.UnitTonne, got.Unit)
+
+	name := "Tver - Moscow"
+	updated, err := c.Update(ctx, 2, dto.UpdateRouteRequestBody{RouteName: &name})
+	require.NoError(t, err)
+	require.Equal(t, name, updated.RouteName)
+
+	page, err := c.List(ctx, dto.ListRoutesRequest{Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, page.Routes, 1)
+	require.NotEmpty(t, page.NextCursor)
+
+	page, err = c.List(ctx, dto.ListRoutesRequest{Cursor: page.NextCursor})
+	require.NoError(t, err)
+	require.Len(t, page.Routes, 1)
+	require.Equal(t, 2, page.Routes[0].RouteID)
+
+	_, err = c.Get(ctx, 42, "")
+	require.ErrorIs(t, err, ErrNotFound)
+	var apiErr *Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+	require.Equal(t, CodeNotFound, apiErr.Code)
+
+	route.CargoType = "ore"
+	_, err = c.Register(ctx, route)
+	require.ErrorIs(t, err, ErrValidation)
+	require.EqualError(t, err, `register handler: converting dto to entity model: unknown cargo type "ore" (validation_failed)`)
+}
+
+func TestRetries(t *testing.T) {
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if attempts.Add(1) < 3 {
+			http.Error(w, "try later", http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"status": "success", "data": {"job_id": 7, "status": "succeeded", "attempts": 1, "items": []}}`))
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	c := New(server.URL, WithRetries(2, time.Millisecond, time.Millisecond))
+
+	job, err := c.DeleteJob(ctx, 7)
+	require.NoError(t, err)
+	require.Equal(t, "succeeded", job.Status)
+	require.EqualValues(t, 3, attempts.Load())
+
+	// register, update and delete are not idempotent
+	attempts.Store(0)
+	_, err = c.Register(ctx, dto.RegisterRouteRequestBody{})
+	require.ErrorIs(t, err, ErrInternal)
+	require.EqualValues(t, 1, attempts.Load())
+
+	attempts.Store(0)
+	name := "Tver - Moscow"
+	_, err = c.Update(ctx, 1, dto.UpdateRouteRequestBody{RouteName: &name})
+	require.ErrorIs(t, err, ErrInternal)
+	require.EqualValues(t, 1, attempts.Load())
+
+	attempts.Store(0)
+	_, err = c.Delete(ctx, []int{1})
+	require.ErrorIs(t, err, ErrInternal)
+	require.EqualValues(t, 1, attempts.Load())
+
+	// retries are exhausted
+	attempts.Store(-10)
+	_, err = c.DeleteJob(ctx, 7)
+	var apiErr *Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusServiceUnavailable, apiErr.StatusCode)
+	require.EqualValues(t, -7, attempts.Load())
+}
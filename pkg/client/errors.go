@@ -0,0 +1,68 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Error codes of server error responses.
+const (
+	CodeBadRequest = "bad_request"
+	CodeValidation = "validation_failed"
+	CodeNotFound   = "not_found"
+	CodeConflict   = "conflict"
+	CodeGone       = "gone"
+	CodeInternal   = "internal_error"
+)
+
+// Error kinds matching error codes. Check them with errors.Is.
+var (
+	ErrBadRequest = errors.New("bad request")
+	ErrValidation = errors.New("validation failed")
+	ErrNotFound   = errors.New("not found")
+	ErrConflict   = errors.New("conflict")
+	ErrGone       = errors.New("gone")
+	ErrInternal   = errors.New("internal error")
+)
+
+var errorKinds = map[string]error{
+	CodeBadRequest: ErrBadRequest,
+	CodeValidation: ErrValidation,
+	CodeNotFound:   ErrNotFound,
+	CodeConflict:   ErrConflict,
+	CodeGone:       ErrGone,
+	CodeInternal:   ErrInternal,
+}
+
+// Error is error response of the server.
+type Error struct {
+	StatusCode int
+	Code       string
+	Message    string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s (%s)", e.Message, e.Code)
+}
+
+// Is matches e with error kind of its code.
+func (e *Error) Is(target error) bool {
+	return errorKinds[e.Code] == target
+}
+
+// responseError reads error response. Responses without JSON body, e.g. from proxies,
+// get internal error code and status text as the message.
+func responseError(resp *http.Response) *Error {
+	var body struct {
+		Code  string `json:"code"`
+		Error string `json:"error"`
+	}
+	err := json.NewDecoder(resp.Body).Decode(&body)
+	if err != nil || body.Code == "" {
+		return &Error{StatusCode: resp.StatusCode, Code: CodeInternal, Message: resp.Status}
+	}
+
+	return &Error{StatusCode: resp.StatusCode, Code: body.Code, Message: body.Error}
+}
@@ -0,0 +1,147 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"task/pkg/dto"
+)
+
+// Register registers route and returns its id. It differs from requested one if that was taken.
+//
+// Only Get, List, DeleteJob and Export are retried, see WithRetries. Register, Update, Delete and Import
+// are sent once, so a request lost on the way back can not register the route twice, save one more
+// version of it or start one more delete job.
+func (c *Client) Register(ctx context.Context, route dto.RegisterRouteRequestBody) (int, error) {
+	var resp struct {
+		RouteID *int `json:"route_id"`
+	}
+	err := c.do(ctx, request{method: http.MethodPost, path: "/register", body: route}, &resp)
+	if err != nil {
+		return 0, err
+	}
+
+	// route id is returned only if the requested one was taken
+	if resp.RouteID != nil {
+		return *resp.RouteID, nil
+	}
+	return route.RouteID, nil
+}
+
+// Get returns actual route with load converted to unit, empty unit means canonical one.
+// Deleted routes fail with ErrGone.
+func (c *Client) Get(ctx context.Context, id int, unit string) (dto.RouteResponseBody, error) {
+	query := url.Values{}
+	if unit != "" {
+		query.Set("unit", unit)
+	}
+
+	var route dto.RouteResponseBody
+	err := c.do(ctx, request{method: http.MethodGet, path: "/" + strconv.Itoa(id), query: query}, &route)
+	if err != nil {
+		return dto.RouteResponseBody{}, err
+	}
+
+	// server returns only actual routes and omits their id
+	route.RouteID = id
+	route.IsActual = true
+	return route, nil
+}
+
+// Update changes supplied fields of the route and returns its new state.
+func (c *Client) Update(ctx context.Context, id int, update dto.UpdateRouteRequestBody) (route dto.RouteResponseBody, err error) {
+	err = c.do(ctx, request{method: http.MethodPatch, path: "/" + strconv.Itoa(id), body: update}, &route)
+	return route, err
+}
+
+// Delete starts background job deleting routes and returns its id, see DeleteJob.
+func (c *Client) Delete(ctx context.Context, ids []int) (int64, error) {
+	var resp struct {
+		JobID int64 `json:"job_id"`
+	}
+	err := c.do(ctx, request{method: http.MethodDelete, body: ids}, &resp)
+	if err != nil {
+		return 0, err
+	}
+
+	return resp.JobID, nil
+}
+
+// DeleteJob returns state of delete job.
+func (c *Client) DeleteJob(ctx context.Context, id int64) (job dto.DeleteJobResponseBody, err error) {
+	err = c.do(ctx, request{method: http.MethodGet, path: "/jobs/" + strconv.FormatInt(id, 10)}, &job)
+	return job, err
+}
+
+// List returns page of routes matching the request. Next page is requested with NextCursor of the response.
+func (c *Client) List(ctx context.Context, req dto.ListRoutesRequest) (resp dto.ListRoutesResponseBody, err error) {
+	err = c.do(ctx, request{method: http.MethodGet, path: "/", query: listQuery(req)}, &resp)
+	return resp, err
+}
+
+// Import registers routes read from r in format dto.FormatCSV or dto.FormatJSONL.
+// Rows failed validation are listed in the report instead of failing the whole import.
+func (c *Client) Import(ctx context.Context, r io.Reader, format string) (report dto.ImportReportResponseBody, err error) {
+	query := url.Values{}
+	if format != "" {
+		query.Set("format", format)
+	}
+
+	err = c.do(ctx, request{method: http.MethodPost, path: "/import", query: query, reader: r}, &report)
+	return report, err
+}
+
+// Export writes all routes matching the request to w in given format, limit of the request is ignored.
+func (c *Client) Export(ctx context.Context, req dto.ListRoutesRequest, format string, w io.Writer) error {
+	query := listQuery(req)
+	query.Del("limit")
+	if format != "" {
+		query.Set("format", format)
+	}
+
+	resp, err := c.send(ctx, request{method: http.MethodGet, path: "/export", query: query})
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	_, err = io.Copy(w, resp.Body)
+	if err != nil {
+		return fmt.Errorf("reading export: %w", err)
+	}
+
+	return nil
+}
+
+func listQuery(req dto.ListRoutesRequest) url.Values {
+	query := url.Values{}
+	if req.Cursor != "" {
+		query.Set("cursor", req.Cursor)
+	}
+	if req.Limit != 0 {
+		query.Set("limit", strconv.Itoa(req.Limit))
+	}
+	if req.CargoType != "" {
+		query.Set("cargo_type", req.CargoType)
+	}
+	if req.IsActual != nil {
+		query.Set("is_actual", strconv.FormatBool(*req.IsActual))
+	}
+	if req.MinLoad != nil {
+		query.Set("min_load", req.MinLoad.String())
+	}
+	if req.MaxLoad != nil {
+		query.Set("max_load", req.MaxLoad.String())
+	}
+	if req.NamePrefix != "" {
+		query.Set("name_prefix", req.NamePrefix)
+	}
+	if req.VehicleID != nil {
+		query.Set("vehicle_id", strconv.Itoa(*req.VehicleID))
+	}
+
+	return query
+}
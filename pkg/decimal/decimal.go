@@ -0,0 +1,302 @@
+// Package decimal implements exact fixed-point numbers used for loads and capacities.
+package decimal
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// MaxScale limits number of fractional digits of Decimal.
+const MaxScale = 18
+
+// maxDigits is number of digits of the largest int64.
+const maxDigits = 19
+
+// ErrOverflow is returned if result does not fit into Decimal.
+var ErrOverflow = errors.New("decimal overflow")
+
+// Decimal is an exact fixed-point number coef * 10^-scale. It is kept normalized,
+// without trailing zeros in fractional part, so equal numbers are equal with ==.
+// Zero value is 0.
+type Decimal struct {
+	coef  int64
+	scale int32
+}
+
+// New returns coef * 10^-scale.
+func New(coef int64, scale int32) Decimal {
+	d, err := newBig(big.NewInt(coef), scale)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+// FromFloat returns decimal with the shortest representation of f.
+func FromFloat(f float64) (Decimal, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return Decimal{}, fmt.Errorf("%v is not a finite number", f)
+	}
+	return Parse(strconv.FormatFloat(f, 'g', -1, 64))
+}
+
+// Parse parses decimal number like "12.1", "-3" or "1.5e3".
+func Parse(s string) (Decimal, error) {
+	mantissa, exp := s, int64(0)
+	if i := strings.IndexAny(s, "eE"); i >= 0 {
+		var err error
+		mantissa = s[:i]
+		exp, err = strconv.ParseInt(s[i+1:], 10, 32)
+		if err != nil {
+			return Decimal{}, fmt.Errorf("invalid decimal %q", s)
+		}
+	}
+
+	intPart, fracPart, _ := strings.Cut(mantissa, ".")
+	digits := strings.TrimLeft(intPart, "+-")
+	if len(intPart)-len(digits) > 1 || digits+fracPart == "" || !isDigits(digits) || !isDigits(fracPart) {
+		return Decimal{}, fmt.Errorf("invalid decimal %q", s)
+	}
+
+	// zeros which do not change the value are dropped before the number is built
+	digits = strings.TrimLeft(digits, "0")
+	fracPart = strings.TrimRight(fracPart, "0")
+	if len(digits)+len(fracPart) > maxDigits {
+		return Decimal{}, fmt.Errorf("decimal %q is out of range", s)
+	}
+	if digits+fracPart == "" {
+		return Decimal{}, nil
+	}
+
+	coef, ok := new(big.Int).SetString(digits+fracPart, 10)
+	if !ok {
+		return Decimal{}, fmt.Errorf("invalid decimal %q", s)
+	}
+	if strings.HasPrefix(intPart, "-") {
+		coef.Neg(coef)
+	}
+
+	scale := int64(len(fracPart)) - exp
+	if scale < math.MinInt32 || scale > math.MaxInt32 {
+		return Decimal{}, fmt.Errorf("decimal %q is out of range", s)
+	}
+
+	d, err := newBig(coef, int32(scale))
+	if err != nil {
+		return Decimal{}, fmt.Errorf("decimal %q: %w", s, err)
+	}
+	return d, nil
+}
+
+// MustParse is like Parse but panics on error. It simplifies initialization of constants.
+func MustParse(s string) Decimal {
+	d, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+func isDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// NewFromBig returns coef * 10^exp. It fails if result does not fit into Decimal.
+func NewFromBig(coef *big.Int, exp int32) (Decimal, error) {
+	return newBig(new(big.Int).Set(coef), -exp)
+}
+
+// newBig normalizes coef * 10^-scale, coef may be modified.
+func newBig(coef *big.Int, scale int32) (Decimal, error) {
+	ten := big.NewInt(10)
+	if coef.Sign() == 0 {
+		return Decimal{}, nil
+	}
+
+	if scale < 0 {
+		if scale < -maxDigits {
+			return Decimal{}, ErrOverflow
+		}
+		coef.Mul(coef, new(big.Int).Exp(ten, big.NewInt(int64(-scale)), nil))
+		scale = 0
+	}
+
+	rem := new(big.Int)
+	for scale > 0 {
+		q, r := new(big.Int).QuoRem(coef, ten, rem)
+		if r.Sign() != 0 {
+			break
+		}
+		coef, scale = q, scale-1
+	}
+
+	if scale > MaxScale {
+		return Decimal{}, fmt.Errorf("more than %d fractional digits", MaxScale)
+	}
+	if !coef.IsInt64() {
+		return Decimal{}, ErrOverflow
+	}
+
+	return Decimal{coef: coef.Int64(), scale: scale}, nil
+}
+
+// Coef returns coefficient and exponent of d: d = coef * 10^exp.
+func (d Decimal) Coef() (coef int64, exp int32) {
+	return d.coef, -d.scale
+}
+
+// Scale returns number of fractional digits of d.
+func (d Decimal) Scale() int32 {
+	return d.scale
+}
+
+func (d Decimal) Sign() int {
+	switch {
+	case d.coef > 0:
+		return 1
+	case d.coef < 0:
+		return -1
+	default:
+		return 0
+	}
+}
+
+func (d Decimal) IsZero() bool {
+	return d.coef == 0
+}
+
+// Cmp returns -1, 0 or +1 if d is less than, equal to or greater than other.
+func (d Decimal) Cmp(other Decimal) int {
+	a, b := d.aligned(other)
+	return a.Cmp(b)
+}
+
+// Add returns d + other.
+func (d Decimal) Add(other Decimal) (Decimal, error) {
+	a, b := d.aligned(other)
+	return newBig(a.Add(a, b), max(d.scale, other.scale))
+}
+
+// Mul returns exact product of d and other.
+func (d Decimal) Mul(other Decimal) (Decimal, error) {
+	coef := new(big.Int).Mul(big.NewInt(d.coef), big.NewInt(other.coef))
+	return newBig(coef, d.scale+other.scale)
+}
+
+// Div returns d / other rounded half away from zero to scale fractional digits.
+func (d Decimal) Div(other Decimal, scale int32) (Decimal, error) {
+	if other.coef == 0 {
+		return Decimal{}, fmt.Errorf("division by zero")
+	}
+
+	// d / other = (d.coef * 10^(scale - d.scale + other.scale) / other.coef) * 10^-scale
+	num := big.NewInt(d.coef)
+	den := big.NewInt(other.coef)
+	shift := int64(scale) - int64(d.scale) + int64(other.scale)
+	if shift >= 0 {
+		num.Mul(num, pow10(shift))
+	} else {
+		den.Mul(den, pow10(-shift))
+	}
+
+	return newBig(quoRound(num, den), scale)
+}
+
+// Round returns d rounded half away from zero to scale fractional digits.
+func (d Decimal) Round(scale int32) Decimal {
+	if d.scale <= scale {
+		return d
+	}
+
+	coef := quoRound(big.NewInt(d.coef), pow10(int64(d.scale-scale)))
+	res, err := newBig(coef, scale)
+	if err != nil {
+		// rounding can not add digits to int64 coefficient divided by at least 10
+		panic(err)
+	}
+	return res
+}
+
+// Float64 returns the nearest float64 value of d.
+func (d Decimal) Float64() float64 {
+	f, _ := strconv.ParseFloat(d.String(), 64)
+	return f
+}
+
+func (d Decimal) String() string {
+	s := strconv.FormatInt(d.coef, 10)
+	if d.scale == 0 {
+		return s
+	}
+
+	sign := ""
+	if d.coef < 0 {
+		sign, s = "-", s[1:]
+	}
+	if pad := int(d.scale) - len(s) + 1; pad > 0 {
+		s = strings.Repeat("0", pad) + s
+	}
+
+	point := len(s) - int(d.scale)
+	return sign + s[:point] + "." + s[point:]
+}
+
+// MarshalJSON writes d as JSON number without loss of precision.
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalJSON reads d from JSON number or string holding a number.
+func (d *Decimal) UnmarshalJSON(data []byte) (err error) {
+	s := string(data)
+	if s == "null" {
+		return nil
+	}
+	if unquoted, err := strconv.Unquote(s); err == nil {
+		s = unquoted
+	}
+
+	*d, err = Parse(s)
+	return err
+}
+
+func (d Decimal) aligned(other Decimal) (a, b *big.Int) {
+	a, b = big.NewInt(d.coef), big.NewInt(other.coef)
+	if d.scale < other.scale {
+		a.Mul(a, pow10(int64(other.scale-d.scale)))
+	} else {
+		b.Mul(b, pow10(int64(d.scale-other.scale)))
+	}
+	return a, b
+}
+
+func pow10(n int64) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(n), nil)
+}
+
+// quoRound returns num / den rounded half away from zero.
+func quoRound(num, den *big.Int) *big.Int {
+	q, r := new(big.Int).QuoRem(num, den, new(big.Int))
+	if r.Sign() == 0 {
+		return q
+	}
+
+	r.Abs(r).Lsh(r, 1)
+	if r.CmpAbs(den) >= 0 {
+		if num.Sign()*den.Sign() < 0 {
+			q.Sub(q, big.NewInt(1))
+		} else {
+			q.Add(q, big.NewInt(1))
+		}
+	}
+	return q
+}
@@ -0,0 +1,104 @@
+package decimal
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected Decimal
+		str      string
+		err      error
+	}{
+		{input: "12.1", expected: New(121, 1), str: "12.1"},
+		{input: "12.100", expected: New(121, 1), str: "12.1"},
+		{input: "-0.05", expected: New(-5, 2), str: "-0.05"},
+		{input: "+7", expected: New(7, 0), str: "7"},
+		{input: ".5", expected: New(5, 1), str: "0.5"},
+		{input: "1.5e3", expected: New(1500, 0), str: "1500"},
+		{input: "25E-4", expected: New(25, 4), str: "0.0025"},
+		{input: "-0", expected: Decimal{}, str: "0"},
+		{input: "0001200", expected: New(1200, 0), str: "1200"},
+		{input: "", err: fmt.Errorf(`invalid decimal ""`)},
+		{input: "1.2.3", err: fmt.Errorf(`invalid decimal "1.2.3"`)},
+		{input: "--1", err: fmt.Errorf(`invalid decimal "--1"`)},
+		{input: "1e", err: fmt.Errorf(`invalid decimal "1e"`)},
+		{input: "NaN", err: fmt.Errorf(`invalid decimal "NaN"`)},
+		{input: "1e30", err: fmt.Errorf(`decimal "1e30": decimal overflow`)},
+		{input: "1e-30", err: fmt.Errorf(`decimal "1e-30": more than 18 fractional digits`)},
+		{input: "12345678901234567890", err: fmt.Errorf(`decimal "12345678901234567890" is out of range`)},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			d, err := Parse(tc.input)
+			if tc.err != nil {
+				require.Equal(t, tc.err.Error(), err.Error())
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, tc.expected, d)
+			require.Equal(t, tc.str, d.String())
+		})
+	}
+}
+
+func TestArithmetic(t *testing.T) {
+	a := MustParse("12.1")
+	b := MustParse("0.03")
+
+	require.Equal(t, 1, a.Cmp(b))
+	require.Equal(t, -1, b.Cmp(a))
+	require.Equal(t, 0, a.Cmp(MustParse("12.10")))
+
+	sum, err := a.Add(b)
+	require.Nil(t, err)
+	require.Equal(t, MustParse("12.13"), sum)
+
+	product, err := a.Mul(b)
+	require.Nil(t, err)
+	require.Equal(t, MustParse("0.363"), product)
+
+	quotient, err := a.Div(MustParse("3"), 3)
+	require.Nil(t, err)
+	require.Equal(t, MustParse("4.033"), quotient)
+
+	quotient, err = MustParse("-2").Div(MustParse("3"), 2)
+	require.Nil(t, err)
+	require.Equal(t, MustParse("-0.67"), quotient)
+
+	_, err = a.Div(Decimal{}, 2)
+	require.EqualError(t, err, "division by zero")
+
+	_, err = New(1<<62, 0).Mul(New(4, 0))
+	require.ErrorIs(t, err, ErrOverflow)
+
+	require.Equal(t, MustParse("2.35"), MustParse("2.345").Round(2))
+	require.Equal(t, MustParse("-2.35"), MustParse("-2.345").Round(2))
+	require.Equal(t, MustParse("2.3"), MustParse("2.3").Round(2))
+	require.Equal(t, MustParse("3"), MustParse("2.5").Round(0))
+}
+
+func TestJSON(t *testing.T) {
+	var body struct {
+		Load Decimal `json:"load"`
+	}
+
+	err := json.Unmarshal([]byte(`{"load": 12.1}`), &body)
+	require.Nil(t, err)
+	require.Equal(t, New(121, 1), body.Load)
+
+	err = json.Unmarshal([]byte(`{"load": "0.125"}`), &body)
+	require.Nil(t, err)
+	require.Equal(t, New(125, 3), body.Load)
+
+	err = json.Unmarshal([]byte(`{"load": "abc"}`), &body)
+	require.EqualError(t, err, `invalid decimal "abc"`)
+
+	out, err := json.Marshal(map[string]Decimal{"load": MustParse("12.100000")})
+	require.Nil(t, err)
+	require.Equal(t, `{"load":12.1}`, string(out))
+}
@@ -0,0 +1,27 @@
+package dto
+
+type CargoTypeRequestBody struct {
+	Code        string   `json:"code"`
+	DisplayName string   `json:"display_name"`
+	Unit        string   `json:"unit"`
+	Density     float64  `json:"density"`
+	HazardClass string   `json:"hazard_class"`
+	Aliases     []string `json:"aliases"`
+}
+
+type UpdateCargoTypeRequestBody struct {
+	DisplayName *string   `json:"display_name"`
+	Unit        *string   `json:"unit"`
+	Density     *float64  `json:"density"`
+	HazardClass *string   `json:"hazard_class"`
+	Aliases     *[]string `json:"aliases"`
+}
+
+type CargoTypeResponseBody struct {
+	Code        string   `json:"code"`
+	DisplayName string   `json:"display_name"`
+	Unit        string   `json:"unit"`
+	Density     float64  `json:"density,omitempty"`
+	HazardClass string   `json:"hazard_class,omitempty"`
+	Aliases     []string `json:"aliases"`
+}
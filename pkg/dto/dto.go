@@ -0,0 +1,139 @@
+package dto
+
+import (
+	"task/pkg/decimal"
+	"time"
+)
+
+const (
+	DefaultListLimit = 20
+	MaxListLimit     = 100
+	MaxBatchSize     = 10000
+	// MaxSearchQueryLength limits length of route search query in bytes
+	MaxSearchQueryLength = 256
+)
+
+// RegisterRouteRequestBody describes route to register. Load is measured in Unit,
+// or in default unit of the cargo type if Unit is empty.
+type RegisterRouteRequestBody struct {
+	RouteID   int             `json:"route_id"`
+	RouteName string          `json:"route_name"`
+	Load      decimal.Decimal `json:"load"`
+	Unit      string          `json:"unit"`
+	CargoType string          `json:"cargo_type"`
+	Waypoints []WaypointBody  `json:"waypoints"`
+}
+
+// RegisterBatchRequestBody holds routes to register. If Atomic is set, routes are registered
+// in one transaction and any failure rejects the whole batch, otherwise each route is registered
+// on its own.
+type RegisterBatchRequestBody struct {
+	Atomic bool                       `json:"atomic"`
+	Routes []RegisterRouteRequestBody `json:"routes"`
+}
+
+type RegisterBatchItemResponseBody struct {
+	Index    int    `json:"index"`
+	RouteID  int    `json:"route_id,omitempty"`
+	Reissued bool   `json:"reissued"`
+	Error    string `json:"error,omitempty"`
+}
+
+type ImportReportResponseBody struct {
+	Total    int                       `json:"total"`
+	Imported int                       `json:"imported"`
+	Reissued int                       `json:"reissued"`
+	Rejected []RejectedRowResponseBody `json:"rejected"`
+}
+
+type RejectedRowResponseBody struct {
+	Line   int    `json:"line"`
+	Reason string `json:"reason"`
+}
+
+// UpdateRouteRequestBody holds fields of route to change. Unit applies to Load
+// and is ignored without it.
+type UpdateRouteRequestBody struct {
+	RouteName *string          `json:"route_name"`
+	Load      *decimal.Decimal `json:"load"`
+	Unit      *string          `json:"unit"`
+	CargoType *string          `json:"cargo_type"`
+	Waypoints *[]WaypointBody  `json:"waypoints"`
+}
+
+type DeleteRoutesRequestBody struct {
+	RouteIDs []int `json:"route_ids"`
+}
+
+type ListRoutesRequest struct {
+	Cursor     string
+	Limit      int
+	CargoType  string
+	IsActual   *bool
+	MinLoad    *decimal.Decimal
+	MaxLoad    *decimal.Decimal
+	NamePrefix string
+	VehicleID  *int
+}
+
+type SearchRoutesRequest struct {
+	Query string
+	Limit int
+}
+
+type RouteResponseBody struct {
+	RouteID   int             `json:"route_id"`
+	RouteName string          `json:"route_name"`
+	Load      decimal.Decimal `json:"load"`
+	Unit      string          `json:"unit"`
+	CargoType string          `json:"cargo_type"`
+	IsActual  bool            `json:"is_actual"`
+	Waypoints []WaypointBody  `json:"waypoints"`
+	Polyline  string          `json:"polyline"`
+	DistanceM float64         `json:"distance_m"`
+	DurationS int64           `json:"duration_s"`
+	VehicleID *int            `json:"vehicle_id,omitempty"`
+}
+
+type ListRoutesResponseBody struct {
+	Routes     []RouteResponseBody `json:"routes"`
+	NextCursor string              `json:"next_cursor,omitempty"`
+}
+
+type RouteStatsResponseBody struct {
+	CargoType string          `json:"cargo_type"`
+	IsActual  bool            `json:"is_actual"`
+	Count     int             `json:"count"`
+	TotalLoad decimal.Decimal `json:"total_load"`
+	AvgLoad   decimal.Decimal `json:"avg_load"`
+	MinLoad   decimal.Decimal `json:"min_load"`
+	MaxLoad   decimal.Decimal `json:"max_load"`
+	Unit      string          `json:"unit"`
+}
+
+type RouteVersionResponseBody struct {
+	VersionID    int64           `json:"version_id"`
+	RouteID      int             `json:"route_id"`
+	RouteName    string          `json:"route_name"`
+	Load         decimal.Decimal `json:"load"`
+	CargoType    string          `json:"cargo_type"`
+	Waypoints    []WaypointBody  `json:"waypoints"`
+	CreatedAt    time.Time       `json:"created_at"`
+	SupersededBy *int64          `json:"superseded_by,omitempty"`
+	SupersededAt *time.Time      `json:"superseded_at,omitempty"`
+}
+
+type DeleteJobResponseBody struct {
+	JobID     int64                       `json:"job_id"`
+	Status    string                      `json:"status"`
+	Attempts  int                         `json:"attempts"`
+	Error     string                      `json:"error,omitempty"`
+	Items     []DeleteJobItemResponseBody `json:"items"`
+	CreatedAt time.Time                   `json:"created_at"`
+	UpdatedAt time.Time                   `json:"updated_at"`
+}
+
+type DeleteJobItemResponseBody struct {
+	RouteID int    `json:"route_id"`
+	Status  string `json:"status"`
+}
@@ -0,0 +1,8 @@
+package dto
+
+// Formats of import and export files.
+const (
+	FormatCSV     = "csv"
+	FormatJSONL   = "jsonl"
+	FormatGeoJSON = "geojson"
+)
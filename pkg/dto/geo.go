@@ -0,0 +1,39 @@
+package dto
+
+const (
+	MinWaypoints = 2
+	// MaxNearRadius is the largest radius in meters of near query
+	MaxNearRadius = 500_000
+)
+
+type WaypointBody struct {
+	Lat      float64 `json:"lat"`
+	Lon      float64 `json:"lon"`
+	StopName string  `json:"stop_name,omitempty"`
+	StopType string  `json:"stop_type,omitempty"`
+}
+
+type NearRequest struct {
+	Lat     float64
+	Lon     float64
+	RadiusM float64
+}
+
+// WithinRequest holds bounding box of within query.
+type WithinRequest struct {
+	MinLon float64
+	MinLat float64
+	MaxLon float64
+	MaxLat float64
+}
+
+type GeoJSONFeature struct {
+	Type       string            `json:"type"`
+	Geometry   *GeoJSONGeometry  `json:"geometry"`
+	Properties RouteResponseBody `json:"properties"`
+}
+
+type GeoJSONGeometry struct {
+	Type        string       `json:"type"`
+	Coordinates [][2]float64 `json:"coordinates"`
+}
@@ -0,0 +1,66 @@
+package dto
+
+import (
+	"time"
+)
+
+const (
+	// MaxTripRangeDays is the largest number of days trips are expanded for at once
+	MaxTripRangeDays = 366
+	DefaultTimezone  = "UTC"
+)
+
+// TimeWindowBody holds times of day in "HH:MM" form. Hours may exceed 23 for times
+// on the following days, e.g. "26:30" is 02:30 of the next day.
+type TimeWindowBody struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// ScheduleRequestBody describes when route runs. Windows are given in Timezone, UTC by default.
+// ValidFrom and ValidUntil are optional dates in "YYYY-MM-DD" form.
+type ScheduleRequestBody struct {
+	Departure  TimeWindowBody `json:"departure"`
+	Arrival    TimeWindowBody `json:"arrival"`
+	Recurrence string         `json:"recurrence"`
+	Timezone   string         `json:"timezone"`
+	ValidFrom  string         `json:"valid_from"`
+	ValidUntil string         `json:"valid_until"`
+}
+
+type ScheduleResponseBody struct {
+	RouteID    int            `json:"route_id"`
+	Departure  TimeWindowBody `json:"departure"`
+	Arrival    TimeWindowBody `json:"arrival"`
+	Recurrence string         `json:"recurrence"`
+	Timezone   string         `json:"timezone"`
+	ValidFrom  string         `json:"valid_from,omitempty"`
+	ValidUntil string         `json:"valid_until,omitempty"`
+}
+
+// TripsRequest selects trips departing on dates From to To inclusive, of one route if RouteID is set.
+// Trips are returned by pages of Limit, DefaultListLimit by default; Cursor is NextCursor of the previous page.
+type TripsRequest struct {
+	From    string
+	To      string
+	RouteID *int
+	Cursor  string
+	Limit   int
+}
+
+type IntervalBody struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+}
+
+type TripResponseBody struct {
+	RouteID   int          `json:"route_id"`
+	Date      string       `json:"date"`
+	Departure IntervalBody `json:"departure"`
+	Arrival   IntervalBody `json:"arrival"`
+}
+
+type TripsResponseBody struct {
+	Trips      []TripResponseBody `json:"trips"`
+	NextCursor string             `json:"next_cursor,omitempty"`
+}
@@ -0,0 +1,34 @@
+package dto
+
+import (
+	"task/pkg/decimal"
+)
+
+// VehicleRequestBody describes vehicle to create. Capacity is measured in kilograms,
+// empty status means available vehicle.
+type VehicleRequestBody struct {
+	Name       string          `json:"name"`
+	Capacity   decimal.Decimal `json:"capacity"`
+	CargoTypes []string        `json:"cargo_types"`
+	Status     string          `json:"status"`
+}
+
+type UpdateVehicleRequestBody struct {
+	Name       *string          `json:"name"`
+	Capacity   *decimal.Decimal `json:"capacity"`
+	CargoTypes *[]string        `json:"cargo_types"`
+	Status     *string          `json:"status"`
+}
+
+type VehicleResponseBody struct {
+	VehicleID  int             `json:"vehicle_id"`
+	Name       string          `json:"name"`
+	Capacity   decimal.Decimal `json:"capacity"`
+	Unit       string          `json:"unit"`
+	CargoTypes []string        `json:"cargo_types"`
+	Status     string          `json:"status"`
+}
+
+type AssignVehicleRequestBody struct {
+	VehicleID int `json:"vehicle_id"`
+}